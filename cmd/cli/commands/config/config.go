@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
 	"github.com/rivo/tview"
@@ -16,23 +17,64 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 		Name:      opts.Name(),
 		Usage:     "Configure Contributoor settings",
 		UsageText: "contributoor config",
+		Flags:     []cli.Flag{options.InstanceFlag()},
 		Action: func(c *cli.Context) error {
 			log := opts.Logger()
 
-			sidecarCfg, err := sidecar.NewConfigService(log, c.String("config-path"))
+			configPath, err := opts.ConfigPath(c)
 			if err != nil {
 				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			return configureContributoor(c, log, sidecarCfg)
+			sidecarCfg, err := sidecar.NewConfigService(log, configPath)
+			if err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+			}
+
+			return configureContributoor(c, log, sidecarCfg, opts.InstallerConfig())
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:      "render-compose",
+				Usage:     "Print the compose project rendered for the docker run method",
+				UsageText: "contributoor config render-compose",
+				Flags:     []cli.Flag{options.InstanceFlag()},
+				Action: func(c *cli.Context) error {
+					configPath, err := opts.ConfigPath(c)
+					if err != nil {
+						return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+					}
+
+					sidecarCfg, err := sidecar.NewConfigService(opts.Logger(), configPath)
+					if err != nil {
+						return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+					}
+
+					return renderCompose(c, sidecarCfg, opts.InstallerConfig())
+				},
+			},
 		},
 	})
 }
 
-func configureContributoor(c *cli.Context, log *logrus.Logger, sidecarCfg sidecar.ConfigManager) error {
+// renderCompose prints the compose project that the docker run method would start.
+func renderCompose(c *cli.Context, sidecarCfg sidecar.ConfigManager, installerCfg *installer.Config) error {
+	data, err := sidecar.RenderCompose(sidecarCfg, installerCfg)
+	if err != nil {
+		return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+	}
+
+	if _, err := c.App.Writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write compose file: %w", err)
+	}
+
+	return nil
+}
+
+func configureContributoor(c *cli.Context, log *logrus.Logger, sidecarCfg sidecar.ConfigManager, installerCfg *installer.Config) error {
 	var (
 		app     = tview.NewApplication()
-		display = NewConfigDisplay(log, app, sidecarCfg)
+		display = NewConfigDisplay(log, app, sidecarCfg, installerCfg)
 	)
 
 	if err := display.Run(); err != nil {
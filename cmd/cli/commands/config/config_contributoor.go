@@ -1,9 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"runtime"
-	"strings"
 
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
 	"github.com/ethpandaops/contributoor/pkg/config/v1"
 	"github.com/gdamore/tcell/v2"
@@ -11,13 +13,6 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// Available run modes.
-var runModes = []config.RunMethod{
-	config.RunMethod_RUN_METHOD_DOCKER,
-	config.RunMethod_RUN_METHOD_SYSTEMD,
-	config.RunMethod_RUN_METHOD_BINARY,
-}
-
 // ContributoorSettingsPage is a page that allows the user to configure core contributoor settings.
 type ContributoorSettingsPage struct {
 	display                 *ConfigDisplay
@@ -91,31 +86,16 @@ func (p *ContributoorSettingsPage) initPage() {
 		}
 	}
 
-	// Find current run mode index
-	currentRunMode := p.display.sidecarCfg.Get().RunMethod
-	currentRunModeIndex := 0 // Default to docker
-
-	for i, mode := range runModes {
-		if mode == currentRunMode {
-			currentRunModeIndex = i
-
-			break
-		}
-	}
-
 	// Get current attestation opt-in status
 	cfg := p.display.sidecarCfg.Get()
 	p.attestationOptInEnabled = cfg.AttestationSubnetCheck != nil && cfg.AttestationSubnetCheck.Enabled
 
-	// Create display labels that show launchd on macOS
-	runModeLabels := make([]string, len(runModes))
+	// The run mode is shown with launchd on macOS.
+	runMode := p.currentRunMode()
 
-	for i, mode := range runModes {
-		if mode == config.RunMethod_RUN_METHOD_SYSTEMD {
-			runModeLabels[i] = getServiceManagerLabel()
-		} else {
-			runModeLabels[i] = strings.ToLower(mode.DisplayName())
-		}
+	runModeLabel := runMode.Name()
+	if runMode.Method == config.RunMethod_RUN_METHOD_SYSTEMD && runMode.Scope != installer.SystemdScopeUser {
+		runModeLabel = getServiceManagerLabel()
 	}
 
 	// Add our form fields.
@@ -123,16 +103,23 @@ func (p *ContributoorSettingsPage) initPage() {
 		p.description.SetText("Set the logging verbosity level. Debug and Trace provide more detailed output.")
 	})
 
-	form.AddDropDown("Run Mode", runModeLabels, currentRunModeIndex, func(text string, index int) {
-		if text == strings.ToLower(config.RunMethod_RUN_METHOD_DOCKER.DisplayName()) {
-			p.description.SetText("Run using Docker containers (recommended)")
-		} else if text == getServiceManagerLabel() {
-			p.description.SetText(getServiceManagerDescription())
-		} else {
-			p.description.SetText("Run directly as a binary on your system")
-		}
+	// The run mode is read only, as only rewriting it would leave the current service
+	// running alongside the new one. switch-run-method moves the service over.
+	runModeView := tview.NewTextView().
+		SetLabel("Run Mode").
+		SetSize(1, 0).
+		SetText(runModeLabel)
+
+	runModeView.SetFocusFunc(func() {
+		p.description.SetText(fmt.Sprintf(
+			"%s.\n\nTo change it, exit and run 'contributoor switch-run-method <docker|systemd|systemd-user|binary>', "+
+				"which stops the current service so it isn't left running alongside the new one.",
+			runModeDescription(runMode),
+		))
 	})
 
+	form.AddFormItem(runModeView)
+
 	// Add attestation opt-in checkbox
 	form.AddCheckbox("Enable attestation data contribution", p.attestationOptInEnabled, func(checked bool) {
 		p.attestationOptInEnabled = checked
@@ -235,14 +222,10 @@ func (p *ContributoorSettingsPage) initPage() {
 
 func validateAndUpdateContributoor(p *ContributoorSettingsPage) {
 	logLevel, _ := p.form.GetFormItem(0).(*tview.DropDown)
-	runMode, _ := p.form.GetFormItem(1).(*tview.DropDown)
-
 	_, logLevelText := logLevel.GetCurrentOption()
-	runModeIndex, _ := runMode.GetCurrentOption()
 
 	if err := p.display.sidecarCfg.Update(func(cfg *config.Config) {
 		cfg.LogLevel = logLevelText
-		cfg.RunMethod = runModes[runModeIndex]
 
 		// Update attestation preference
 		if p.attestationOptInEnabled {
@@ -266,6 +249,17 @@ func validateAndUpdateContributoor(p *ContributoorSettingsPage) {
 	p.display.setPage(p.display.homePage)
 }
 
+// currentRunMode returns the configured run mode.
+func (p *ContributoorSettingsPage) currentRunMode() sidecar.RunMode {
+	mode := sidecar.RunMode{Method: p.display.sidecarCfg.Get().RunMethod}
+
+	if mode.Method == config.RunMethod_RUN_METHOD_SYSTEMD && p.display.installerCfg != nil {
+		mode.Scope = p.display.installerCfg.Systemd.Scope
+	}
+
+	return mode
+}
+
 func (p *ContributoorSettingsPage) openErrorModal(err error) {
 	p.display.app.SetRoot(tui.CreateErrorModal(
 		p.display.app,
@@ -277,6 +271,21 @@ func (p *ContributoorSettingsPage) openErrorModal(err error) {
 	), true).EnableMouse(true)
 }
 
+// runModeDescription describes how contributoor runs with mode.
+func runModeDescription(mode sidecar.RunMode) string {
+	switch {
+	case mode.Method == config.RunMethod_RUN_METHOD_DOCKER:
+		return "Runs using Docker containers (recommended)"
+	case mode.Name() == sidecar.RunMethodSystemdUser:
+		return "Runs using a systemd user unit, managed with systemctl --user, so sudo isn't needed. " +
+			"Lingering must be enabled for it to keep running once you log out"
+	case mode.Method == config.RunMethod_RUN_METHOD_SYSTEMD:
+		return getServiceManagerDescription()
+	default:
+		return "Runs directly as a binary on your system"
+	}
+}
+
 // getServiceManagerLabel returns the appropriate label based on platform.
 func getServiceManagerLabel() string {
 	if runtime.GOOS == "darwin" {
@@ -289,8 +298,8 @@ func getServiceManagerLabel() string {
 // getServiceManagerDescription returns the appropriate description based on platform.
 func getServiceManagerDescription() string {
 	if runtime.GOOS == "darwin" {
-		return "Run using macOS launchd service manager"
+		return "Runs using the macOS launchd service manager"
 	}
 
-	return "Run using Linux systemd service manager"
+	return "Runs using the Linux systemd service manager"
 }
@@ -1,11 +1,12 @@
 package config
 
 import (
-	"os/exec"
+	"context"
 	"slices"
 	"sort"
 	"strings"
 
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
 	"github.com/ethpandaops/contributoor-installer/internal/validate"
 	"github.com/ethpandaops/contributoor/pkg/config/v1"
@@ -80,12 +81,16 @@ func (p *NetworkConfigPage) initPage() {
 			existingCommonNetworks = make([]string, 0, len(commonNetworks))
 		)
 
-		// Get list of existing Docker networks.
-		cmd := exec.Command("docker", "network", "ls", "--format", "{{.Name}}")
+		// Get list of existing networks from the container runtime, which may be podman.
+		var existing []string
 
-		output, err := cmd.Output()
+		runtime, err := sidecar.NewContainerRuntime(p.display.installerCfg.ContainerRuntime)
 		if err == nil {
-			for network := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
+			existing, err = runtime.NetworkList(context.Background())
+		}
+
+		if err == nil {
+			for _, network := range existing {
 				if network != "" && !strings.Contains(network, "contributoor") && network != "none" {
 					if contains(commonNetworks, network) {
 						existingCommonNetworks = append(existingCommonNetworks, network)
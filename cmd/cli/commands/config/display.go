@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
 	"github.com/gdamore/tcell/v2"
@@ -17,6 +18,7 @@ type ConfigDisplay struct {
 	frame                  *tview.Frame
 	log                    *logrus.Logger
 	sidecarCfg             sidecar.ConfigManager
+	installerCfg           *installer.Config
 	homePage               *tui.Page
 	categoryList           *tview.List
 	content                tview.Primitive
@@ -30,12 +32,18 @@ type ConfigDisplay struct {
 }
 
 // NewConfigDisplay creates a new Configtui.
-func NewConfigDisplay(log *logrus.Logger, app *tview.Application, sidecarCfg sidecar.ConfigManager) *ConfigDisplay {
+func NewConfigDisplay(
+	log *logrus.Logger,
+	app *tview.Application,
+	sidecarCfg sidecar.ConfigManager,
+	installerCfg *installer.Config,
+) *ConfigDisplay {
 	display := &ConfigDisplay{
-		app:        app,
-		pages:      tview.NewPages(),
-		log:        log,
-		sidecarCfg: sidecarCfg,
+		app:          app,
+		pages:        tview.NewPages(),
+		log:          log,
+		sidecarCfg:   sidecarCfg,
+		installerCfg: installerCfg,
 	}
 
 	display.homePage = tui.NewPage(nil, "config-home", "Categories", "", nil)
@@ -0,0 +1,176 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os/user"
+
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
+	"github.com/ethpandaops/contributoor-installer/internal/doctor"
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/service"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      opts.Name(),
+		Aliases:   opts.Aliases(),
+		Usage:     "Diagnose common problems with your Contributoor installation",
+		UsageText: "contributoor doctor [options]",
+		Flags:     []cli.Flag{options.InstanceFlag()},
+		Action: func(c *cli.Context) error {
+			var (
+				log          = opts.Logger()
+				installerCfg = opts.InstallerConfig()
+			)
+
+			configPath, err := opts.ConfigPath(c)
+			if err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+			}
+
+			sidecarCfg, err := sidecar.NewConfigService(log, configPath)
+			if err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+			}
+
+			// Unlike other commands, a sidecar failing to initialise is something we want
+			// to diagnose rather than bail on, so the error is reported as a check.
+			runner, runnerErr := sidecar.ResolveRunner(log, sidecarCfg, installerCfg)
+
+			githubService, err := service.NewGitHubService(log, installerCfg)
+			if err != nil {
+				return fmt.Errorf("error creating github service: %w", err)
+			}
+
+			findOrphans := func(ctx context.Context) []sidecar.Orphan {
+				return sidecar.FindOrphans(ctx, log, sidecarCfg, installerCfg)
+			}
+
+			return runDoctor(c, log, sidecarCfg, installerCfg, runner, githubService, runnerErr, findOrphans)
+		},
+	})
+}
+
+func runDoctor(
+	c *cli.Context,
+	log *logrus.Logger,
+	sidecarCfg sidecar.ConfigManager,
+	installerCfg *installer.Config,
+	runner sidecar.SidecarRunner,
+	github service.GitHubService,
+	runnerErr error,
+	findOrphans func(ctx context.Context) []sidecar.Orphan,
+) error {
+	checks := buildChecks(log, sidecarCfg, installerCfg, runner, github, runnerErr, findOrphans)
+	results := doctor.Run(c.Context, checks)
+
+	printResults(results)
+
+	if doctor.HasFailures(results) {
+		return fmt.Errorf("%sone or more checks failed%s", tui.TerminalColorRed, tui.TerminalColorReset)
+	}
+
+	return nil
+}
+
+// buildChecks returns the checks relevant to the configured run method.
+func buildChecks(
+	log *logrus.Logger,
+	sidecarCfg sidecar.ConfigManager,
+	installerCfg *installer.Config,
+	runner sidecar.SidecarRunner,
+	github service.GitHubService,
+	runnerErr error,
+	findOrphans func(ctx context.Context) []sidecar.Orphan,
+) []doctor.Check {
+	var (
+		checks []doctor.Check
+		cfg    = sidecarCfg.Get()
+	)
+
+	switch cfg.RunMethod {
+	case config.RunMethod_RUN_METHOD_DOCKER:
+		checks = append(checks, doctor.ContainerRuntimeCheck(installerCfg.ContainerRuntime), doctor.ComposeCheck(sidecarCfg, installerCfg))
+	case config.RunMethod_RUN_METHOD_SYSTEMD:
+		if systemd, ok := runner.(sidecar.SystemdSidecar); ok {
+			checks = append(checks, doctor.SystemdUnitCheck(systemd))
+		}
+
+		// A user unit only keeps running once the user logs out if they linger.
+		if installerCfg.Systemd.Scope == installer.SystemdScopeUser {
+			if current, err := user.Current(); err == nil {
+				checks = append(checks, doctor.LingerCheck(current.Username, sidecar.LingerEnabled))
+			}
+		}
+	case config.RunMethod_RUN_METHOD_BINARY:
+		checks = append(checks, doctor.PidFileCheck(cfg))
+	default:
+		runnerErr = fmt.Errorf("invalid sidecar run method: %s", cfg.RunMethod)
+	}
+
+	checks = append([]doctor.Check{doctor.RunMethodCheck(cfg.RunMethod, runnerErr)}, checks...)
+	checks = append(checks,
+		doctor.ConfigCheck(cfg),
+		doctor.BeaconCheck(cfg, func(address string) service.BeaconService {
+			return service.NewBeaconService(log, address)
+		}),
+		doctor.OrphanCheck(findOrphans),
+	)
+
+	// The version and log checks need a working runner.
+	if runner != nil && runnerErr == nil {
+		checks = append(checks, doctor.VersionCheck(cfg, runner, github), doctor.LogCheck(runner, cfg))
+	}
+
+	return checks
+}
+
+// printResults prints the results as a table, with remediation hints below any
+// warnings or failures.
+func printResults(results []doctor.Result) {
+	fmt.Printf("%sContributoor Doctor%s\n", tui.TerminalColorLightBlue, tui.TerminalColorReset)
+	fmt.Printf("%-20s %-6s %s\n", "CHECK", "STATUS", "DETAILS")
+
+	counts := make(map[doctor.Status]int)
+
+	for _, result := range results {
+		counts[result.Status]++
+
+		fmt.Printf(
+			"%-20s %s%-6s%s %s\n",
+			result.Name,
+			statusColor(result.Status),
+			result.Status,
+			tui.TerminalColorReset,
+			result.Message,
+		)
+
+		if result.Status != doctor.StatusPass && result.Hint != "" {
+			fmt.Printf("%-20s %-6s ↳ %s\n", "", "", result.Hint)
+		}
+	}
+
+	fmt.Printf(
+		"\n%d passed, %d warnings, %d failed\n",
+		counts[doctor.StatusPass],
+		counts[doctor.StatusWarn],
+		counts[doctor.StatusFail],
+	)
+}
+
+func statusColor(status doctor.Status) string {
+	switch status {
+	case doctor.StatusPass:
+		return tui.TerminalColorGreen
+	case doctor.StatusWarn:
+		return tui.TerminalColorYellow
+	default:
+		return tui.TerminalColorRed
+	}
+}
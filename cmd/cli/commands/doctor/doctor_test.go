@@ -0,0 +1,167 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	servicemock "github.com/ethpandaops/contributoor-installer/internal/service/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/test"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRunDoctor(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           func(dir string) *config.Config
+		runnerErr     error
+		setupMocks    func(*mock.MockBinarySidecar, *servicemock.MockGitHubService)
+		expectedError string
+	}{
+		{
+			name: "binary - all checks pass",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{
+					Version:               "1.0.0",
+					RunMethod:             config.RunMethod_RUN_METHOD_BINARY,
+					ContributoorDirectory: dir,
+					BeaconNodeAddress:     "http://beacon:5052",
+					OutputServer:          &config.OutputServer{Address: "https://xatu.example.com"},
+				}
+			},
+			setupMocks: func(b *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {
+				b.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				b.EXPECT().Logs(gomock.Any(), gomock.Any()).Return(nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("1.0.0", nil)
+			},
+		},
+		{
+			name: "binary - warnings do not fail",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{
+					Version:               "latest",
+					RunMethod:             config.RunMethod_RUN_METHOD_BINARY,
+					ContributoorDirectory: dir,
+					BeaconNodeAddress:     "http://beacon:5052",
+					OutputServer:          &config.OutputServer{Address: "https://xatu.example.com"},
+				}
+			},
+			setupMocks: func(b *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {
+				b.EXPECT().Logs(gomock.Any(), gomock.Any()).Return(nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("", errors.New("rate limited"))
+			},
+		},
+		{
+			name: "binary - known error in logs fails",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{
+					Version:               "1.0.0",
+					RunMethod:             config.RunMethod_RUN_METHOD_BINARY,
+					ContributoorDirectory: dir,
+					BeaconNodeAddress:     "http://beacon:5052",
+					OutputServer:          &config.OutputServer{Address: "https://xatu.example.com"},
+				}
+			},
+			setupMocks: func(b *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {
+				b.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				b.EXPECT().Logs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts sidecar.LogOptions) error {
+					_, err := io.WriteString(opts.Output, `level=error msg="failed to send events" error="rpc error: code = Unauthenticated"`+"\n")
+
+					return err
+				})
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("1.0.0", nil)
+			},
+			expectedError: "one or more checks failed",
+		},
+		{
+			name: "binary - invalid config fails",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{
+					Version:               "1.0.0",
+					RunMethod:             config.RunMethod_RUN_METHOD_BINARY,
+					ContributoorDirectory: dir,
+					BeaconNodeAddress:     "beacon:5052",
+					OutputServer:          &config.OutputServer{Address: "https://xatu.example.com"},
+				}
+			},
+			setupMocks: func(b *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {
+				b.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				b.EXPECT().Logs(gomock.Any(), gomock.Any()).Return(nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("1.0.0", nil)
+			},
+			expectedError: "one or more checks failed",
+		},
+		{
+			name: "binary - sidecar failed to initialise",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{
+					Version:               "1.0.0",
+					RunMethod:             config.RunMethod_RUN_METHOD_BINARY,
+					ContributoorDirectory: dir,
+					BeaconNodeAddress:     "http://beacon:5052",
+					OutputServer:          &config.OutputServer{Address: "https://xatu.example.com"},
+				}
+			},
+			runnerErr:     errors.New("failed to open stdout log file"),
+			setupMocks:    func(b *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {},
+			expectedError: "one or more checks failed",
+		},
+		{
+			name: "invalid sidecar run method",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{
+					RunMethod:         config.RunMethod_RUN_METHOD_UNSPECIFIED,
+					BeaconNodeAddress: "http://beacon:5052",
+					OutputServer:      &config.OutputServer{Address: "https://xatu.example.com"},
+				}
+			},
+			setupMocks:    func(b *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {},
+			expectedError: "one or more checks failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := test.SuppressOutput(t)
+			defer cleanup()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockConfig := mock.NewMockConfigManager(ctrl)
+			mockConfig.EXPECT().Get().Return(tt.cfg(t.TempDir())).AnyTimes()
+
+			mockBinary := mock.NewMockBinarySidecar(ctrl)
+			mockGitHub := servicemock.NewMockGitHubService(ctrl)
+
+			tt.setupMocks(mockBinary, mockGitHub)
+
+			err := runDoctor(
+				cli.NewContext(cli.NewApp(), nil, nil),
+				logrus.New(),
+				mockConfig,
+				installer.NewConfig(),
+				mockBinary,
+				mockGitHub,
+				tt.runnerErr,
+				func(context.Context) []sidecar.Orphan { return nil },
+			)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package install
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/ethpandaops/contributoor-installer/internal/validate"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/mitchellh/go-homedir"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Answers holds every value the install wizard would otherwise prompt for. They can be
+// provided via a YAML answers file, flags, or both (flags take precedence).
+type Answers struct {
+	Version            string              `yaml:"version"`
+	RunMethod          string              `yaml:"runMethod"`
+	BeaconNodeAddress  string              `yaml:"beaconNodeAddress"`
+	DockerNetwork      string              `yaml:"dockerNetwork"`
+	OutputServer       OutputServerAnswers `yaml:"outputServer"`
+	AttestationOptIn   *bool               `yaml:"attestationOptIn"`
+	MetricsAddress     *string             `yaml:"metricsAddress"`
+	HealthCheckAddress *string             `yaml:"healthCheckAddress"`
+}
+
+// OutputServerAnswers holds the output server values for a non-interactive install.
+type OutputServerAnswers struct {
+	Address  string `yaml:"address"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	TLS      *bool  `yaml:"tls"`
+}
+
+// loadAnswersFile reads install answers from a YAML file.
+func loadAnswersFile(path string) (*Answers, error) {
+	expanded, err := homedir.Expand(path)
+	if err != nil {
+		return nil, fmt.Errorf("error expanding answers file path [%s]: %w", path, err)
+	}
+
+	data, err := os.ReadFile(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answers file: %w", err)
+	}
+
+	answers := &Answers{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(answers); err != nil {
+		return nil, fmt.Errorf("failed to parse answers file [%s]: %w", expanded, err)
+	}
+
+	return answers, nil
+}
+
+// collectAnswers builds the install answers from the answers file (if any), with any
+// explicitly set flags taking precedence.
+func collectAnswers(c *cli.Context) (*Answers, error) {
+	answers := &Answers{}
+
+	if path := c.String("answers-file"); path != "" {
+		fileAnswers, err := loadAnswersFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		answers = fileAnswers
+	}
+
+	if c.IsSet("version") {
+		answers.Version = c.String("version")
+	}
+
+	if c.IsSet("run-method") {
+		answers.RunMethod = c.String("run-method")
+	}
+
+	if c.IsSet("beacon-node-address") {
+		answers.BeaconNodeAddress = c.String("beacon-node-address")
+	}
+
+	if c.IsSet("docker-network") {
+		answers.DockerNetwork = c.String("docker-network")
+	}
+
+	if c.IsSet("output-server-address") {
+		answers.OutputServer.Address = c.String("output-server-address")
+	}
+
+	if c.IsSet("output-server-username") {
+		answers.OutputServer.Username = c.String("output-server-username")
+	}
+
+	if c.IsSet("output-server-password") {
+		answers.OutputServer.Password = c.String("output-server-password")
+	}
+
+	if c.IsSet("output-server-tls") {
+		tls := c.Bool("output-server-tls")
+		answers.OutputServer.TLS = &tls
+	}
+
+	if c.IsSet("attestation-opt-in") {
+		optIn := c.Bool("attestation-opt-in")
+		answers.AttestationOptIn = &optIn
+	}
+
+	if c.IsSet("metrics-address") {
+		metrics := c.String("metrics-address")
+		answers.MetricsAddress = &metrics
+	}
+
+	if c.IsSet("health-check-address") {
+		health := c.String("health-check-address")
+		answers.HealthCheckAddress = &health
+	}
+
+	return answers, nil
+}
+
+// applySystemdScope records the systemd scope selected by the run method answer, if
+// any, in the installer config, eg: a user unit for systemd-user.
+func applySystemdScope(sidecarCfg sidecar.ConfigManager, installerCfg *installer.Config, answers *Answers) error {
+	scope := sidecar.ParseSystemdScope(answers.RunMethod)
+	if scope == "" || scope == installerCfg.Systemd.Scope {
+		return nil
+	}
+
+	path := filepath.Join(filepath.Dir(sidecarCfg.GetConfigPath()), installer.ConfigFilename)
+	if err := installer.SaveSystemdScope(path, scope); err != nil {
+		return err
+	}
+
+	installerCfg.Systemd.Scope = scope
+
+	return nil
+}
+
+// applyAnswers validates the answers using the same checks as the install wizard, and
+// writes them to the sidecar config in a single update. Any value not provided falls
+// back to whatever is already in the config.
+func applyAnswers(sidecarCfg sidecar.ConfigManager, answers *Answers) error {
+	var (
+		cfg                = sidecarCfg.Get()
+		runMethod          = cfg.RunMethod
+		version            = cfg.Version
+		beaconAddress      = cfg.BeaconNodeAddress
+		dockerNetwork      = cfg.DockerNetwork
+		metricsAddress     = cfg.MetricsAddress
+		healthCheckAddress = cfg.HealthCheckAddress
+		attestationOptIn   = cfg.AttestationSubnetCheck != nil && cfg.AttestationSubnetCheck.Enabled
+		serverAddress      = tui.OutputServerProduction
+		useTLS             = true
+		username           string
+		password           string
+	)
+
+	if answers.RunMethod != "" {
+		method, err := sidecar.ParseRunMethod(answers.RunMethod)
+		if err != nil {
+			return err
+		}
+
+		runMethod = method
+	}
+
+	if answers.Version != "" {
+		version = answers.Version
+	}
+
+	// Beacon node.
+	if answers.BeaconNodeAddress != "" {
+		beaconAddress = answers.BeaconNodeAddress
+	}
+
+	if err := validate.ValidateBeaconNodeAddress(beaconAddress); err != nil {
+		return fmt.Errorf("invalid beacon node address: %w", err)
+	}
+
+	if answers.DockerNetwork != "" {
+		dockerNetwork = answers.DockerNetwork
+	}
+
+	// Docker networks are only relevant when running under docker.
+	if runMethod != config.RunMethod_RUN_METHOD_DOCKER {
+		dockerNetwork = ""
+	}
+
+	// Output server.
+	if cfg.OutputServer != nil && cfg.OutputServer.Address != "" {
+		serverAddress = cfg.OutputServer.Address
+		useTLS = cfg.OutputServer.Tls
+
+		// Keep hold of any existing credentials, so re-running an install doesn't
+		// require them to be provided again.
+		if u, p, err := validate.DecodeCredentials(cfg.OutputServer.Credentials); err == nil {
+			username, password = u, p
+		}
+	}
+
+	if answers.OutputServer.Address != "" && answers.OutputServer.Address != serverAddress {
+		serverAddress = answers.OutputServer.Address
+		username, password = "", ""
+		useTLS = false
+	}
+
+	if answers.OutputServer.Username != "" || answers.OutputServer.Password != "" {
+		username = answers.OutputServer.Username
+		password = answers.OutputServer.Password
+	}
+
+	isEthPandaOps := validate.IsEthPandaOpsServer(serverAddress)
+
+	if isEthPandaOps {
+		// TLS is always on for ethPandaOps servers.
+		useTLS = true
+	} else {
+		if err := validate.ValidateOutputServerAddress(serverAddress); err != nil {
+			return fmt.Errorf("invalid output server address: %w", err)
+		}
+
+		if answers.OutputServer.TLS != nil {
+			useTLS = *answers.OutputServer.TLS
+		}
+	}
+
+	if err := validate.ValidateOutputServerCredentials(username, password, isEthPandaOps); err != nil {
+		return fmt.Errorf("invalid output server credentials: %w", err)
+	}
+
+	// Metrics + health.
+	if answers.MetricsAddress != nil {
+		metricsAddress = *answers.MetricsAddress
+	}
+
+	if err := validate.ValidateMetricsAddress(metricsAddress); err != nil {
+		return err
+	}
+
+	if answers.HealthCheckAddress != nil {
+		healthCheckAddress = *answers.HealthCheckAddress
+	}
+
+	if err := validate.ValidateHealthCheckAddress(healthCheckAddress); err != nil {
+		return err
+	}
+
+	if answers.AttestationOptIn != nil {
+		attestationOptIn = *answers.AttestationOptIn
+	}
+
+	return sidecarCfg.Update(func(cfg *config.Config) {
+		cfg.Version = version
+		cfg.RunMethod = runMethod
+		cfg.BeaconNodeAddress = beaconAddress
+		cfg.DockerNetwork = dockerNetwork
+		cfg.MetricsAddress = metricsAddress
+		cfg.HealthCheckAddress = healthCheckAddress
+
+		if cfg.OutputServer == nil {
+			cfg.OutputServer = &config.OutputServer{}
+		}
+
+		cfg.OutputServer.Address = serverAddress
+		cfg.OutputServer.Credentials = validate.EncodeCredentials(username, password)
+		cfg.OutputServer.Tls = useTLS
+
+		if attestationOptIn {
+			// Only create the AttestationSubnetCheck if user opts in.
+			if cfg.AttestationSubnetCheck == nil {
+				cfg.AttestationSubnetCheck = &config.AttestationSubnetCheck{}
+			}
+
+			cfg.AttestationSubnetCheck.Enabled = true
+		} else {
+			// Remove the field entirely for opt-out.
+			cfg.AttestationSubnetCheck = nil
+		}
+	})
+}
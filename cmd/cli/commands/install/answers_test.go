@@ -0,0 +1,367 @@
+package install
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/ethpandaops/contributoor-installer/internal/validate"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoadAnswersFile(t *testing.T) {
+	t.Run("parses all fields", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "answers.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+version: 0.0.71
+runMethod: systemd
+beaconNodeAddress: http://beacon:5052
+dockerNetwork: rocketpool_net
+outputServer:
+  address: https://xatu.example.com
+  username: user
+  password: pass
+  tls: true
+attestationOptIn: true
+metricsAddress: :9090
+healthCheckAddress: :9191
+`), 0600))
+
+		answers, err := loadAnswersFile(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, "0.0.71", answers.Version)
+		assert.Equal(t, "systemd", answers.RunMethod)
+		assert.Equal(t, "http://beacon:5052", answers.BeaconNodeAddress)
+		assert.Equal(t, "rocketpool_net", answers.DockerNetwork)
+		assert.Equal(t, "https://xatu.example.com", answers.OutputServer.Address)
+		assert.Equal(t, "user", answers.OutputServer.Username)
+		assert.Equal(t, "pass", answers.OutputServer.Password)
+		require.NotNil(t, answers.OutputServer.TLS)
+		assert.True(t, *answers.OutputServer.TLS)
+		require.NotNil(t, answers.AttestationOptIn)
+		assert.True(t, *answers.AttestationOptIn)
+		require.NotNil(t, answers.MetricsAddress)
+		assert.Equal(t, ":9090", *answers.MetricsAddress)
+		require.NotNil(t, answers.HealthCheckAddress)
+		assert.Equal(t, ":9191", *answers.HealthCheckAddress)
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "answers.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("beaconNode: http://beacon:5052\n"), 0600))
+
+		_, err := loadAnswersFile(path)
+		assert.ErrorContains(t, err, "failed to parse answers file")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := loadAnswersFile(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.ErrorContains(t, err, "failed to read answers file")
+	})
+}
+
+func TestCollectAnswers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+beaconNodeAddress: http://beacon:5052
+runMethod: docker
+`), 0600))
+
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.String("answers-file", "", "")
+	set.String("version", "", "")
+	set.String("run-method", "", "")
+	set.String("beacon-node-address", "", "")
+	set.Bool("attestation-opt-in", false, "")
+
+	require.NoError(t, set.Parse([]string{
+		"--answers-file", path,
+		"--run-method", "binary",
+		"--attestation-opt-in",
+	}))
+
+	answers, err := collectAnswers(cli.NewContext(cli.NewApp(), set, nil))
+	require.NoError(t, err)
+
+	// File values are kept unless a flag overrides them.
+	assert.Equal(t, "http://beacon:5052", answers.BeaconNodeAddress)
+	assert.Equal(t, "binary", answers.RunMethod)
+	assert.Empty(t, answers.Version)
+	require.NotNil(t, answers.AttestationOptIn)
+	assert.True(t, *answers.AttestationOptIn)
+	assert.Nil(t, answers.MetricsAddress)
+}
+
+func TestApplyAnswers(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+	strPtr := func(s string) *string { return &s }
+
+	newConfig := func() *config.Config {
+		return &config.Config{
+			Version:               "0.0.70",
+			ContributoorDirectory: "/tmp/contributoor",
+			RunMethod:             config.RunMethod_RUN_METHOD_DOCKER,
+			OutputServer: &config.OutputServer{
+				Address: tui.OutputServerProduction,
+				Tls:     true,
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		cfg           *config.Config
+		answers       *Answers
+		expectedError string
+		verify        func(t *testing.T, cfg *config.Config)
+	}{
+		{
+			name: "applies all answers",
+			cfg:  newConfig(),
+			answers: &Answers{
+				Version:           "0.0.71",
+				RunMethod:         "RUN_METHOD_DOCKER",
+				BeaconNodeAddress: "http://beacon:5052",
+				DockerNetwork:     "rocketpool_net",
+				OutputServer: OutputServerAnswers{
+					Username: "user",
+					Password: "pass",
+				},
+				AttestationOptIn:   boolPtr(true),
+				MetricsAddress:     strPtr(":9090"),
+				HealthCheckAddress: strPtr("127.0.0.1:9191"),
+			},
+			verify: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+
+				assert.Equal(t, "0.0.71", cfg.Version)
+				assert.Equal(t, config.RunMethod_RUN_METHOD_DOCKER, cfg.RunMethod)
+				assert.Equal(t, "http://beacon:5052", cfg.BeaconNodeAddress)
+				assert.Equal(t, "rocketpool_net", cfg.DockerNetwork)
+				assert.Equal(t, tui.OutputServerProduction, cfg.OutputServer.Address)
+				assert.Equal(t, validate.EncodeCredentials("user", "pass"), cfg.OutputServer.Credentials)
+				assert.True(t, cfg.OutputServer.Tls)
+				assert.True(t, cfg.AttestationSubnetCheck.Enabled)
+				assert.Equal(t, ":9090", cfg.MetricsAddress)
+				assert.Equal(t, "127.0.0.1:9191", cfg.HealthCheckAddress)
+			},
+		},
+		{
+			name: "drops docker network for non-docker run methods",
+			cfg:  newConfig(),
+			answers: &Answers{
+				RunMethod:         "binary",
+				BeaconNodeAddress: "http://beacon:5052",
+				DockerNetwork:     "rocketpool_net",
+				OutputServer: OutputServerAnswers{
+					Username: "user",
+					Password: "pass",
+				},
+			},
+			verify: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+
+				assert.Equal(t, config.RunMethod_RUN_METHOD_BINARY, cfg.RunMethod)
+				assert.Empty(t, cfg.DockerNetwork)
+				assert.Nil(t, cfg.AttestationSubnetCheck)
+			},
+		},
+		{
+			name: "custom output server without credentials",
+			cfg:  newConfig(),
+			answers: &Answers{
+				BeaconNodeAddress: "http://beacon:5052",
+				OutputServer: OutputServerAnswers{
+					Address: "https://xatu.example.com",
+				},
+			},
+			verify: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+
+				assert.Equal(t, "https://xatu.example.com", cfg.OutputServer.Address)
+				assert.Empty(t, cfg.OutputServer.Credentials)
+				assert.False(t, cfg.OutputServer.Tls)
+			},
+		},
+		{
+			name: "keeps existing credentials",
+			cfg: func() *config.Config {
+				cfg := newConfig()
+				cfg.OutputServer.Credentials = validate.EncodeCredentials("existing", "secret")
+
+				return cfg
+			}(),
+			answers: &Answers{
+				BeaconNodeAddress: "http://beacon:5052",
+			},
+			verify: func(t *testing.T, cfg *config.Config) {
+				t.Helper()
+
+				assert.Equal(t, validate.EncodeCredentials("existing", "secret"), cfg.OutputServer.Credentials)
+			},
+		},
+		{
+			name:          "invalid run method",
+			cfg:           newConfig(),
+			answers:       &Answers{RunMethod: "kubernetes"},
+			expectedError: "invalid run method",
+		},
+		{
+			name:          "invalid beacon node address",
+			cfg:           newConfig(),
+			answers:       &Answers{BeaconNodeAddress: "beacon:5052"},
+			expectedError: "invalid beacon node address",
+		},
+		{
+			name:          "missing beacon node address",
+			cfg:           newConfig(),
+			answers:       &Answers{},
+			expectedError: "invalid beacon node address",
+		},
+		{
+			name: "missing ethpandaops credentials",
+			cfg:  newConfig(),
+			answers: &Answers{
+				BeaconNodeAddress: "http://beacon:5052",
+			},
+			expectedError: "username and password are required",
+		},
+		{
+			name: "invalid custom output server",
+			cfg:  newConfig(),
+			answers: &Answers{
+				BeaconNodeAddress: "http://beacon:5052",
+				OutputServer: OutputServerAnswers{
+					Address: "xatu.example.com",
+				},
+			},
+			expectedError: "invalid output server address",
+		},
+		{
+			name: "invalid metrics address",
+			cfg:  newConfig(),
+			answers: &Answers{
+				BeaconNodeAddress: "http://beacon:5052",
+				OutputServer:      OutputServerAnswers{Username: "user", Password: "pass"},
+				MetricsAddress:    strPtr("http://127.0.0.1"),
+			},
+			expectedError: "metrics address must include a port",
+		},
+		{
+			name: "invalid health check address",
+			cfg:  newConfig(),
+			answers: &Answers{
+				BeaconNodeAddress:  "http://beacon:5052",
+				OutputServer:       OutputServerAnswers{Username: "user", Password: "pass"},
+				HealthCheckAddress: strPtr("http://127.0.0.1"),
+			},
+			expectedError: "health check address must include a port",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockConfig := mock.NewMockConfigManager(ctrl)
+			mockConfig.EXPECT().Get().Return(tt.cfg).AnyTimes()
+
+			if tt.expectedError == "" {
+				mockConfig.EXPECT().Update(gomock.Any()).DoAndReturn(func(fn func(*config.Config)) error {
+					fn(tt.cfg)
+
+					return nil
+				})
+			}
+
+			err := applyAnswers(mockConfig, tt.answers)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			tt.verify(t, tt.cfg)
+		})
+	}
+}
+
+func TestApplySystemdScope(t *testing.T) {
+	tests := []struct {
+		name          string
+		runMethod     string
+		existing      string
+		expectedScope string
+		expectedFile  string
+	}{
+		{
+			name:          "systemd user",
+			runMethod:     "systemd-user",
+			expectedScope: installer.SystemdScopeUser,
+			expectedFile:  "systemd:\n  scope: user\n",
+		},
+		{
+			name:          "back to a system unit",
+			runMethod:     "RUN_METHOD_SYSTEMD",
+			existing:      "containerRuntime: podman\nsystemd:\n  scope: user\n",
+			expectedScope: installer.SystemdScopeSystem,
+			expectedFile:  "containerRuntime: podman\nsystemd:\n  scope: system\n",
+		},
+		{
+			name:          "system unit by default",
+			runMethod:     "systemd",
+			expectedScope: installer.SystemdScopeSystem,
+		},
+		{
+			name:          "other run methods",
+			runMethod:     "docker",
+			expectedScope: installer.SystemdScopeSystem,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var (
+				dir          = t.TempDir()
+				path         = filepath.Join(dir, installer.ConfigFilename)
+				installerCfg = installer.NewConfig()
+			)
+
+			if tt.existing != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.existing), 0600))
+				require.NoError(t, installerCfg.LoadFile(path))
+			}
+
+			mockConfig := mock.NewMockConfigManager(ctrl)
+			mockConfig.EXPECT().GetConfigPath().Return(filepath.Join(dir, "config.yaml")).AnyTimes()
+
+			require.NoError(t, applySystemdScope(mockConfig, installerCfg, &Answers{RunMethod: tt.runMethod}))
+			assert.Equal(t, tt.expectedScope, installerCfg.Systemd.Scope)
+
+			data, err := os.ReadFile(path)
+			if tt.expectedFile == "" {
+				assert.True(t, os.IsNotExist(err), "installer config should only be written when the scope changes")
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedFile, string(data))
+		})
+	}
+}
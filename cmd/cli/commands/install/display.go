@@ -1,8 +1,7 @@
 package install
 
 import (
-	"fmt"
-
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
 	"github.com/rivo/tview"
@@ -16,6 +15,7 @@ type InstallDisplay struct {
 	frame        *tview.Frame
 	log          *logrus.Logger
 	sidecarCfg   sidecar.ConfigManager
+	installerCfg *installer.Config
 	installPages []tui.PageInterface
 	welcomePage  *WelcomePage
 	beaconPage   *BeaconNodePage
@@ -27,12 +27,18 @@ type InstallDisplay struct {
 }
 
 // NewInstallDisplay creates a new InstallDisplay.
-func NewInstallDisplay(log *logrus.Logger, app *tview.Application, sidecarCfg sidecar.ConfigManager) *InstallDisplay {
+func NewInstallDisplay(
+	log *logrus.Logger,
+	app *tview.Application,
+	sidecarCfg sidecar.ConfigManager,
+	installerCfg *installer.Config,
+) *InstallDisplay {
 	display := &InstallDisplay{
-		app:        app,
-		pages:      tview.NewPages(),
-		log:        log,
-		sidecarCfg: sidecarCfg,
+		app:          app,
+		pages:        tview.NewPages(),
+		log:          log,
+		sidecarCfg:   sidecarCfg,
+		installerCfg: installerCfg,
 	}
 
 	// Create all of our install wizard pages.
@@ -127,21 +133,7 @@ func (d *InstallDisplay) setPage(page *tui.Page) {
 
 // OnComplete is called when the install wizard is complete.
 func (d *InstallDisplay) OnComplete() error {
-	cfg := d.sidecarCfg.Get()
-
-	fmt.Printf("%sContributoor Status%s\n", tui.TerminalColorLightBlue, tui.TerminalColorReset)
-	fmt.Printf("%-20s: %s\n", "Version", cfg.Version)
-	fmt.Printf("%-20s: %s\n", "Run Method", cfg.RunMethod)
-	fmt.Printf("%-20s: %s\n", "Beacon Node", cfg.BeaconNodeAddress)
-	fmt.Printf("%-20s: %s\n", "Config Path", d.sidecarCfg.GetConfigPath())
-
-	if cfg.OutputServer != nil {
-		fmt.Printf("%-20s: %s\n", "Output Server", cfg.OutputServer.Address)
-	}
-
-	fmt.Printf("\n%sInstallation complete%s\n", tui.TerminalColorGreen, tui.TerminalColorReset)
-	fmt.Printf("You can now manage contributoor using the following command(s):\n")
-	fmt.Printf("    contributoor [start|stop|restart|status|update|config|logs]\n")
+	printInstallSummary(d.sidecarCfg)
 
 	return nil
 }
@@ -4,8 +4,10 @@ import (
 	"fmt"
 
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
 	"github.com/rivo/tview"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
@@ -21,32 +23,104 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 		Action: func(c *cli.Context) error {
 			log := opts.Logger()
 
-			sidecarCfg, err := sidecar.NewConfigService(log, c.String("config-path"))
+			configPath, err := opts.ConfigPath(c)
 			if err != nil {
 				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			return installContributoor(c, log, sidecarCfg)
+			// Named instances are created on first install, install.sh only sets up the default one.
+			if options.InstanceName(c) != "" {
+				if err := sidecar.InitInstanceConfig(configPath); err != nil {
+					return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+				}
+			}
+
+			sidecarCfg, err := sidecar.NewConfigService(log, configPath)
+			if err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+			}
+
+			return installContributoor(c, log, sidecarCfg, opts.InstallerConfig())
 		},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  "version, v",
 				Usage: "The contributoor version to install",
-				Value: "latest",
 			},
 			&cli.StringFlag{
 				Name:  "run-method, r",
-				Usage: "The method to run contributoor",
-				Value: sidecar.RunMethodDocker,
+				Usage: "The method to run contributoor (docker, systemd, systemd-user or binary)",
+			},
+			&cli.StringFlag{
+				Name:  "answers-file",
+				Usage: "Install non-interactively using the answers in this YAML `file`",
+			},
+			&cli.StringFlag{
+				Name:  "beacon-node-address",
+				Usage: "Beacon node address(es), comma separated",
+			},
+			&cli.StringFlag{
+				Name:  "docker-network",
+				Usage: "Optional docker network to join (docker run method only)",
+			},
+			&cli.StringFlag{
+				Name:  "output-server-address",
+				Usage: "Output server address",
 			},
+			&cli.StringFlag{
+				Name:    "output-server-username",
+				Usage:   "Output server username",
+				EnvVars: []string{"CONTRIBUTOOR_OUTPUT_SERVER_USERNAME"},
+			},
+			&cli.StringFlag{
+				Name:    "output-server-password",
+				Usage:   "Output server password",
+				EnvVars: []string{"CONTRIBUTOOR_OUTPUT_SERVER_PASSWORD"},
+			},
+			&cli.BoolFlag{
+				Name:  "output-server-tls",
+				Usage: "Use TLS when connecting to a custom output server",
+			},
+			&cli.BoolFlag{
+				Name:  "attestation-opt-in",
+				Usage: "Opt in to attestation data contribution",
+			},
+			&cli.StringFlag{
+				Name:  "metrics-address",
+				Usage: "Optional address to serve metrics on",
+			},
+			&cli.StringFlag{
+				Name:  "health-check-address",
+				Usage: "Optional address to serve health checks on",
+			},
+			options.InstanceFlag(),
 		},
 	})
 }
 
-func installContributoor(c *cli.Context, log *logrus.Logger, sidecarCfg sidecar.ConfigManager) error {
+func installContributoor(c *cli.Context, log *logrus.Logger, sidecarCfg sidecar.ConfigManager, installerCfg *installer.Config) error {
+	answers, err := collectAnswers(c)
+	if err != nil {
+		return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+	}
+
+	if err := applySystemdScope(sidecarCfg, installerCfg, answers); err != nil {
+		return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+	}
+
+	// Skip the wizard entirely if we've been asked to run headless.
+	if c.Bool("non-interactive") || c.IsSet("answers-file") {
+		return installNonInteractive(log, sidecarCfg, answers)
+	}
+
+	// The wizard pages depend on the run method, so apply it (and the version) up front.
+	if err := applyVersionAndRunMethod(sidecarCfg, answers); err != nil {
+		return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+	}
+
 	var (
 		app     = tview.NewApplication()
-		display = NewInstallDisplay(log, app, sidecarCfg)
+		display = NewInstallDisplay(log, app, sidecarCfg, installerCfg)
 	)
 
 	// Run the display.
@@ -65,3 +139,61 @@ func installContributoor(c *cli.Context, log *logrus.Logger, sidecarCfg sidecar.
 
 	return nil
 }
+
+// installNonInteractive validates and saves the answers without launching the wizard.
+func installNonInteractive(log *logrus.Logger, sidecarCfg sidecar.ConfigManager, answers *Answers) error {
+	log.WithField("config_path", sidecarCfg.GetConfigPath()).Debug("Running non-interactive installation")
+
+	if err := applyAnswers(sidecarCfg, answers); err != nil {
+		return fmt.Errorf("%sinstallation failed: %v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+	}
+
+	printInstallSummary(sidecarCfg)
+
+	return nil
+}
+
+// applyVersionAndRunMethod writes the version and run method answers (if provided) to the config.
+func applyVersionAndRunMethod(sidecarCfg sidecar.ConfigManager, answers *Answers) error {
+	if answers.Version == "" && answers.RunMethod == "" {
+		return nil
+	}
+
+	runMethod := sidecarCfg.Get().RunMethod
+
+	if answers.RunMethod != "" {
+		method, err := sidecar.ParseRunMethod(answers.RunMethod)
+		if err != nil {
+			return err
+		}
+
+		runMethod = method
+	}
+
+	return sidecarCfg.Update(func(cfg *config.Config) {
+		if answers.Version != "" {
+			cfg.Version = answers.Version
+		}
+
+		cfg.RunMethod = runMethod
+	})
+}
+
+// printInstallSummary prints the installed configuration and next steps.
+func printInstallSummary(sidecarCfg sidecar.ConfigManager) {
+	cfg := sidecarCfg.Get()
+
+	fmt.Printf("%sContributoor Status%s\n", tui.TerminalColorLightBlue, tui.TerminalColorReset)
+	fmt.Printf("%-20s: %s\n", "Version", cfg.Version)
+	fmt.Printf("%-20s: %s\n", "Run Method", cfg.RunMethod)
+	fmt.Printf("%-20s: %s\n", "Beacon Node", cfg.BeaconNodeAddress)
+	fmt.Printf("%-20s: %s\n", "Config Path", sidecarCfg.GetConfigPath())
+
+	if cfg.OutputServer != nil {
+		fmt.Printf("%-20s: %s\n", "Output Server", cfg.OutputServer.Address)
+	}
+
+	fmt.Printf("\n%sInstallation complete%s\n", tui.TerminalColorGreen, tui.TerminalColorReset)
+	fmt.Printf("You can now manage contributoor using the following command(s):\n")
+	fmt.Printf("    contributoor [start|stop|restart|status|update|config|logs]\n")
+}
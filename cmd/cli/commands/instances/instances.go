@@ -0,0 +1,169 @@
+package instances
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      opts.Name(),
+		Aliases:   opts.Aliases(),
+		Usage:     "Manage the contributoor instances on this host",
+		UsageText: "contributoor instances [command]",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "list",
+				Usage:     "List the contributoor instances and their status",
+				UsageText: "contributoor instances list",
+				Action: func(c *cli.Context) error {
+					found, err := sidecar.ListInstances(c.String("config-path"))
+					if err != nil {
+						return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+					}
+
+					return listInstances(c, found, func(ctx context.Context, instance sidecar.Instance) instanceSummary {
+						return summarise(ctx, opts.Logger(), opts.InstallerConfig(), instance)
+					})
+				},
+			},
+		},
+	})
+}
+
+// instanceSummary is a single row of `contributoor instances list`.
+type instanceSummary struct {
+	Name       string
+	RunMethod  string
+	Network    string
+	ConfigPath string
+	Status     *sidecar.Status
+	Err        error
+}
+
+func listInstances(
+	c *cli.Context,
+	found []sidecar.Instance,
+	summariseFn func(ctx context.Context, instance sidecar.Instance) instanceSummary,
+) error {
+	if len(found) == 0 {
+		fmt.Fprintf(c.App.Writer, "%sNo contributoor instances found under %s%s\n",
+			tui.TerminalColorYellow, c.String("config-path"), tui.TerminalColorReset)
+
+		return nil
+	}
+
+	summaries := make([]instanceSummary, 0, len(found))
+	for _, instance := range found {
+		summaries = append(summaries, summariseFn(c.Context, instance))
+	}
+
+	printInstances(c.App.Writer, summaries)
+
+	return nil
+}
+
+// summarise loads the config of instance and asks its runner for the status. Errors
+// are recorded on the summary so one broken instance doesn't hide the others.
+func summarise(
+	ctx context.Context,
+	log *logrus.Logger,
+	installerCfg *installer.Config,
+	instance sidecar.Instance,
+) instanceSummary {
+	summary := instanceSummary{
+		Name:       instance.DisplayName(),
+		ConfigPath: instance.ConfigPath,
+	}
+
+	sidecarCfg, err := sidecar.NewConfigService(log, instance.ConfigPath)
+	if err != nil {
+		summary.Err = err
+
+		return summary
+	}
+
+	cfg := sidecarCfg.Get()
+	summary.Network = cfg.NetworkName
+
+	// Each instance gets its own copy of the installer config, with its own overrides.
+	instanceCfg := *installerCfg
+	instanceCfg.Instance = instance.Name
+
+	if instance.Name != "" {
+		if err := instanceCfg.LoadFile(filepath.Join(instance.ConfigPath, installer.ConfigFilename)); err != nil {
+			summary.Err = err
+
+			return summary
+		}
+	}
+
+	summary.RunMethod = sidecar.RunMode{Method: cfg.RunMethod, Scope: instanceCfg.Systemd.Scope}.Name()
+
+	runner, err := sidecar.ResolveRunner(log, sidecarCfg, &instanceCfg)
+	if err != nil {
+		summary.Err = err
+
+		return summary
+	}
+
+	summary.Status, summary.Err = runner.Status(ctx)
+
+	return summary
+}
+
+// printInstances prints the summaries as a table.
+func printInstances(w io.Writer, summaries []instanceSummary) {
+	fmt.Fprintf(w, "%sContributoor Instances%s\n", tui.TerminalColorLightBlue, tui.TerminalColorReset)
+	fmt.Fprintf(w, "%-20s %-10s %-12s %-12s %s\n", "NAME", "METHOD", "NETWORK", "STATUS", "CONFIG PATH")
+
+	for _, summary := range summaries {
+		var (
+			color  = tui.TerminalColorRed
+			status = "error"
+		)
+
+		if summary.Err == nil && summary.Status != nil {
+			status = summary.Status.State.String()
+
+			switch summary.Status.State {
+			case sidecar.StateRunning:
+				color = tui.TerminalColorGreen
+			case sidecar.StateStarting, sidecar.StateRestarting, sidecar.StateStopped:
+				color = tui.TerminalColorYellow
+			}
+		}
+
+		fmt.Fprintf(w, "%-20s %-10s %-12s %s%-12s%s %s\n",
+			summary.Name,
+			orDash(summary.RunMethod),
+			orDash(summary.Network),
+			color, status, tui.TerminalColorReset,
+			summary.ConfigPath,
+		)
+	}
+
+	// Errors are listed below the table, they're usually too long to fit in it.
+	for _, summary := range summaries {
+		if summary.Err != nil {
+			fmt.Fprintf(w, "\n%s%s:%s %v\n", tui.TerminalColorRed, summary.Name, tui.TerminalColorReset, summary.Err)
+		}
+	}
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
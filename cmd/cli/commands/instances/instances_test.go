@@ -0,0 +1,85 @@
+package instances
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestListInstances(t *testing.T) {
+	tests := []struct {
+		name     string
+		found    []sidecar.Instance
+		expected []string
+		absent   []string
+	}{
+		{
+			name:     "no instances",
+			expected: []string{"No contributoor instances found under /etc/contributoor"},
+			absent:   []string{"NAME"},
+		},
+		{
+			name: "default and named instances",
+			found: []sidecar.Instance{
+				{ConfigPath: "/etc/contributoor"},
+				{Name: "holesky", ConfigPath: "/etc/contributoor/instances/holesky"},
+				{Name: "broken", ConfigPath: "/etc/contributoor/instances/broken"},
+			},
+			expected: []string{
+				"NAME",
+				"default",
+				"/etc/contributoor/instances/holesky",
+				"running",
+				"stopped",
+				"broken:",
+				"config is unreadable",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.String("config-path", "/etc/contributoor", "")
+
+			app := cli.NewApp()
+			app.Writer = &out
+
+			c := cli.NewContext(app, set, nil)
+			c.Context = context.Background()
+
+			err := listInstances(c, tt.found, func(_ context.Context, instance sidecar.Instance) instanceSummary {
+				summary := instanceSummary{Name: instance.DisplayName(), ConfigPath: instance.ConfigPath, RunMethod: "docker"}
+
+				switch instance.Name {
+				case "":
+					summary.Status = &sidecar.Status{State: sidecar.StateRunning}
+				case "broken":
+					summary.Err = errors.New("config is unreadable")
+				default:
+					summary.Status = &sidecar.Status{State: sidecar.StateStopped}
+				}
+
+				return summary
+			})
+			require.NoError(t, err)
+
+			for _, expected := range tt.expected {
+				assert.Contains(t, out.String(), expected)
+			}
+
+			for _, absent := range tt.absent {
+				assert.NotContains(t, out.String(), absent)
+			}
+		})
+	}
+}
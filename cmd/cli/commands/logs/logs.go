@@ -1,16 +1,30 @@
 package logs
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"regexp"
+	"time"
 
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
-	"github.com/ethpandaops/contributoor/pkg/config/v1"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
 )
 
+// logTimeLayouts are the layouts --since and --until accept, besides a duration.
+// Layouts without a zone are in local time.
+var logTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
 func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 	app.Commands = append(app.Commands, &cli.Command{
 		Name:      "logs",
@@ -26,6 +40,28 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 				Name:  "follow, f",
 				Usage: "Follow log output",
 			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Show logs since a time, eg: \"2024-01-02 15:04:05\", or a duration ago, eg: \"1h\"",
+			},
+			&cli.StringFlag{
+				Name:  "until",
+				Usage: "Show logs before a time, eg: \"2024-01-02 15:04:05\", or a duration ago, eg: \"30m\"",
+			},
+			&cli.StringFlag{
+				Name:  "level",
+				Usage: "Show logs at this level or more severe, eg: \"warn\"",
+			},
+			&cli.StringFlag{
+				Name:  "grep",
+				Usage: "Show logs matching a regular expression",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: raw, pretty or json",
+				Value: string(sidecar.LogFormatRaw),
+			},
+			options.InstanceFlag(),
 		},
 		Action: func(c *cli.Context) error {
 			var (
@@ -33,55 +69,111 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 				installerCfg = opts.InstallerConfig()
 			)
 
-			sidecarCfg, err := sidecar.NewConfigService(log, c.String("config-path"))
+			configPath, err := opts.ConfigPath(c)
 			if err != nil {
 				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			dockerSidecar, err := sidecar.NewDockerSidecar(log, sidecarCfg, installerCfg)
+			sidecarCfg, err := sidecar.NewConfigService(log, configPath)
 			if err != nil {
-				return fmt.Errorf("error creating docker sidecar service: %w", err)
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			systemdSidecar, err := sidecar.NewSystemdSidecar(log, sidecarCfg, installerCfg)
+			runner, err := sidecar.ResolveRunner(log, sidecarCfg, installerCfg)
 			if err != nil {
-				return fmt.Errorf("error creating systemd sidecar service: %w", err)
+				return err
 			}
 
-			binarySidecar, err := sidecar.NewBinarySidecar(log, sidecarCfg, installerCfg)
-			if err != nil {
-				return fmt.Errorf("error creating binary sidecar service: %w", err)
+			if err := showLogs(c, runner); err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			return showLogs(c, log, sidecarCfg, dockerSidecar, systemdSidecar, binarySidecar)
+			return nil
 		},
 	})
 }
 
-func showLogs(
-	c *cli.Context,
-	log *logrus.Logger,
-	sidecarCfg sidecar.ConfigManager,
-	docker sidecar.DockerSidecar,
-	systemd sidecar.SystemdSidecar,
-	binary sidecar.BinarySidecar,
-) error {
-	var (
-		runner sidecar.SidecarRunner
-		cfg    = sidecarCfg.Get()
-	)
-
-	// Get the appropriate runner based on run method.
-	switch cfg.RunMethod {
-	case config.RunMethod_RUN_METHOD_DOCKER:
-		runner = docker
-	case config.RunMethod_RUN_METHOD_SYSTEMD:
-		runner = systemd
-	case config.RunMethod_RUN_METHOD_BINARY:
-		runner = binary
+func showLogs(c *cli.Context, runner sidecar.SidecarRunner) error {
+	opts, err := logOptions(c, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return runner.Logs(c.Context, opts)
+}
+
+// logOptions returns the log options selected by the command's flags.
+func logOptions(c *cli.Context, now time.Time) (sidecar.LogOptions, error) {
+	opts := sidecar.LogOptions{
+		Tail:   c.Int("tail"),
+		Follow: c.Bool("follow"),
+		Format: sidecar.LogFormat(c.String("format")),
+		// Colours would be written out as escape codes if the output isn't a terminal.
+		Color: term.IsTerminal(int(os.Stdout.Fd())) && os.Getenv("NO_COLOR") == "",
+	}
+
+	switch opts.Format {
+	case "":
+		opts.Format = sidecar.LogFormatRaw
+	case sidecar.LogFormatRaw, sidecar.LogFormatPretty, sidecar.LogFormatJSON:
 	default:
-		return fmt.Errorf("invalid sidecar run method: %s", cfg.RunMethod)
+		return opts, fmt.Errorf(
+			"invalid --format %q, must be one of: %s, %s, %s",
+			opts.Format, sidecar.LogFormatRaw, sidecar.LogFormatPretty, sidecar.LogFormatJSON,
+		)
+	}
+
+	var err error
+
+	if since := c.String("since"); since != "" {
+		if opts.Since, err = parseLogTime(since, now); err != nil {
+			return opts, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+
+	if until := c.String("until"); until != "" {
+		if opts.Follow {
+			return opts, errors.New("--until can't be used with --follow")
+		}
+
+		if opts.Until, err = parseLogTime(until, now); err != nil {
+			return opts, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Until.After(opts.Since) {
+		return opts, errors.New("--until must be after --since")
+	}
+
+	if level := c.String("level"); level != "" {
+		if _, err := logrus.ParseLevel(level); err != nil {
+			return opts, fmt.Errorf("invalid --level: %q isn't a log level", level)
+		}
+
+		opts.Level = level
+	}
+
+	if grep := c.String("grep"); grep != "" {
+		if opts.Grep, err = regexp.Compile(grep); err != nil {
+			return opts, fmt.Errorf("invalid --grep: %w", err)
+		}
+	}
+
+	return opts, nil
+}
+
+// parseLogTime parses a --since or --until value, which is either a time or a
+// duration before now.
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d.Abs()), nil
+	}
+
+	for _, layout := range logTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
 	}
 
-	return runner.Logs(c.Int("tail"), c.Bool("follow"))
+	return time.Time{}, fmt.Errorf("%q isn't a time or duration, eg: \"2024-01-02 15:04:05\" or \"1h\"", value)
 }
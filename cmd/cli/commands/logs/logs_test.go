@@ -4,8 +4,10 @@ import (
 	"errors"
 	"flag"
 	"testing"
+	"time"
 
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	sidecarmock "github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
 	"github.com/ethpandaops/contributoor/pkg/config/v1"
 	"github.com/sirupsen/logrus"
@@ -24,7 +26,8 @@ func TestShowLogs(t *testing.T) {
 		runMethod     config.RunMethod
 		tailLines     int
 		follow        bool
-		setupMocks    func(*sidecarmock.MockConfigManager, *sidecarmock.MockDockerSidecar, *sidecarmock.MockBinarySidecar, *sidecarmock.MockSystemdSidecar)
+		format        string
+		setupMocks    func(*sidecarmock.MockDockerSidecar, *sidecarmock.MockBinarySidecar, *sidecarmock.MockSystemdSidecar)
 		expectedError string
 	}{
 		{
@@ -32,11 +35,8 @@ func TestShowLogs(t *testing.T) {
 			runMethod: config.RunMethod_RUN_METHOD_DOCKER,
 			tailLines: 100,
 			follow:    false,
-			setupMocks: func(cfg *sidecarmock.MockConfigManager, d *sidecarmock.MockDockerSidecar, b *sidecarmock.MockBinarySidecar, s *sidecarmock.MockSystemdSidecar) {
-				cfg.EXPECT().Get().Return(&config.Config{
-					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
-				}).Times(1)
-				d.EXPECT().Logs(100, false).Return(nil)
+			setupMocks: func(d *sidecarmock.MockDockerSidecar, b *sidecarmock.MockBinarySidecar, s *sidecarmock.MockSystemdSidecar) {
+				d.EXPECT().Logs(gomock.Any(), sidecar.LogOptions{Tail: 100, Format: sidecar.LogFormatRaw}).Return(nil)
 			},
 		},
 		{
@@ -44,11 +44,8 @@ func TestShowLogs(t *testing.T) {
 			runMethod: config.RunMethod_RUN_METHOD_DOCKER,
 			tailLines: 100,
 			follow:    false,
-			setupMocks: func(cfg *sidecarmock.MockConfigManager, d *sidecarmock.MockDockerSidecar, b *sidecarmock.MockBinarySidecar, s *sidecarmock.MockSystemdSidecar) {
-				cfg.EXPECT().Get().Return(&config.Config{
-					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
-				}).Times(1)
-				d.EXPECT().Logs(100, false).Return(errors.New("logs failed"))
+			setupMocks: func(d *sidecarmock.MockDockerSidecar, b *sidecarmock.MockBinarySidecar, s *sidecarmock.MockSystemdSidecar) {
+				d.EXPECT().Logs(gomock.Any(), sidecar.LogOptions{Tail: 100, Format: sidecar.LogFormatRaw}).Return(errors.New("logs failed"))
 			},
 			expectedError: "logs failed",
 		},
@@ -57,11 +54,9 @@ func TestShowLogs(t *testing.T) {
 			runMethod: config.RunMethod_RUN_METHOD_BINARY,
 			tailLines: 50,
 			follow:    true,
-			setupMocks: func(cfg *sidecarmock.MockConfigManager, d *sidecarmock.MockDockerSidecar, b *sidecarmock.MockBinarySidecar, s *sidecarmock.MockSystemdSidecar) {
-				cfg.EXPECT().Get().Return(&config.Config{
-					RunMethod: config.RunMethod_RUN_METHOD_BINARY,
-				}).Times(1)
-				b.EXPECT().Logs(50, true).Return(nil)
+			format:    "json",
+			setupMocks: func(d *sidecarmock.MockDockerSidecar, b *sidecarmock.MockBinarySidecar, s *sidecarmock.MockSystemdSidecar) {
+				b.EXPECT().Logs(gomock.Any(), sidecar.LogOptions{Tail: 50, Follow: true, Format: sidecar.LogFormatJSON}).Return(nil)
 			},
 		},
 		{
@@ -69,37 +64,21 @@ func TestShowLogs(t *testing.T) {
 			runMethod: config.RunMethod_RUN_METHOD_SYSTEMD,
 			tailLines: 200,
 			follow:    false,
-			setupMocks: func(cfg *sidecarmock.MockConfigManager, d *sidecarmock.MockDockerSidecar, b *sidecarmock.MockBinarySidecar, s *sidecarmock.MockSystemdSidecar) {
-				cfg.EXPECT().Get().Return(&config.Config{
-					RunMethod: config.RunMethod_RUN_METHOD_SYSTEMD,
-				}).Times(1)
-				s.EXPECT().Logs(200, false).Return(nil)
+			setupMocks: func(d *sidecarmock.MockDockerSidecar, b *sidecarmock.MockBinarySidecar, s *sidecarmock.MockSystemdSidecar) {
+				s.EXPECT().Logs(gomock.Any(), sidecar.LogOptions{Tail: 200, Format: sidecar.LogFormatRaw}).Return(nil)
 			},
 		},
-		{
-			name:      "invalid sidecar run method",
-			runMethod: config.RunMethod_RUN_METHOD_UNSPECIFIED,
-			tailLines: 100,
-			follow:    false,
-			setupMocks: func(cfg *sidecarmock.MockConfigManager, d *sidecarmock.MockDockerSidecar, b *sidecarmock.MockBinarySidecar, s *sidecarmock.MockSystemdSidecar) {
-				cfg.EXPECT().Get().Return(&config.Config{
-					RunMethod: config.RunMethod_RUN_METHOD_UNSPECIFIED,
-				}).Times(1)
-			},
-			expectedError: "invalid sidecar run method",
-		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var (
-				mockConfig  = sidecarmock.NewMockConfigManager(ctrl)
 				mockDocker  = sidecarmock.NewMockDockerSidecar(ctrl)
 				mockBinary  = sidecarmock.NewMockBinarySidecar(ctrl)
 				mockSystemd = sidecarmock.NewMockSystemdSidecar(ctrl)
 			)
 
-			tt.setupMocks(mockConfig, mockDocker, mockBinary, mockSystemd)
+			tt.setupMocks(mockDocker, mockBinary, mockSystemd)
 
 			var (
 				app = cli.NewApp()
@@ -108,9 +87,20 @@ func TestShowLogs(t *testing.T) {
 
 			set.Int("tail", tt.tailLines, "")
 			set.Bool("follow", tt.follow, "")
+			set.String("since", "", "")
+			set.String("until", "", "")
+			set.String("level", "", "")
+			set.String("grep", "", "")
+			set.String("format", tt.format, "")
 			ctx := cli.NewContext(app, set, nil)
 
-			err := showLogs(ctx, logrus.New(), mockConfig, mockDocker, mockSystemd, mockBinary)
+			runner := map[config.RunMethod]sidecar.SidecarRunner{
+				config.RunMethod_RUN_METHOD_DOCKER:  mockDocker,
+				config.RunMethod_RUN_METHOD_SYSTEMD: mockSystemd,
+				config.RunMethod_RUN_METHOD_BINARY:  mockBinary,
+			}[tt.runMethod]
+
+			err := showLogs(ctx, runner)
 
 			if tt.expectedError != "" {
 				assert.ErrorContains(t, err, tt.expectedError)
@@ -123,6 +113,131 @@ func TestShowLogs(t *testing.T) {
 	}
 }
 
+func TestLogOptions(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		flags         map[string]string
+		expected      sidecar.LogOptions
+		expectedError string
+	}{
+		{
+			name:     "defaults",
+			flags:    map[string]string{"tail": "100"},
+			expected: sidecar.LogOptions{Tail: 100, Format: sidecar.LogFormatRaw},
+		},
+		{
+			name:  "time window and level",
+			flags: map[string]string{"since": "2h", "until": "2024-01-02 14:30:00", "level": "warn", "format": "pretty"},
+			expected: sidecar.LogOptions{
+				Since:  now.Add(-2 * time.Hour),
+				Until:  time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC),
+				Level:  "warn",
+				Format: sidecar.LogFormatPretty,
+			},
+		},
+		{
+			name:          "until with follow",
+			flags:         map[string]string{"follow": "true", "until": "1h"},
+			expectedError: "--until can't be used with --follow",
+		},
+		{
+			name:          "until before since",
+			flags:         map[string]string{"since": "1h", "until": "2h"},
+			expectedError: "--until must be after --since",
+		},
+		{
+			name:          "invalid since",
+			flags:         map[string]string{"since": "yesterday"},
+			expectedError: "invalid --since",
+		},
+		{
+			name:          "invalid level",
+			flags:         map[string]string{"level": "loud"},
+			expectedError: "invalid --level",
+		},
+		{
+			name:          "invalid format",
+			flags:         map[string]string{"format": "yaml"},
+			expectedError: `invalid --format "yaml"`,
+		},
+		{
+			name:          "invalid grep",
+			flags:         map[string]string{"grep": "(unclosed"},
+			expectedError: "invalid --grep",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.Int("tail", 0, "")
+			set.Bool("follow", false, "")
+			set.String("since", "", "")
+			set.String("until", "", "")
+			set.String("level", "", "")
+			set.String("grep", "", "")
+			set.String("format", "raw", "")
+
+			for name, value := range tt.flags {
+				require.NoError(t, set.Set(name, value))
+			}
+
+			opts, err := logOptions(cli.NewContext(cli.NewApp(), set, nil), now)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, opts)
+		})
+	}
+
+	t.Run("grep", func(t *testing.T) {
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		set.String("grep", "", "")
+		set.String("format", "", "")
+		require.NoError(t, set.Set("grep", "peer.*dropped"))
+
+		opts, err := logOptions(cli.NewContext(cli.NewApp(), set, nil), now)
+		require.NoError(t, err)
+		require.NotNil(t, opts.Grep)
+		assert.True(t, opts.Grep.MatchString("msg=\"peer 1 dropped\""))
+	})
+}
+
+func TestParseLogTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: "30m", expected: now.Add(-30 * time.Minute)},
+		{value: "-30m", expected: now.Add(-30 * time.Minute)},
+		{value: "2024-01-02T10:00:00+02:00", expected: time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC)},
+		{value: "2024-01-02 10:00:00", expected: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+		{value: "2024-01-02T10:00:00", expected: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+		{value: "2024-01-02 10:00", expected: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+		{value: "2024-01-01", expected: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			parsed, err := parseLogTime(tt.value, now)
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(parsed), "expected %s, got %s", tt.expected, parsed)
+		})
+	}
+
+	_, err := parseLogTime("last tuesday", now)
+	assert.ErrorContains(t, err, "isn't a time or duration")
+}
+
 func TestRegisterCommands(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -199,13 +314,20 @@ func TestRegisterCommands(t *testing.T) {
 				assert.NotNil(t, cmd.Action)
 
 				// Verify flags.
-				assert.Len(t, cmd.Flags, 2)
+				assert.Len(t, cmd.Flags, 8)
 				tailFlag, _ := cmd.Flags[0].(*cli.IntFlag)
 				followFlag, _ := cmd.Flags[1].(*cli.BoolFlag)
+				instanceFlag, _ := cmd.Flags[7].(*cli.StringFlag)
 
 				assert.Equal(t, "tail", tailFlag.Name)
 				assert.Equal(t, 100, tailFlag.Value)
 				assert.Equal(t, "follow, f", followFlag.Name)
+				assert.Equal(t, options.InstanceFlagName, instanceFlag.Name)
+
+				for i, name := range []string{"since", "until", "level", "grep", "format"} {
+					flag, _ := cmd.Flags[i+2].(*cli.StringFlag)
+					assert.Equal(t, name, flag.Name)
+				}
 			}
 		})
 	}
@@ -7,7 +7,6 @@ import (
 	"github.com/ethpandaops/contributoor-installer/internal/service"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
-	"github.com/ethpandaops/contributoor/pkg/config/v1"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
@@ -18,30 +17,26 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 		Aliases:   opts.Aliases(),
 		Usage:     "Restart Contributoor",
 		UsageText: "contributoor restart [options]",
+		Flags:     []cli.Flag{options.InstanceFlag()},
 		Action: func(c *cli.Context) error {
 			var (
 				log          = opts.Logger()
 				installerCfg = opts.InstallerConfig()
 			)
 
-			sidecarCfg, err := sidecar.NewConfigService(log, c.String("config-path"))
+			configPath, err := opts.ConfigPath(c)
 			if err != nil {
 				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			dockerSidecar, err := sidecar.NewDockerSidecar(log, sidecarCfg, installerCfg)
+			sidecarCfg, err := sidecar.NewConfigService(log, configPath)
 			if err != nil {
-				return fmt.Errorf("error creating docker sidecar service: %w", err)
-			}
-
-			systemdSidecar, err := sidecar.NewSystemdSidecar(log, sidecarCfg, installerCfg)
-			if err != nil {
-				return fmt.Errorf("error creating systemd sidecar service: %w", err)
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			binarySidecar, err := sidecar.NewBinarySidecar(log, sidecarCfg, installerCfg)
+			runner, err := sidecar.ResolveRunner(log, sidecarCfg, installerCfg)
 			if err != nil {
-				return fmt.Errorf("error creating binary sidecar service: %w", err)
+				return err
 			}
 
 			githubService, err := service.NewGitHubService(log, installerCfg)
@@ -49,7 +44,7 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 				return fmt.Errorf("error creating github service: %w", err)
 			}
 
-			return restartContributoor(c, log, sidecarCfg, dockerSidecar, systemdSidecar, binarySidecar, githubService)
+			return restartContributoor(c, log, sidecarCfg, runner, githubService)
 		},
 	})
 }
@@ -58,30 +53,13 @@ func restartContributoor(
 	c *cli.Context,
 	log *logrus.Logger,
 	sidecarCfg sidecar.ConfigManager,
-	docker sidecar.DockerSidecar,
-	systemd sidecar.SystemdSidecar,
-	binary sidecar.BinarySidecar,
+	runner sidecar.SidecarRunner,
 	github service.GitHubService,
 ) error {
-	var (
-		runner sidecar.SidecarRunner
-		cfg    = sidecarCfg.Get()
-	)
-
-	// Determine which runner to use
-	switch cfg.RunMethod {
-	case config.RunMethod_RUN_METHOD_DOCKER:
-		runner = docker
-	case config.RunMethod_RUN_METHOD_SYSTEMD:
-		runner = systemd
-	case config.RunMethod_RUN_METHOD_BINARY:
-		runner = binary
-	default:
-		return fmt.Errorf("invalid sidecar run method: %s", cfg.RunMethod)
-	}
+	cfg := sidecarCfg.Get()
 
 	// Check version and show upgrade warning if needed.
-	current, latest, needsUpdate, err := sidecar.CheckVersion(runner, github, cfg.Version)
+	current, latest, needsUpdate, err := sidecar.CheckVersion(c.Context, runner, github, cfg.Version)
 	if err == nil && needsUpdate {
 		tui.UpgradeWarning(current, latest)
 	}
@@ -89,7 +67,7 @@ func restartContributoor(
 	fmt.Printf("%sRestarting Contributoor%s\n", tui.TerminalColorLightBlue, tui.TerminalColorReset)
 
 	// Check if running
-	running, err := runner.IsRunning()
+	running, err := runner.IsRunning(c.Context)
 	if err != nil {
 		log.Errorf("could not check sidecar status: %v", err)
 
@@ -98,7 +76,7 @@ func restartContributoor(
 
 	// Stop if running
 	if running {
-		if err := runner.Stop(); err != nil {
+		if err := runner.Stop(c.Context); err != nil {
 			return fmt.Errorf("failed to stop service: %w", err)
 		}
 	} else {
@@ -106,7 +84,7 @@ func restartContributoor(
 	}
 
 	// Start the service.
-	if err := runner.Start(); err != nil {
+	if err := runner.Start(c.Context); err != nil {
 		return fmt.Errorf("failed to start service: %w", err)
 	}
 
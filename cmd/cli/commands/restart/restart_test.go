@@ -31,22 +31,20 @@ func TestRestartContributoor(t *testing.T) {
 
 		// Create mock docker sidecar that's running
 		mockDocker := mock.NewMockDockerSidecar(ctrl)
-		mockDocker.EXPECT().Version().Return("1.0.0", nil)
-		mockDocker.EXPECT().IsRunning().Return(true, nil)
-		mockDocker.EXPECT().Stop().Return(nil)
-		mockDocker.EXPECT().Start().Return(nil)
+		mockDocker.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+		mockDocker.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+		mockDocker.EXPECT().Stop(gomock.Any()).Return(nil)
+		mockDocker.EXPECT().Start(gomock.Any()).Return(nil)
 
 		// Create mock GitHub service
 		mockGitHub := servicemock.NewMockGitHubService(ctrl)
-		mockGitHub.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
+		mockGitHub.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
 
 		err := restartContributoor(
 			cli.NewContext(nil, nil, nil),
 			logrus.New(),
 			mockConfig,
 			mockDocker,
-			mock.NewMockSystemdSidecar(ctrl),
-			mock.NewMockBinarySidecar(ctrl),
 			mockGitHub,
 		)
 
@@ -69,21 +67,19 @@ func TestRestartContributoor(t *testing.T) {
 
 		// Create mock systemd sidecar that's not running
 		mockSystemd := mock.NewMockSystemdSidecar(ctrl)
-		mockSystemd.EXPECT().Version().Return("1.0.0", nil)
-		mockSystemd.EXPECT().IsRunning().Return(false, nil)
-		mockSystemd.EXPECT().Start().Return(nil)
+		mockSystemd.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+		mockSystemd.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+		mockSystemd.EXPECT().Start(gomock.Any()).Return(nil)
 
 		// Create mock GitHub service
 		mockGitHub := servicemock.NewMockGitHubService(ctrl)
-		mockGitHub.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
+		mockGitHub.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
 
 		err := restartContributoor(
 			cli.NewContext(nil, nil, nil),
 			logrus.New(),
 			mockConfig,
-			mock.NewMockDockerSidecar(ctrl),
 			mockSystemd,
-			mock.NewMockBinarySidecar(ctrl),
 			mockGitHub,
 		)
 
@@ -105,20 +101,18 @@ func TestRestartContributoor(t *testing.T) {
 
 		// Create mock binary sidecar that fails to stop
 		mockBinary := mock.NewMockBinarySidecar(ctrl)
-		mockBinary.EXPECT().Version().Return("1.0.0", nil)
-		mockBinary.EXPECT().IsRunning().Return(true, nil)
-		mockBinary.EXPECT().Stop().Return(errors.New("test error"))
+		mockBinary.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+		mockBinary.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+		mockBinary.EXPECT().Stop(gomock.Any()).Return(errors.New("test error"))
 
 		// Create mock GitHub service
 		mockGitHub := servicemock.NewMockGitHubService(ctrl)
-		mockGitHub.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
+		mockGitHub.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
 
 		err := restartContributoor(
 			cli.NewContext(nil, nil, nil),
 			logrus.New(),
 			mockConfig,
-			mock.NewMockDockerSidecar(ctrl),
-			mock.NewMockSystemdSidecar(ctrl),
 			mockBinary,
 			mockGitHub,
 		)
@@ -142,20 +136,18 @@ func TestRestartContributoor(t *testing.T) {
 
 		// Create mock binary sidecar that fails to start
 		mockBinary := mock.NewMockBinarySidecar(ctrl)
-		mockBinary.EXPECT().Version().Return("1.0.0", nil)
-		mockBinary.EXPECT().IsRunning().Return(false, nil)
-		mockBinary.EXPECT().Start().Return(errors.New("test error"))
+		mockBinary.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+		mockBinary.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+		mockBinary.EXPECT().Start(gomock.Any()).Return(errors.New("test error"))
 
 		// Create mock GitHub service
 		mockGitHub := servicemock.NewMockGitHubService(ctrl)
-		mockGitHub.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
+		mockGitHub.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
 
 		err := restartContributoor(
 			cli.NewContext(nil, nil, nil),
 			logrus.New(),
 			mockConfig,
-			mock.NewMockDockerSidecar(ctrl),
-			mock.NewMockSystemdSidecar(ctrl),
 			mockBinary,
 			mockGitHub,
 		)
@@ -164,33 +156,6 @@ func TestRestartContributoor(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to start service")
 	})
 
-	t.Run("handles invalid run method", func(t *testing.T) {
-		cleanup := test.SuppressOutput(t)
-		defer cleanup()
-
-		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
-
-		mockConfig := mock.NewMockConfigManager(ctrl)
-		mockConfig.EXPECT().Get().Return(&config.Config{
-			RunMethod: config.RunMethod_RUN_METHOD_UNSPECIFIED,
-			Version:   "latest",
-		}).AnyTimes()
-
-		err := restartContributoor(
-			cli.NewContext(nil, nil, nil),
-			logrus.New(),
-			mockConfig,
-			mock.NewMockDockerSidecar(ctrl),
-			mock.NewMockSystemdSidecar(ctrl),
-			mock.NewMockBinarySidecar(ctrl),
-			servicemock.NewMockGitHubService(ctrl),
-		)
-
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid sidecar run method")
-	})
-
 	t.Run("handles github error gracefully", func(t *testing.T) {
 		cleanup := test.SuppressOutput(t)
 		defer cleanup()
@@ -205,21 +170,19 @@ func TestRestartContributoor(t *testing.T) {
 		}).AnyTimes()
 
 		mockDocker := mock.NewMockDockerSidecar(ctrl)
-		mockDocker.EXPECT().IsRunning().Return(true, nil)
-		mockDocker.EXPECT().Stop().Return(nil)
-		mockDocker.EXPECT().Start().Return(nil)
+		mockDocker.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+		mockDocker.EXPECT().Stop(gomock.Any()).Return(nil)
+		mockDocker.EXPECT().Start(gomock.Any()).Return(nil)
 
 		// Create mock GitHub service that returns an error
 		mockGitHub := servicemock.NewMockGitHubService(ctrl)
-		mockGitHub.EXPECT().GetLatestVersion().Return("", errors.New("github error"))
+		mockGitHub.EXPECT().GetLatestVersion(gomock.Any()).Return("", errors.New("github error"))
 
 		err := restartContributoor(
 			cli.NewContext(nil, nil, nil),
 			logrus.New(),
 			mockConfig,
 			mockDocker,
-			mock.NewMockSystemdSidecar(ctrl),
-			mock.NewMockBinarySidecar(ctrl),
 			mockGitHub,
 		)
 
@@ -7,7 +7,6 @@ import (
 	"github.com/ethpandaops/contributoor-installer/internal/service"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
-	"github.com/ethpandaops/contributoor/pkg/config/v1"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
@@ -18,30 +17,26 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 		Aliases:   opts.Aliases(),
 		Usage:     "Start Contributoor",
 		UsageText: "contributoor start [options]",
+		Flags:     []cli.Flag{options.InstanceFlag()},
 		Action: func(c *cli.Context) error {
 			var (
 				log          = opts.Logger()
 				installerCfg = opts.InstallerConfig()
 			)
 
-			sidecarCfg, err := sidecar.NewConfigService(log, c.String("config-path"))
+			configPath, err := opts.ConfigPath(c)
 			if err != nil {
 				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			dockerSidecar, err := sidecar.NewDockerSidecar(log, sidecarCfg, installerCfg)
+			sidecarCfg, err := sidecar.NewConfigService(log, configPath)
 			if err != nil {
-				return fmt.Errorf("error creating docker sidecar service: %w", err)
-			}
-
-			systemdSidecar, err := sidecar.NewSystemdSidecar(log, sidecarCfg, installerCfg)
-			if err != nil {
-				return fmt.Errorf("error creating systemd sidecar service: %w", err)
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			binarySidecar, err := sidecar.NewBinarySidecar(log, sidecarCfg, installerCfg)
+			runner, err := sidecar.ResolveRunner(log, sidecarCfg, installerCfg)
 			if err != nil {
-				return fmt.Errorf("error creating binary sidecar service: %w", err)
+				return err
 			}
 
 			githubService, err := service.NewGitHubService(log, installerCfg)
@@ -49,7 +44,7 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 				return fmt.Errorf("error creating github service: %w", err)
 			}
 
-			return startContributoor(c, log, sidecarCfg, dockerSidecar, systemdSidecar, binarySidecar, githubService)
+			return startContributoor(c, log, sidecarCfg, runner, githubService)
 		},
 	})
 }
@@ -58,30 +53,13 @@ func startContributoor(
 	c *cli.Context,
 	log *logrus.Logger,
 	sidecarCfg sidecar.ConfigManager,
-	docker sidecar.DockerSidecar,
-	systemd sidecar.SystemdSidecar,
-	binary sidecar.BinarySidecar,
+	runner sidecar.SidecarRunner,
 	github service.GitHubService,
 ) error {
-	var (
-		runner sidecar.SidecarRunner
-		cfg    = sidecarCfg.Get()
-	)
-
-	// Start the sidecar via whatever method the user has configured (docker or binary).
-	switch cfg.RunMethod {
-	case config.RunMethod_RUN_METHOD_DOCKER:
-		runner = docker
-	case config.RunMethod_RUN_METHOD_SYSTEMD:
-		runner = systemd
-	case config.RunMethod_RUN_METHOD_BINARY:
-		runner = binary
-	default:
-		return fmt.Errorf("invalid sidecar run method: %s", cfg.RunMethod)
-	}
+	cfg := sidecarCfg.Get()
 
 	// Check version and show upgrade warning if needed.
-	current, latest, needsUpdate, err := sidecar.CheckVersion(runner, github, cfg.Version)
+	current, latest, needsUpdate, err := sidecar.CheckVersion(c.Context, runner, github, cfg.Version)
 	if err == nil && needsUpdate {
 		tui.UpgradeWarning(current, latest)
 	}
@@ -89,7 +67,7 @@ func startContributoor(
 	fmt.Printf("%sStarting Contributoor%s\n", tui.TerminalColorLightBlue, tui.TerminalColorReset)
 
 	// Check if the sidecar is already running.
-	running, err := runner.IsRunning()
+	running, err := runner.IsRunning(c.Context)
 	if err != nil {
 		log.Errorf("could not check sidecar status: %v", err)
 
@@ -103,7 +81,7 @@ func startContributoor(
 		return nil
 	}
 
-	if err := runner.Start(); err != nil {
+	if err := runner.Start(c.Context); err != nil {
 		return err
 	}
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
 	servicemock "github.com/ethpandaops/contributoor-installer/internal/service/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	sidecarmock "github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
 	"github.com/ethpandaops/contributoor-installer/internal/test"
 	"github.com/ethpandaops/contributoor/pkg/config/v1"
@@ -35,10 +36,10 @@ func TestStartContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
 					Version:   "latest",
 				}).Times(1)
-				gh.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
-				d.EXPECT().Version().Return("1.0.0", nil)
-				d.EXPECT().IsRunning().Return(false, nil)
-				d.EXPECT().Start().Return(nil)
+				gh.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
+				d.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				d.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				d.EXPECT().Start(gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -49,9 +50,9 @@ func TestStartContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
 					Version:   "latest",
 				}).Times(1)
-				gh.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
-				d.EXPECT().Version().Return("1.0.0", nil)
-				d.EXPECT().IsRunning().Return(true, nil)
+				gh.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
+				d.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				d.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
 			},
 		},
 		{
@@ -62,10 +63,10 @@ func TestStartContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
 					Version:   "latest",
 				}).Times(1)
-				gh.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
-				d.EXPECT().Version().Return("1.0.0", nil)
-				d.EXPECT().IsRunning().Return(false, nil)
-				d.EXPECT().Start().Return(errors.New("start failed"))
+				gh.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
+				d.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				d.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				d.EXPECT().Start(gomock.Any()).Return(errors.New("start failed"))
 			},
 			expectedError: "start failed",
 		},
@@ -77,10 +78,10 @@ func TestStartContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_BINARY,
 					Version:   "latest",
 				}).Times(1)
-				gh.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
-				b.EXPECT().Version().Return("1.0.0", nil)
-				b.EXPECT().IsRunning().Return(false, nil)
-				b.EXPECT().Start().Return(nil)
+				gh.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
+				b.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				b.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				b.EXPECT().Start(gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -91,22 +92,11 @@ func TestStartContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_BINARY,
 					Version:   "latest",
 				}).Times(1)
-				gh.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
-				b.EXPECT().Version().Return("1.0.0", nil)
-				b.EXPECT().IsRunning().Return(true, nil)
+				gh.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
+				b.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				b.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
 			},
 		},
-		{
-			name:      "invalid sidecar run method",
-			runMethod: config.RunMethod_RUN_METHOD_UNSPECIFIED,
-			setupMocks: func(cfg *sidecarmock.MockConfigManager, d *sidecarmock.MockDockerSidecar, b *sidecarmock.MockBinarySidecar, gh *servicemock.MockGitHubService) {
-				cfg.EXPECT().Get().Return(&config.Config{
-					RunMethod: config.RunMethod_RUN_METHOD_UNSPECIFIED,
-					Version:   "latest",
-				}).Times(1)
-			},
-			expectedError: "invalid sidecar run method",
-		},
 	}
 
 	for _, tt := range tests {
@@ -125,7 +115,13 @@ func TestStartContributoor(t *testing.T) {
 			app := cli.NewApp()
 			ctx := cli.NewContext(app, nil, nil)
 
-			err := startContributoor(ctx, logrus.New(), mockConfig, mockDocker, mockSystemd, mockBinary, mockGitHub)
+			runner := map[config.RunMethod]sidecar.SidecarRunner{
+				config.RunMethod_RUN_METHOD_DOCKER:  mockDocker,
+				config.RunMethod_RUN_METHOD_SYSTEMD: mockSystemd,
+				config.RunMethod_RUN_METHOD_BINARY:  mockBinary,
+			}[tt.runMethod]
+
+			err := startContributoor(ctx, logrus.New(), mockConfig, runner, mockGitHub)
 
 			if tt.expectedError != "" {
 				assert.ErrorContains(t, err, tt.expectedError)
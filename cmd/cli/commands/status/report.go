@@ -0,0 +1,249 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/contributoor-installer/internal/doctor"
+	"github.com/ethpandaops/contributoor-installer/internal/service"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/validate"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported values for the --output flag.
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// statusReport is the machine-readable form of `contributoor status`.
+type statusReport struct {
+	Version          string              `json:"version"                 yaml:"version"`
+	LatestVersion    string              `json:"latestVersion,omitempty" yaml:"latestVersion,omitempty"`
+	NeedsUpdate      bool                `json:"needsUpdate"             yaml:"needsUpdate"`
+	VersionError     string              `json:"versionError,omitempty"  yaml:"versionError,omitempty"`
+	RunMethod        string              `json:"runMethod"               yaml:"runMethod"`
+	SystemdScope     string              `json:"systemdScope,omitempty"  yaml:"systemdScope,omitempty"`
+	Running          bool                `json:"running"                 yaml:"running"`
+	Status           *runnerStatusReport `json:"status"                  yaml:"status"`
+	ConfigPath       string              `json:"configPath"              yaml:"configPath"`
+	OutputServer     string              `json:"outputServer,omitempty"  yaml:"outputServer,omitempty"`
+	AttestationOptIn bool                `json:"attestationOptIn"        yaml:"attestationOptIn"`
+	BeaconNodes      []beaconNodeReport  `json:"beaconNodes"             yaml:"beaconNodes"`
+	Orphans          []string            `json:"orphans,omitempty"       yaml:"orphans,omitempty"`
+	KnownIssues      []knownIssueReport  `json:"knownIssues,omitempty"   yaml:"knownIssues,omitempty"`
+}
+
+// runnerStatusReport describes the service as reported by its run method.
+type runnerStatusReport struct {
+	State         sidecar.State `json:"state"                 yaml:"state"`
+	Detail        string        `json:"detail"                yaml:"detail"`
+	PID           int           `json:"pid,omitempty"         yaml:"pid,omitempty"`
+	ContainerID   string        `json:"containerId,omitempty" yaml:"containerId,omitempty"`
+	StartedAt     *time.Time    `json:"startedAt,omitempty"   yaml:"startedAt,omitempty"`
+	Uptime        string        `json:"uptime,omitempty"      yaml:"uptime,omitempty"`
+	UptimeSeconds int64         `json:"uptimeSeconds"         yaml:"uptimeSeconds"`
+	RestartCount  int           `json:"restartCount"          yaml:"restartCount"`
+	ExitCode      int           `json:"exitCode"              yaml:"exitCode"`
+}
+
+// knownIssueReport is a known error found in the sidecar's recent logs.
+type knownIssueReport struct {
+	Rule     string     `json:"rule"               yaml:"rule"`
+	Message  string     `json:"message"            yaml:"message"`
+	Hint     string     `json:"hint"               yaml:"hint"`
+	Count    int        `json:"count"              yaml:"count"`
+	LastSeen *time.Time `json:"lastSeen,omitempty" yaml:"lastSeen,omitempty"`
+}
+
+// beaconNodeReport describes a single configured beacon node. Nodes which aren't
+// reachable from this host (eg: docker network hostnames) are listed but not checked.
+type beaconNodeReport struct {
+	Address  string          `json:"address"            yaml:"address"`
+	Checked  bool            `json:"checked"            yaml:"checked"`
+	Network  string          `json:"network,omitempty"  yaml:"network,omitempty"`
+	Identity *identityReport `json:"identity,omitempty" yaml:"identity,omitempty"`
+	Sync     *syncReport     `json:"sync,omitempty"     yaml:"sync,omitempty"`
+	Health   *healthReport   `json:"health,omitempty"   yaml:"health,omitempty"`
+	Error    string          `json:"error,omitempty"    yaml:"error,omitempty"`
+}
+
+type identityReport struct {
+	PeerID             string   `json:"peerId"             yaml:"peerId"`
+	ENR                string   `json:"enr"                yaml:"enr"`
+	P2PAddresses       []string `json:"p2pAddresses"       yaml:"p2pAddresses"`
+	DiscoveryAddresses []string `json:"discoveryAddresses" yaml:"discoveryAddresses"`
+	SeqNumber          string   `json:"seqNumber"          yaml:"seqNumber"`
+	Attnets            string   `json:"attnets"            yaml:"attnets"`
+	Syncnets           string   `json:"syncnets"           yaml:"syncnets"`
+}
+
+type syncReport struct {
+	HeadSlot     string `json:"headSlot"     yaml:"headSlot"`
+	SyncDistance string `json:"syncDistance" yaml:"syncDistance"`
+	IsSyncing    bool   `json:"isSyncing"    yaml:"isSyncing"`
+	IsOptimistic bool   `json:"isOptimistic" yaml:"isOptimistic"`
+	ELOffline    bool   `json:"elOffline"    yaml:"elOffline"`
+}
+
+type healthReport struct {
+	StatusCode int  `json:"statusCode" yaml:"statusCode"`
+	IsHealthy  bool `json:"isHealthy"  yaml:"isHealthy"`
+	IsSyncing  bool `json:"isSyncing"  yaml:"isSyncing"`
+}
+
+func newRunnerStatusReport(status *sidecar.Status) *runnerStatusReport {
+	report := &runnerStatusReport{
+		State:         status.State,
+		Detail:        status.Detail,
+		PID:           status.PID,
+		ContainerID:   status.ContainerID,
+		UptimeSeconds: int64(status.Uptime.Seconds()),
+		RestartCount:  status.RestartCount,
+		ExitCode:      status.ExitCode,
+	}
+
+	if !status.StartedAt.IsZero() {
+		startedAt := status.StartedAt
+		report.StartedAt = &startedAt
+	}
+
+	if status.Uptime > 0 {
+		report.Uptime = status.Uptime.String()
+	}
+
+	return report
+}
+
+func newKnownIssueReports(findings []doctor.LogFinding) []knownIssueReport {
+	issues := make([]knownIssueReport, 0, len(findings))
+
+	for _, finding := range findings {
+		issue := knownIssueReport{
+			Rule:    finding.Rule,
+			Message: finding.Message,
+			Hint:    finding.Hint,
+			Count:   finding.Count,
+		}
+
+		if !finding.LastSeen.IsZero() {
+			lastSeen := finding.LastSeen
+			issue.LastSeen = &lastSeen
+		}
+
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// validateOutputFormat checks the --output flag value.
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputText, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q, must be one of: %s, %s, %s", format, outputText, outputJSON, outputYAML)
+	}
+}
+
+// collectBeaconNodes fetches info for each configured beacon node reachable from this host.
+func collectBeaconNodes(
+	ctx context.Context,
+	addresses string,
+	newBeaconService func(address string) service.BeaconService,
+) []beaconNodeReport {
+	nodes := make([]beaconNodeReport, 0)
+
+	for address := range strings.SplitSeq(addresses, ",") {
+		address = strings.TrimSpace(address)
+		if address == "" {
+			continue
+		}
+
+		node := beaconNodeReport{Address: address}
+
+		if validate.IsLocalhostAddress(address) {
+			node.Checked = true
+			node.setInfo(newBeaconService(address).GetBeaconInfo(ctx))
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+func (n *beaconNodeReport) setInfo(info *service.BeaconInfo) {
+	if info.Error != nil {
+		n.Error = info.Error.Error()
+
+		return
+	}
+
+	n.Network = info.Network
+
+	if info.Identity != nil {
+		n.Identity = &identityReport{
+			PeerID:             info.Identity.PeerID,
+			ENR:                info.Identity.ENR,
+			P2PAddresses:       info.Identity.P2PAddresses,
+			DiscoveryAddresses: info.Identity.DiscoveryAddresses,
+			SeqNumber:          info.Identity.Metadata.SeqNumber,
+			Attnets:            info.Identity.Metadata.Attnets,
+			Syncnets:           info.Identity.Metadata.Syncnets,
+		}
+	}
+
+	if info.Sync != nil {
+		n.Sync = &syncReport{
+			HeadSlot:     info.Sync.HeadSlot,
+			SyncDistance: info.Sync.SyncDistance,
+			IsSyncing:    info.Sync.IsSyncing,
+			IsOptimistic: info.Sync.IsOptimistic,
+			ELOffline:    info.Sync.ELOffline,
+		}
+	}
+
+	if info.Health != nil {
+		n.Health = &healthReport{
+			StatusCode: info.Health.StatusCode,
+			IsHealthy:  info.Health.IsHealthy,
+			IsSyncing:  info.Health.IsSyncing,
+		}
+	}
+}
+
+// writeReport encodes the report to w in the given structured format.
+func writeReport(w io.Writer, format string, report *statusReport) error {
+	switch format {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode status: %w", err)
+		}
+	case outputYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode status: %w", err)
+		}
+
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("failed to encode status: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+
+	return nil
+}
@@ -3,9 +3,11 @@ package status
 import (
 	"context"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
+	"github.com/ethpandaops/contributoor-installer/internal/doctor"
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
 	"github.com/ethpandaops/contributoor-installer/internal/service"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
@@ -22,30 +24,38 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 		Aliases:   opts.Aliases(),
 		Usage:     "Show Contributoor status",
 		UsageText: "contributoor status [options]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output format: text, json or yaml",
+				Value:   outputText,
+			},
+			&cli.BoolFlag{
+				Name:  "cleanup-orphans",
+				Usage: "Stop and remove sidecars running under a run method other than the configured one",
+			},
+			options.InstanceFlag(),
+		},
 		Action: func(c *cli.Context) error {
 			var (
 				log          = opts.Logger()
 				installerCfg = opts.InstallerConfig()
 			)
 
-			sidecarCfg, err := sidecar.NewConfigService(log, c.String("config-path"))
+			configPath, err := opts.ConfigPath(c)
 			if err != nil {
 				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			dockerSidecar, err := sidecar.NewDockerSidecar(log, sidecarCfg, installerCfg)
-			if err != nil {
-				return fmt.Errorf("error creating docker sidecar service: %w", err)
-			}
-
-			systemdSidecar, err := sidecar.NewSystemdSidecar(log, sidecarCfg, installerCfg)
+			sidecarCfg, err := sidecar.NewConfigService(log, configPath)
 			if err != nil {
-				return fmt.Errorf("error creating systemd sidecar service: %w", err)
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			binarySidecar, err := sidecar.NewBinarySidecar(log, sidecarCfg, installerCfg)
+			runner, err := sidecar.ResolveRunner(log, sidecarCfg, installerCfg)
 			if err != nil {
-				return fmt.Errorf("error creating binary sidecar service: %w", err)
+				return err
 			}
 
 			githubService, err := service.NewGitHubService(log, installerCfg)
@@ -53,7 +63,9 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 				return fmt.Errorf("error creating github service: %w", err)
 			}
 
-			return showStatus(c, log, sidecarCfg, dockerSidecar, systemdSidecar, binarySidecar, githubService)
+			orphans := sidecar.FindOrphans(c.Context, log, sidecarCfg, installerCfg)
+
+			return showStatus(c, log, sidecarCfg, installerCfg, runner, githubService, orphans)
 		},
 	})
 }
@@ -62,130 +74,271 @@ func showStatus(
 	c *cli.Context,
 	log *logrus.Logger,
 	sidecarCfg sidecar.ConfigManager,
-	docker sidecar.DockerSidecar,
-	systemd sidecar.SystemdSidecar,
-	binary sidecar.BinarySidecar,
+	installerCfg *installer.Config,
+	runner sidecar.SidecarRunner,
 	github service.GitHubService,
+	orphans []sidecar.Orphan,
 ) error {
 	var (
-		runner sidecar.SidecarRunner
 		cfg    = sidecarCfg.Get()
+		format = c.String("output")
 	)
 
-	// Determine which runner to use.
-	switch cfg.RunMethod {
-	case config.RunMethod_RUN_METHOD_DOCKER:
-		runner = docker
-	case config.RunMethod_RUN_METHOD_SYSTEMD:
-		runner = systemd
-	case config.RunMethod_RUN_METHOD_BINARY:
-		runner = binary
-	default:
-		return fmt.Errorf("invalid sidecar run method: %s", cfg.RunMethod)
-	}
-
-	// Check version and show upgrade warning if needed.
-	current, latest, needsUpdate, err := sidecar.CheckVersion(runner, github, cfg.Version)
-	if err == nil && needsUpdate {
-		tui.UpgradeWarning(current, latest)
+	if format == "" {
+		format = outputText
+	}
+
+	if err := validateOutputFormat(format); err != nil {
+		return err
+	}
+
+	if c.Bool("cleanup-orphans") {
+		// Stopping a sidecar reports progress, which would corrupt structured documents.
+		if format != outputText {
+			return fmt.Errorf("--cleanup-orphans can't be combined with --output %s", format)
+		}
+
+		orphans = cleanupOrphans(c, orphans)
+	}
+
+	report := &statusReport{
+		RunMethod:        cfg.RunMethod.String(),
+		ConfigPath:       sidecarCfg.GetConfigPath(),
+		AttestationOptIn: cfg.AttestationSubnetCheck != nil && cfg.AttestationSubnetCheck.Enabled,
+	}
+
+	// The systemd run method runs as a system or user unit.
+	if cfg.RunMethod == config.RunMethod_RUN_METHOD_SYSTEMD {
+		report.SystemdScope = installerCfg.Systemd.Scope
 	}
 
-	// Check if running.
-	running, err := runner.IsRunning()
+	if cfg.OutputServer != nil {
+		report.OutputServer = cfg.OutputServer.Address
+	}
+
+	// Check version, the upgrade warning is only shown for text output so it
+	// doesn't corrupt structured documents.
+	current, latest, needsUpdate, err := sidecar.CheckVersion(c.Context, runner, github, cfg.Version)
 	if err != nil {
-		return fmt.Errorf("failed to check status: %w", err)
+		report.VersionError = err.Error()
+	}
+
+	report.Version = current
+	report.LatestVersion = latest
+	report.NeedsUpdate = needsUpdate
+
+	if format == outputText && err == nil && needsUpdate {
+		tui.UpgradeWarning(current, latest)
 	}
 
 	// Get the underlying status from the sidecar.
-	status, err := runner.Status()
+	status, err := runner.Status(c.Context)
 	if err != nil {
 		return fmt.Errorf("failed to get status: %w", err)
 	}
 
-	// Print status information.
+	report.Running = status.IsRunning()
+	report.Status = newRunnerStatusReport(status)
+
+	for _, orphan := range orphans {
+		report.Orphans = append(report.Orphans, orphan.Name())
+	}
+
+	// Logs which can't be read, eg: before the sidecar is first started, have nothing
+	// to report.
+	if findings, err := doctor.ScanLogs(c.Context, runner, cfg); err == nil {
+		report.KnownIssues = newKnownIssueReports(findings)
+	}
+
+	// Fetch beacon node information if configured.
+	report.BeaconNodes = collectBeaconNodes(c.Context, cfg.BeaconNodeAddress, func(address string) service.BeaconService {
+		return service.NewBeaconService(log, address)
+	})
+
+	if format != outputText {
+		return writeReport(c.App.Writer, format, report)
+	}
+
+	printStatus(report, sidecar.RunMode{Method: cfg.RunMethod, Scope: report.SystemdScope}.Name(), cfg.BeaconNodeAddress)
+
+	return nil
+}
+
+// printStatus prints the human readable status.
+func printStatus(report *statusReport, runMethod, beaconNodeAddress string) {
 	fmt.Printf("%sContributoor Status%s\n", tui.TerminalColorLightBlue, tui.TerminalColorReset)
-	fmt.Printf("%-20s: %s\n", "Version", current)
-	fmt.Printf("%-20s: %s\n", "Run Method", cfg.RunMethod)
-	fmt.Printf("%-20s: %s\n", "Beacon Node", cfg.BeaconNodeAddress)
-	fmt.Printf("%-20s: %s\n", "Config Path", sidecarCfg.GetConfigPath())
+	fmt.Printf("%-20s: %s\n", "Version", report.Version)
+	fmt.Printf("%-20s: %s\n", "Run Method", runMethod)
+	fmt.Printf("%-20s: %s\n", "Beacon Node", beaconNodeAddress)
+	fmt.Printf("%-20s: %s\n", "Config Path", report.ConfigPath)
 
-	if cfg.OutputServer != nil {
-		fmt.Printf("%-20s: %s\n", "Output Server", cfg.OutputServer.Address)
+	if report.OutputServer != "" {
+		fmt.Printf("%-20s: %s\n", "Output Server", report.OutputServer)
 	}
 
-	fmt.Printf(
-		"%-20s: %v\n", "Opt-in Attestations",
-		cfg.AttestationSubnetCheck != nil && cfg.AttestationSubnetCheck.Enabled,
-	)
+	fmt.Printf("%-20s: %v\n", "Opt-in Attestations", report.AttestationOptIn)
 
 	// Print running status with color.
 	statusColor := tui.TerminalColorRed
-	statusText := cases.Title(language.English).String(status)
+	statusText := cases.Title(language.English).String(report.Status.State.String())
 
-	if running {
+	switch report.Status.State {
+	case sidecar.StateRunning:
 		statusColor = tui.TerminalColorGreen
+	case sidecar.StateStarting, sidecar.StateRestarting:
+		statusColor = tui.TerminalColorYellow
+	}
+
+	if report.Status.Detail != "" && report.Status.Detail != report.Status.State.String() {
+		statusText = fmt.Sprintf("%s (%s)", statusText, report.Status.Detail)
 	}
 
 	fmt.Printf("%-20s: %s%s%s\n", "Status", statusColor, statusText, tui.TerminalColorReset)
 
-	// Print beacon node information if configured.
-	if cfg.BeaconNodeAddress != "" {
-		printBeaconNodeInfo(c.Context, log, cfg.BeaconNodeAddress)
+	if report.Status.ContainerID != "" {
+		fmt.Printf("%-20s: %.12s\n", "Container ID", report.Status.ContainerID)
 	}
 
-	return nil
+	if report.Status.PID != 0 {
+		fmt.Printf("%-20s: %d\n", "PID", report.Status.PID)
+	}
+
+	if report.Status.Uptime != "" {
+		fmt.Printf("%-20s: %s\n", "Uptime", report.Status.Uptime)
+	}
+
+	if report.Status.RestartCount > 0 {
+		fmt.Printf("%-20s: %d\n", "Restarts", report.Status.RestartCount)
+	}
+
+	if report.Status.State == sidecar.StateFailed {
+		fmt.Printf("%-20s: %d\n", "Exit Code", report.Status.ExitCode)
+	}
+
+	for _, orphan := range report.Orphans {
+		fmt.Printf(
+			"%-20s: %s%s is also running, run 'contributoor status --cleanup-orphans' to stop it%s\n",
+			"Orphaned Sidecar",
+			tui.TerminalColorYellow,
+			orphan,
+			tui.TerminalColorReset,
+		)
+	}
+
+	for _, issue := range report.KnownIssues {
+		seen := fmt.Sprintf("seen %d times", issue.Count)
+		if issue.Count == 1 {
+			seen = "seen once"
+		}
+
+		if issue.LastSeen != nil {
+			seen += ", last at " + issue.LastSeen.Local().Format(time.DateTime)
+		}
+
+		fmt.Printf("%-20s: %s%s (%s)%s\n", "Known Issue", tui.TerminalColorRed, issue.Message, seen, tui.TerminalColorReset)
+		fmt.Printf("%-20s  ↳ %s\n", "", issue.Hint)
+	}
+
+	printBeaconNodeInfo(report.BeaconNodes)
 }
 
-func printBeaconNodeInfo(ctx context.Context, log *logrus.Logger, addresses string) {
-	nodes := strings.Split(addresses, ",")
+// cleanupOrphans stops and removes the orphans the user confirms, returning any left
+// running.
+func cleanupOrphans(c *cli.Context, orphans []sidecar.Orphan) []sidecar.Orphan {
+	var remaining []sidecar.Orphan
+
+	for _, orphan := range orphans {
+		steps := sidecar.OrphanCleanupSteps(orphan)
+
+		fmt.Printf("%sCleaning up the orphaned %s sidecar will:%s\n", tui.TerminalColorRed, orphan.Name(), tui.TerminalColorReset)
+
+		for _, step := range steps {
+			fmt.Printf(" • %s\n", step.Description)
+		}
+
+		if !tui.Confirm("\nAre you sure you want to clean it up?") {
+			fmt.Printf("Left the orphaned %s sidecar running\n\n", orphan.Name())
+
+			remaining = append(remaining, orphan)
 
-	for i, address := range nodes {
-		address = strings.TrimSpace(address)
-		if address == "" {
 			continue
 		}
 
-		// Skip status check for non-localhost addresses (e.g., Docker network hostnames).
-		// These are not reachable from the host machine where the CLI runs.
-		if !isLocalhostAddress(address) {
+		if err := runCleanupSteps(c.Context, steps); err != nil {
+			fmt.Printf(
+				"%sFailed to clean up orphaned %s sidecar: %v%s\n",
+				tui.TerminalColorRed,
+				orphan.Name(),
+				err,
+				tui.TerminalColorReset,
+			)
+
+			remaining = append(remaining, orphan)
+
 			continue
 		}
 
-		beaconSvc := service.NewBeaconService(log, address)
-		info := beaconSvc.GetBeaconInfo(ctx)
+		fmt.Printf("%sCleaned up orphaned %s sidecar%s\n", tui.TerminalColorGreen, orphan.Name(), tui.TerminalColorReset)
+	}
+
+	if len(orphans) > 0 {
+		fmt.Println()
+	}
+
+	return remaining
+}
+
+// runCleanupSteps runs the steps in order, stopping at the first which fails.
+func runCleanupSteps(ctx context.Context, steps []sidecar.UninstallStep) error {
+	for _, step := range steps {
+		if err := step.Run(ctx); err != nil {
+			return fmt.Errorf("%s: %w", step.Description, err)
+		}
+	}
+
+	return nil
+}
+
+func printBeaconNodeInfo(nodes []beaconNodeReport) {
+	for i, node := range nodes {
+		// Nodes not reachable from the host machine (e.g., Docker network hostnames)
+		// aren't checked.
+		if !node.Checked {
+			continue
+		}
 
 		fmt.Println()
 
 		// Show header with node number if multiple nodes.
 		if len(nodes) > 1 {
 			fmt.Printf("%sBeacon Node %d Status%s (%s)\n",
-				tui.TerminalColorLightBlue, i+1, tui.TerminalColorReset, address)
+				tui.TerminalColorLightBlue, i+1, tui.TerminalColorReset, node.Address)
 		} else {
 			fmt.Printf("%sBeacon Node Status%s\n", tui.TerminalColorLightBlue, tui.TerminalColorReset)
 		}
 
 		// Show error if we couldn't connect.
-		if info.Error != nil {
+		if node.Error != "" {
 			fmt.Printf("%-20s: %s%s%s\n", "Status", tui.TerminalColorRed, "Unreachable", tui.TerminalColorReset)
-			fmt.Printf("%-20s: %s\n", "Error", info.Error.Error())
+			fmt.Printf("%-20s: %s\n", "Error", node.Error)
 
 			continue
 		}
 
 		// Network.
-		if info.Network != "" {
-			fmt.Printf("%-20s: %s\n", "Network", info.Network)
+		if node.Network != "" {
+			fmt.Printf("%-20s: %s\n", "Network", node.Network)
 		}
 
 		// Health status.
-		if info.Health != nil {
+		if node.Health != nil {
 			healthColor := tui.TerminalColorGreen
 			healthText := "Healthy"
 
-			if info.Health.IsSyncing {
+			if node.Health.IsSyncing {
 				healthColor = tui.TerminalColorYellow
 				healthText = "Syncing"
-			} else if !info.Health.IsHealthy {
+			} else if !node.Health.IsHealthy {
 				healthColor = tui.TerminalColorRed
 				healthText = "Unhealthy"
 			}
@@ -194,16 +347,16 @@ func printBeaconNodeInfo(ctx context.Context, log *logrus.Logger, addresses stri
 		}
 
 		// Sync status.
-		if info.Sync != nil {
+		if node.Sync != nil {
 			syncColor := tui.TerminalColorGreen
 			syncText := "Synced"
 
-			if info.Sync.IsSyncing {
+			if node.Sync.IsSyncing {
 				syncColor = tui.TerminalColorYellow
-				syncText = fmt.Sprintf("Syncing (head: %s, distance: %s)", info.Sync.HeadSlot, info.Sync.SyncDistance)
+				syncText = fmt.Sprintf("Syncing (head: %s, distance: %s)", node.Sync.HeadSlot, node.Sync.SyncDistance)
 			}
 
-			if info.Sync.ELOffline {
+			if node.Sync.ELOffline {
 				syncColor = tui.TerminalColorRed
 				syncText = "Execution Layer Offline"
 			}
@@ -212,8 +365,8 @@ func printBeaconNodeInfo(ctx context.Context, log *logrus.Logger, addresses stri
 		}
 
 		// Peer ID (truncated for readability).
-		if info.Identity != nil && info.Identity.PeerID != "" {
-			peerID := info.Identity.PeerID
+		if node.Identity != nil && node.Identity.PeerID != "" {
+			peerID := node.Identity.PeerID
 			if len(peerID) > 20 {
 				peerID = peerID[:10] + "..." + peerID[len(peerID)-10:]
 			}
@@ -222,12 +375,3 @@ func printBeaconNodeInfo(ctx context.Context, log *logrus.Logger, addresses stri
 		}
 	}
 }
-
-// isLocalhostAddress checks if the address points to localhost.
-// Non-localhost addresses (e.g., Docker network hostnames) are not reachable from the host.
-func isLocalhostAddress(address string) bool {
-	host := strings.TrimPrefix(strings.TrimPrefix(address, "http://"), "https://")
-	host = strings.Split(host, ":")[0]
-
-	return strings.HasPrefix(host, "127.0.0.1") || strings.HasPrefix(host, "localhost")
-}
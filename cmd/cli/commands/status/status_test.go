@@ -1,17 +1,31 @@
 package status
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
 	servicemock "github.com/ethpandaops/contributoor-installer/internal/service/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
 	"github.com/ethpandaops/contributoor-installer/internal/test"
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
 	"github.com/ethpandaops/contributoor/pkg/config/v1"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/mock/gomock"
+	"gopkg.in/yaml.v3"
 )
 
 func TestShowStatus(t *testing.T) {
@@ -34,10 +48,10 @@ func TestShowStatus(t *testing.T) {
 					BeaconNodeAddress: "http://test:4444",
 				}).AnyTimes()
 				cfg.EXPECT().GetConfigPath().Return("/test/config.yaml")
-				g.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
-				d.EXPECT().Version().Return("1.0.0", nil)
-				d.EXPECT().IsRunning().Return(true, nil)
-				d.EXPECT().Status().Return("running", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
+				d.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				d.EXPECT().Status(gomock.Any()).Return(&sidecar.Status{State: sidecar.StateRunning, Detail: "running"}, nil)
+				d.EXPECT().Logs(gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -50,10 +64,10 @@ func TestShowStatus(t *testing.T) {
 					BeaconNodeAddress: "http://test:4444",
 				}).AnyTimes()
 				cfg.EXPECT().GetConfigPath().Return("/test/config.yaml")
-				g.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
-				b.EXPECT().Version().Return("1.0.0", nil)
-				b.EXPECT().IsRunning().Return(true, nil)
-				b.EXPECT().Status().Return("running", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
+				b.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				b.EXPECT().Status(gomock.Any()).Return(&sidecar.Status{State: sidecar.StateRunning, Detail: "running"}, nil)
+				b.EXPECT().Logs(gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -66,23 +80,12 @@ func TestShowStatus(t *testing.T) {
 					BeaconNodeAddress: "http://test:4444",
 				}).AnyTimes()
 				cfg.EXPECT().GetConfigPath().Return("/test/config.yaml")
-				g.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
-				s.EXPECT().Version().Return("1.0.0", nil)
-				s.EXPECT().IsRunning().Return(true, nil)
-				s.EXPECT().Status().Return("active", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
+				s.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				s.EXPECT().Status(gomock.Any()).Return(&sidecar.Status{State: sidecar.StateRunning, PID: 1234, Detail: "active (running)"}, nil)
+				s.EXPECT().Logs(gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
-		{
-			name:      "handles invalid run method",
-			runMethod: config.RunMethod_RUN_METHOD_UNSPECIFIED,
-			setupMocks: func(cfg *mock.MockConfigManager, d *mock.MockDockerSidecar, s *mock.MockSystemdSidecar, b *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {
-				cfg.EXPECT().Get().Return(&config.Config{
-					RunMethod: config.RunMethod_RUN_METHOD_UNSPECIFIED,
-					Version:   "latest",
-				}).AnyTimes()
-			},
-			expectedError: "invalid sidecar run method",
-		},
 		{
 			name:      "handles github error gracefully",
 			runMethod: config.RunMethod_RUN_METHOD_DOCKER,
@@ -93,9 +96,9 @@ func TestShowStatus(t *testing.T) {
 					BeaconNodeAddress: "http://test:4444",
 				}).AnyTimes()
 				cfg.EXPECT().GetConfigPath().Return("/test/config.yaml")
-				g.EXPECT().GetLatestVersion().Return("", errors.New("github error"))
-				d.EXPECT().IsRunning().Return(true, nil)
-				d.EXPECT().Status().Return("running", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("", errors.New("github error"))
+				d.EXPECT().Status(gomock.Any()).Return(&sidecar.Status{State: sidecar.StateRunning, Detail: "running"}, nil)
+				d.EXPECT().Logs(gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
 	}
@@ -116,7 +119,13 @@ func TestShowStatus(t *testing.T) {
 			app := cli.NewApp()
 			ctx := cli.NewContext(app, nil, nil)
 
-			err := showStatus(ctx, logrus.New(), mockConfig, mockDocker, mockSystemd, mockBinary, mockGitHub)
+			runner := map[config.RunMethod]sidecar.SidecarRunner{
+				config.RunMethod_RUN_METHOD_DOCKER:  mockDocker,
+				config.RunMethod_RUN_METHOD_SYSTEMD: mockSystemd,
+				config.RunMethod_RUN_METHOD_BINARY:  mockBinary,
+			}[tt.runMethod]
+
+			err := showStatus(ctx, logrus.New(), mockConfig, installer.NewConfig(), runner, mockGitHub, nil)
 
 			if tt.expectedError != "" {
 				assert.ErrorContains(t, err, tt.expectedError)
@@ -128,3 +137,376 @@ func TestShowStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestShowStatus_StructuredOutput(t *testing.T) {
+	beacon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/eth/v1/node/health":
+			w.WriteHeader(http.StatusOK)
+		case "/eth/v1/node/syncing":
+			_, _ = w.Write([]byte(`{"data":{"head_slot":"100","sync_distance":"0","is_syncing":false,"el_offline":false}}`))
+		case "/eth/v1/node/identity":
+			_, _ = w.Write([]byte(`{"data":{"peer_id":"16Uiu2HAm","enr":"enr:-abc","metadata":{"seq_number":"1"}}}`))
+		case "/eth/v1/config/spec":
+			_, _ = w.Write([]byte(`{"data":{"CONFIG_NAME":"mainnet"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer beacon.Close()
+
+	tests := []struct {
+		name          string
+		output        string
+		unmarshal     func([]byte, any) error
+		expectedError string
+	}{
+		{
+			name:      "json",
+			output:    "json",
+			unmarshal: json.Unmarshal,
+		},
+		{
+			name:      "yaml",
+			output:    "yaml",
+			unmarshal: yaml.Unmarshal,
+		},
+		{
+			name:          "invalid format",
+			output:        "xml",
+			expectedError: "invalid output format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockConfig := mock.NewMockConfigManager(ctrl)
+			mockDocker := mock.NewMockDockerSidecar(ctrl)
+			mockGitHub := servicemock.NewMockGitHubService(ctrl)
+
+			mockConfig.EXPECT().Get().Return(&config.Config{
+				RunMethod:              config.RunMethod_RUN_METHOD_DOCKER,
+				Version:                "1.0.0",
+				BeaconNodeAddress:      beacon.URL + ",http://beacon:5052",
+				OutputServer:           &config.OutputServer{Address: "xatu.example.com:443"},
+				AttestationSubnetCheck: &config.AttestationSubnetCheck{Enabled: true},
+			}).AnyTimes()
+
+			if tt.expectedError == "" {
+				mockConfig.EXPECT().GetConfigPath().Return("/test/config.yaml")
+				mockGitHub.EXPECT().GetLatestVersion(gomock.Any()).Return("1.1.0", nil)
+				mockDocker.EXPECT().Status(gomock.Any()).Return(&sidecar.Status{
+					State:        sidecar.StateRunning,
+					ContainerID:  "abc123",
+					PID:          42,
+					StartedAt:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					Uptime:       90 * time.Second,
+					RestartCount: 2,
+					Detail:       "running",
+				}, nil)
+				mockDocker.EXPECT().Logs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts sidecar.LogOptions) error {
+					_, err := io.WriteString(opts.Output, strings.Join([]string{
+						`time="2024-01-01T00:00:05Z" level=info msg="event sent"`,
+						`time="2024-01-01T00:00:10Z" level=error msg="failed to send" error="rpc error: code = Unauthenticated"`,
+					}, "\n"))
+
+					return err
+				})
+			}
+
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.String("output", "text", "")
+			require.NoError(t, set.Parse([]string{"--output", tt.output}))
+
+			var out bytes.Buffer
+
+			app := cli.NewApp()
+			app.Writer = &out
+
+			err := showStatus(
+				cli.NewContext(app, set, nil),
+				logrus.New(),
+				mockConfig,
+				installer.NewConfig(),
+				mockDocker,
+				mockGitHub,
+				nil,
+			)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var report statusReport
+			require.NoError(t, tt.unmarshal(out.Bytes(), &report))
+
+			assert.Equal(t, "1.0.0", report.Version)
+			assert.Equal(t, "1.1.0", report.LatestVersion)
+			assert.True(t, report.NeedsUpdate)
+			assert.Equal(t, "RUN_METHOD_DOCKER", report.RunMethod)
+			assert.True(t, report.Running)
+			require.NotNil(t, report.Status)
+			assert.Equal(t, sidecar.StateRunning, report.Status.State)
+			assert.Equal(t, "running", report.Status.Detail)
+			assert.Equal(t, "abc123", report.Status.ContainerID)
+			assert.Equal(t, 42, report.Status.PID)
+			assert.Equal(t, "1m30s", report.Status.Uptime)
+			assert.Equal(t, int64(90), report.Status.UptimeSeconds)
+			assert.Equal(t, 2, report.Status.RestartCount)
+			assert.Equal(t, "/test/config.yaml", report.ConfigPath)
+			assert.Equal(t, "xatu.example.com:443", report.OutputServer)
+			assert.True(t, report.AttestationOptIn)
+
+			require.Len(t, report.BeaconNodes, 2)
+
+			local := report.BeaconNodes[0]
+			assert.True(t, local.Checked)
+			assert.Empty(t, local.Error)
+			assert.Equal(t, "mainnet", local.Network)
+			require.NotNil(t, local.Health)
+			assert.True(t, local.Health.IsHealthy)
+			require.NotNil(t, local.Sync)
+			assert.Equal(t, "100", local.Sync.HeadSlot)
+			require.NotNil(t, local.Identity)
+			assert.Equal(t, "16Uiu2HAm", local.Identity.PeerID)
+
+			assert.Equal(t, beaconNodeReport{Address: "http://beacon:5052"}, report.BeaconNodes[1])
+
+			require.Len(t, report.KnownIssues, 1)
+			assert.Equal(t, "output-server-unauthorized", report.KnownIssues[0].Rule)
+			assert.Equal(t, 1, report.KnownIssues[0].Count)
+			require.NotNil(t, report.KnownIssues[0].LastSeen)
+			assert.True(t, time.Date(2024, 1, 1, 0, 0, 10, 0, time.UTC).Equal(*report.KnownIssues[0].LastSeen))
+		})
+	}
+}
+
+func TestShowStatus_SystemdScope(t *testing.T) {
+	tests := []struct {
+		name          string
+		runMethod     config.RunMethod
+		scope         string
+		expectedScope string
+	}{
+		{
+			name:          "system unit",
+			runMethod:     config.RunMethod_RUN_METHOD_SYSTEMD,
+			scope:         installer.SystemdScopeSystem,
+			expectedScope: installer.SystemdScopeSystem,
+		},
+		{
+			name:          "user unit",
+			runMethod:     config.RunMethod_RUN_METHOD_SYSTEMD,
+			scope:         installer.SystemdScopeUser,
+			expectedScope: installer.SystemdScopeUser,
+		},
+		{
+			name:      "other run methods have no scope",
+			runMethod: config.RunMethod_RUN_METHOD_BINARY,
+			scope:     installer.SystemdScopeUser,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockConfig := mock.NewMockConfigManager(ctrl)
+			mockRunner := mock.NewMockSystemdSidecar(ctrl)
+			mockGitHub := servicemock.NewMockGitHubService(ctrl)
+
+			mockConfig.EXPECT().Get().Return(&config.Config{
+				RunMethod: tt.runMethod,
+				Version:   "1.0.0",
+			}).AnyTimes()
+			mockConfig.EXPECT().GetConfigPath().Return("/test/config.yaml")
+			mockGitHub.EXPECT().GetLatestVersion(gomock.Any()).Return("1.0.0", nil)
+			mockRunner.EXPECT().Status(gomock.Any()).Return(&sidecar.Status{State: sidecar.StateRunning}, nil)
+			mockRunner.EXPECT().Logs(gomock.Any(), gomock.Any()).Return(nil)
+
+			installerCfg := installer.NewConfig()
+			installerCfg.Systemd.Scope = tt.scope
+
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.String("output", outputJSON, "")
+
+			var out bytes.Buffer
+
+			app := cli.NewApp()
+			app.Writer = &out
+
+			err := showStatus(cli.NewContext(app, set, nil), logrus.New(), mockConfig, installerCfg, mockRunner, mockGitHub, nil)
+			require.NoError(t, err)
+
+			var report statusReport
+			require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+
+			assert.Equal(t, tt.runMethod.String(), report.RunMethod)
+			assert.Equal(t, tt.expectedScope, report.SystemdScope)
+		})
+	}
+}
+
+func TestShowStatus_Orphans(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		setupOrphan     func(b *mock.MockBinarySidecar)
+		expectedOrphans []string
+		expectedError   string
+	}{
+		{
+			name:            "reports orphans",
+			args:            []string{"--output", "json"},
+			setupOrphan:     func(b *mock.MockBinarySidecar) {},
+			expectedOrphans: []string{"binary"},
+		},
+		{
+			name: "cleans up orphans",
+			args: []string{"--cleanup-orphans"},
+			setupOrphan: func(b *mock.MockBinarySidecar) {
+				b.EXPECT().Stop(gomock.Any()).Return(nil)
+				b.EXPECT().UninstallSteps().Return(nil)
+			},
+		},
+		{
+			name:          "cleanup refuses structured output",
+			args:          []string{"--cleanup-orphans", "--output", "json"},
+			setupOrphan:   func(b *mock.MockBinarySidecar) {},
+			expectedError: "can't be combined with --output json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := test.SuppressOutput(t)
+			defer cleanup()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockConfig := mock.NewMockConfigManager(ctrl)
+			mockDocker := mock.NewMockDockerSidecar(ctrl)
+			mockBinary := mock.NewMockBinarySidecar(ctrl)
+			mockGitHub := servicemock.NewMockGitHubService(ctrl)
+
+			mockConfig.EXPECT().Get().Return(&config.Config{
+				RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
+				Version:   "1.0.0",
+			}).AnyTimes()
+
+			if tt.expectedError == "" {
+				mockConfig.EXPECT().GetConfigPath().Return("/test/config.yaml")
+				mockGitHub.EXPECT().GetLatestVersion(gomock.Any()).Return("1.0.0", nil)
+				mockDocker.EXPECT().Status(gomock.Any()).Return(&sidecar.Status{State: sidecar.StateRunning}, nil)
+				mockDocker.EXPECT().Logs(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			tt.setupOrphan(mockBinary)
+
+			confirmResponses = []bool{true}
+
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.String("output", "text", "")
+			set.Bool("cleanup-orphans", false, "")
+			require.NoError(t, set.Parse(tt.args))
+
+			var out bytes.Buffer
+
+			app := cli.NewApp()
+			app.Writer = &out
+
+			var report *statusReport
+
+			err := showStatus(
+				cli.NewContext(app, set, nil),
+				logrus.New(),
+				mockConfig,
+				installer.NewConfig(),
+				mockDocker,
+				mockGitHub,
+				[]sidecar.Orphan{{RunMode: sidecar.RunMode{Method: config.RunMethod_RUN_METHOD_BINARY}, Runner: mockBinary}},
+			)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			// Text output goes to stdout, so only the structured report can be inspected.
+			if out.Len() > 0 {
+				require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+				assert.Equal(t, tt.expectedOrphans, report.Orphans)
+			}
+		})
+	}
+}
+
+var confirmResponses []bool
+
+// For obvious reasons, we need to mock the confirm prompt. Tests can't be interactive.
+func init() {
+	tui.Confirm = func(string) bool {
+		response := confirmResponses[0]
+		confirmResponses = confirmResponses[1:]
+
+		return response
+	}
+}
+
+func TestCleanupOrphans(t *testing.T) {
+	cleanup := test.SuppressOutput(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mockSystemd = mock.NewMockSystemdSidecar(ctrl)
+		mockBinary  = mock.NewMockBinarySidecar(ctrl)
+		mockDocker  = mock.NewMockDockerSidecar(ctrl)
+		removed     bool
+	)
+
+	// The docker orphan is declined, so it's left running without being touched.
+	confirmResponses = []bool{true, true, false}
+
+	mockSystemd.EXPECT().Stop(gomock.Any()).Return(nil)
+	mockSystemd.EXPECT().UninstallSteps().Return([]sidecar.UninstallStep{{
+		Description: "Remove unit",
+		Run: func(context.Context) error {
+			removed = true
+
+			return nil
+		},
+	}})
+	mockBinary.EXPECT().Stop(gomock.Any()).Return(errors.New("permission denied"))
+	mockBinary.EXPECT().UninstallSteps().Return(nil)
+	mockDocker.EXPECT().UninstallSteps().Return(nil)
+
+	var (
+		failed   = sidecar.Orphan{RunMode: sidecar.RunMode{Method: config.RunMethod_RUN_METHOD_BINARY}, Runner: mockBinary}
+		declined = sidecar.Orphan{RunMode: sidecar.RunMode{Method: config.RunMethod_RUN_METHOD_DOCKER}, Runner: mockDocker}
+	)
+
+	remaining := cleanupOrphans(cli.NewContext(cli.NewApp(), nil, nil), []sidecar.Orphan{
+		{RunMode: sidecar.RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD}, Runner: mockSystemd},
+		failed,
+		declined,
+	})
+
+	assert.True(t, removed)
+	assert.Empty(t, confirmResponses)
+	assert.Equal(t, []sidecar.Orphan{failed, declined}, remaining)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
 	servicemock "github.com/ethpandaops/contributoor-installer/internal/service/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
 	"github.com/ethpandaops/contributoor-installer/internal/test"
 	"github.com/ethpandaops/contributoor/pkg/config/v1"
@@ -35,9 +36,9 @@ func TestStopContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
 					Version:   "latest",
 				}).Times(1)
-				g.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
-				d.EXPECT().Version().Return("1.0.0", nil)
-				d.EXPECT().Stop().Return(nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
+				d.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				d.EXPECT().Stop(gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -48,9 +49,9 @@ func TestStopContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
 					Version:   "latest",
 				}).Times(1)
-				g.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
-				d.EXPECT().Version().Return("1.0.0", nil)
-				d.EXPECT().Stop().Return(errors.New("stop failed"))
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
+				d.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				d.EXPECT().Stop(gomock.Any()).Return(errors.New("stop failed"))
 			},
 			expectedError: "stop failed",
 		},
@@ -62,22 +63,11 @@ func TestStopContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_BINARY,
 					Version:   "latest",
 				}).Times(1)
-				g.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
-				b.EXPECT().Version().Return("1.0.0", nil)
-				b.EXPECT().Stop().Return(nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
+				b.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				b.EXPECT().Stop(gomock.Any()).Return(nil)
 			},
 		},
-		{
-			name:      "invalid sidecar run method",
-			runMethod: config.RunMethod_RUN_METHOD_UNSPECIFIED,
-			setupMocks: func(cfg *mock.MockConfigManager, d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {
-				cfg.EXPECT().Get().Return(&config.Config{
-					RunMethod: config.RunMethod_RUN_METHOD_UNSPECIFIED,
-					Version:   "latest",
-				}).Times(1)
-			},
-			expectedError: "invalid sidecar run method",
-		},
 		{
 			name:      "github error is handled gracefully",
 			runMethod: config.RunMethod_RUN_METHOD_DOCKER,
@@ -86,8 +76,8 @@ func TestStopContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
 					Version:   "latest",
 				}).Times(1)
-				g.EXPECT().GetLatestVersion().Return("", errors.New("github error"))
-				d.EXPECT().Stop().Return(nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("", errors.New("github error"))
+				d.EXPECT().Stop(gomock.Any()).Return(nil)
 			},
 		},
 	}
@@ -108,7 +98,13 @@ func TestStopContributoor(t *testing.T) {
 			app := cli.NewApp()
 			ctx := cli.NewContext(app, nil, nil)
 
-			err := stopContributoor(ctx, logrus.New(), mockConfig, mockDocker, mockSystemd, mockBinary, mockGitHub)
+			runner := map[config.RunMethod]sidecar.SidecarRunner{
+				config.RunMethod_RUN_METHOD_DOCKER:  mockDocker,
+				config.RunMethod_RUN_METHOD_SYSTEMD: mockSystemd,
+				config.RunMethod_RUN_METHOD_BINARY:  mockBinary,
+			}[tt.runMethod]
+
+			err := stopContributoor(ctx, logrus.New(), mockConfig, runner, mockGitHub)
 
 			if tt.expectedError != "" {
 				assert.ErrorContains(t, err, tt.expectedError)
@@ -0,0 +1,72 @@
+package supervise
+
+import (
+	"fmt"
+
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
+	"github.com/ethpandaops/contributoor-installer/internal/logrotate"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// RegisterCommands registers the supervise command, which the binary run method
+// starts in the background to run the sidecar. It isn't meant to be run by hand.
+func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      opts.Name(),
+		Aliases:   opts.Aliases(),
+		Usage:     "Run the Contributoor binary, restarting it if it exits",
+		UsageText: "contributoor --config-path <path> [--instance <name>] supervise",
+		Hidden:    true,
+		Action: func(c *cli.Context) error {
+			var (
+				log          = opts.Logger()
+				installerCfg = opts.InstallerConfig()
+			)
+
+			// This also overlays a named instance's installer.yaml on the root one.
+			dir, err := opts.ConfigPath(c)
+			if err != nil {
+				return err
+			}
+
+			stdout, stderr, err := sidecar.OpenBinaryLogs(dir, installerCfg.Logs)
+			if err != nil {
+				return err
+			}
+
+			defer stdout.Close()
+			defer stderr.Close()
+
+			// Our output goes to the service logs rather than a terminal.
+			log.SetOutput(stderr)
+			log.SetFormatter(&logrus.TextFormatter{DisableColors: true, FullTimestamp: true})
+
+			if err := supervise(c, log, dir, stdout, stderr); err != nil {
+				// The error can't be logged once the log files are closed.
+				log.Error(err)
+
+				return err
+			}
+
+			return nil
+		},
+	})
+}
+
+func supervise(c *cli.Context, log *logrus.Logger, dir string, stdout, stderr *logrotate.Writer) error {
+	sidecarCfg, err := sidecar.NewConfigService(log, dir)
+	if err != nil {
+		return err
+	}
+
+	supervisor, err := sidecar.NewSupervisor(log, sidecarCfg, stdout, stderr)
+	if err != nil {
+		return fmt.Errorf("failed to create supervisor: %w", err)
+	}
+
+	log.Info("Supervising contributoor")
+
+	return supervisor.Run(c.Context)
+}
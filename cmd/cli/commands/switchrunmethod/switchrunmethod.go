@@ -0,0 +1,310 @@
+package switchrunmethod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      opts.Name(),
+		Aliases:   opts.Aliases(),
+		Usage:     "Switch Contributoor to another run method, removing the old one",
+		UsageText: "contributoor switch-run-method [options] <docker|systemd|systemd-user|binary>",
+		Flags: []cli.Flag{
+			options.InstanceFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			var (
+				log          = opts.Logger()
+				installerCfg = opts.InstallerConfig()
+			)
+
+			if c.NArg() != 1 {
+				return fmt.Errorf(
+					"%sexpected a single run method: %s, %s, %s or %s%s",
+					tui.TerminalColorRed,
+					sidecar.RunMethodDocker,
+					sidecar.RunMethodSystemd,
+					sidecar.RunMethodSystemdUser,
+					sidecar.RunMethodBinary,
+					tui.TerminalColorReset,
+				)
+			}
+
+			method, err := sidecar.ParseRunMethod(c.Args().First())
+			if err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+			}
+
+			configPath, err := opts.ConfigPath(c)
+			if err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+			}
+
+			sidecarCfg, err := sidecar.NewConfigService(log, configPath)
+			if err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+			}
+
+			var (
+				current = sidecar.RunMode{Method: sidecarCfg.Get().RunMethod, Scope: installerCfg.Systemd.Scope}
+				target  = sidecar.RunMode{Method: method, Scope: sidecar.ParseSystemdScope(c.Args().First())}
+			)
+
+			if current.Name() == target.Name() {
+				return fmt.Errorf(
+					"%scontributoor is already using the %s run method%s",
+					tui.TerminalColorRed,
+					target.Name(),
+					tui.TerminalColorReset,
+				)
+			}
+
+			currentRunner, err := sidecar.ResolveRunner(log, sidecarCfg, installerCfg)
+			if err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+			}
+
+			// The target may be systemd with the other scope, eg: moving to a user unit.
+			targetCfg := *installerCfg
+			if target.Scope != "" {
+				targetCfg.Systemd.Scope = target.Scope
+			}
+
+			targetRunner, err := sidecar.NewRunner(target.Method, log, sidecarCfg, &targetCfg)
+			if err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+			}
+
+			return switchRunMethod(c, log, sidecarCfg, currentRunner, targetRunner, current, target)
+		},
+	})
+}
+
+// step is a single action taken when switching run method, along with the action
+// which reverts it.
+type step struct {
+	Description string
+	Run         func(ctx context.Context) error
+	Undo        func(ctx context.Context) error
+}
+
+func switchRunMethod(
+	c *cli.Context,
+	log *logrus.Logger,
+	sidecarCfg sidecar.ConfigManager,
+	current, target sidecar.SidecarRunner,
+	currentMethod, targetMethod sidecar.RunMode,
+) error {
+	running, err := current.IsRunning(c.Context)
+	if err != nil {
+		log.Debugf("failed to check if service is running: %v", err)
+	}
+
+	steps := switchSteps(sidecarCfg, current, target, currentMethod, targetMethod, running)
+
+	fmt.Printf(
+		"%sSwitching from %s to %s:%s\n",
+		tui.TerminalColorLightBlue,
+		currentMethod.Name(),
+		targetMethod.Name(),
+		tui.TerminalColorReset,
+	)
+
+	for _, s := range steps {
+		fmt.Printf(" • %s\n", s.Description)
+	}
+
+	if !c.Bool("non-interactive") && !tui.Confirm("\nAre you sure you want to switch run method?") {
+		fmt.Printf("\nSwitch cancelled\n")
+
+		return nil
+	}
+
+	fmt.Println()
+
+	for i, s := range steps {
+		log.Debugf("running switch step: %s", s.Description)
+
+		err := c.Context.Err()
+		if err == nil {
+			err = s.Run(c.Context)
+		}
+
+		if err != nil {
+			fmt.Printf("%s✗%s %s\n", tui.TerminalColorRed, tui.TerminalColorReset, s.Description)
+
+			if rollbackErr := rollback(c.Context, steps[:i+1]); rollbackErr != nil {
+				return fmt.Errorf(
+					"%s%s: %v\n\nrollback to %s failed, contributoor may need to be reinstalled: %v%s",
+					tui.TerminalColorRed,
+					s.Description,
+					err,
+					currentMethod.Name(),
+					rollbackErr,
+					tui.TerminalColorReset,
+				)
+			}
+
+			return fmt.Errorf(
+				"%s%s: %v\n\nrolled back to %s%s",
+				tui.TerminalColorRed,
+				s.Description,
+				err,
+				currentMethod.Name(),
+				tui.TerminalColorReset,
+			)
+		}
+
+		fmt.Printf("%s✓%s %s\n", tui.TerminalColorGreen, tui.TerminalColorReset, s.Description)
+	}
+
+	fmt.Printf(
+		"\n%sContributoor is now running with the %s run method%s\n",
+		tui.TerminalColorGreen,
+		targetMethod.Name(),
+		tui.TerminalColorReset,
+	)
+
+	return nil
+}
+
+// switchSteps builds the steps to switch from the current to the target run method:
+// tear down the current backend, provision the target, then point the config at it
+// and start it. The old service is only started again on rollback if it was running.
+func switchSteps(
+	sidecarCfg sidecar.ConfigManager,
+	current, target sidecar.SidecarRunner,
+	currentMethod, targetMethod sidecar.RunMode,
+	running bool,
+) []step {
+	var steps []step
+
+	if running {
+		steps = append(steps, step{
+			Description: fmt.Sprintf("Stop the %s service", currentMethod.Name()),
+			Run:         current.Stop,
+			Undo: func(ctx context.Context) error {
+				// The stop may have failed, leaving the service running.
+				if running, _ := current.IsRunning(ctx); running {
+					return nil
+				}
+
+				return current.Start(ctx)
+			},
+		})
+	}
+
+	for i, uninstall := range current.UninstallSteps() {
+		s := step{Description: uninstall.Description, Run: uninstall.Run}
+
+		// Provisioning restores everything the uninstall steps removed, so it's only
+		// needed once, after rolling back to the first of them.
+		if i == 0 {
+			s.Undo = current.Provision
+		}
+
+		steps = append(steps, s)
+	}
+
+	steps = append(steps,
+		step{
+			Description: fmt.Sprintf("Install the %s service", targetMethod.Name()),
+			Run:         target.Provision,
+			Undo:        uninstallFunc(target),
+		},
+		step{
+			Description: fmt.Sprintf("Set the run method to %s in %s", targetMethod.Name(), sidecarCfg.GetConfigPath()),
+			Run: func(ctx context.Context) error {
+				return sidecarCfg.Update(func(cfg *config.Config) {
+					cfg.RunMethod = targetMethod.Method
+				})
+			},
+			Undo: func(ctx context.Context) error {
+				return sidecarCfg.Update(func(cfg *config.Config) {
+					cfg.RunMethod = currentMethod.Method
+				})
+			},
+		},
+	)
+
+	// The scope is kept when switching to other run methods, so it's only recorded
+	// when switching to systemd with a different one.
+	if targetMethod.Scope != "" && targetMethod.Scope != currentMethod.Scope {
+		path := filepath.Join(filepath.Dir(sidecarCfg.GetConfigPath()), installer.ConfigFilename)
+
+		steps = append(steps, step{
+			Description: fmt.Sprintf("Set the systemd scope to %s in %s", targetMethod.Scope, path),
+			Run: func(ctx context.Context) error {
+				return installer.SaveSystemdScope(path, targetMethod.Scope)
+			},
+			Undo: func(ctx context.Context) error {
+				return installer.SaveSystemdScope(path, currentMethod.Scope)
+			},
+		})
+	}
+
+	steps = append(steps,
+		step{
+			Description: fmt.Sprintf("Start the %s service", targetMethod.Name()),
+			Run:         target.Start,
+			Undo: func(ctx context.Context) error {
+				// The start may have got far enough to leave the service running.
+				if running, _ := target.IsRunning(ctx); running {
+					return target.Stop(ctx)
+				}
+
+				return nil
+			},
+		},
+	)
+
+	return steps
+}
+
+// rollback undoes steps in reverse order. The failed step is included, as it may
+// have got part way through, so every undo copes with its step not having run.
+func rollback(ctx context.Context, completed []step) error {
+	// Rollback has to run to completion even if we were interrupted.
+	ctx = context.WithoutCancel(ctx)
+
+	fmt.Printf("\n%sRolling back%s\n", tui.TerminalColorYellow, tui.TerminalColorReset)
+
+	var errs []error
+
+	for i := len(completed) - 1; i >= 0; i-- {
+		if completed[i].Undo == nil {
+			continue
+		}
+
+		if err := completed[i].Undo(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("undo %q: %w", completed[i].Description, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// uninstallFunc returns a function running the uninstall steps of runner.
+func uninstallFunc(runner sidecar.SidecarRunner) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		for _, s := range runner.UninstallSteps() {
+			if err := s.Run(ctx); err != nil {
+				return fmt.Errorf("%s: %w", s.Description, err)
+			}
+		}
+
+		return nil
+	}
+}
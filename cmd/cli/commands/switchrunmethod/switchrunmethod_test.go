@@ -0,0 +1,281 @@
+package switchrunmethod
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/test"
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/mock/gomock"
+)
+
+var confirmResponse bool
+
+// For obvious reasons, we need to mock the confirm prompt. Tests can't be interactive.
+func init() {
+	tui.Confirm = func(string) bool {
+		return confirmResponse
+	}
+}
+
+func TestSwitchRunMethod(t *testing.T) {
+	tests := []struct {
+		name              string
+		args              []string
+		confirm           bool
+		setupMocks        func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, calls *[]string)
+		expectedError     string
+		expectedRunMethod config.RunMethod
+		expectedCalls     []string
+	}{
+		{
+			name: "switches a running service",
+			args: []string{"--non-interactive"},
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, calls *[]string) {
+				d.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+				d.EXPECT().Stop(gomock.Any()).DoAndReturn(record(calls, "docker stop"))
+				d.EXPECT().UninstallSteps().Return(steps(calls, "docker project", "docker image"))
+				b.EXPECT().Provision(gomock.Any()).DoAndReturn(record(calls, "binary provision"))
+				b.EXPECT().Start(gomock.Any()).DoAndReturn(record(calls, "binary start"))
+			},
+			expectedRunMethod: config.RunMethod_RUN_METHOD_BINARY,
+			expectedCalls:     []string{"docker stop", "docker project", "docker image", "binary provision", "binary start"},
+		},
+		{
+			name: "switches a stopped service without starting the old one on rollback",
+			args: []string{"--non-interactive"},
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, calls *[]string) {
+				d.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				d.EXPECT().UninstallSteps().Return(steps(calls, "docker project"))
+				b.EXPECT().Provision(gomock.Any()).Return(errors.New("download failed"))
+				b.EXPECT().UninstallSteps().Return(steps(calls, "binary pid file"))
+				d.EXPECT().Provision(gomock.Any()).DoAndReturn(record(calls, "docker provision"))
+			},
+			expectedError:     "download failed",
+			expectedRunMethod: config.RunMethod_RUN_METHOD_DOCKER,
+			expectedCalls:     []string{"docker project", "binary pid file", "docker provision"},
+		},
+		{
+			name: "rolls back when the new service fails to start",
+			args: []string{"--non-interactive"},
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, calls *[]string) {
+				gomock.InOrder(
+					d.EXPECT().IsRunning(gomock.Any()).Return(true, nil),
+					d.EXPECT().IsRunning(gomock.Any()).Return(false, nil),
+				)
+				d.EXPECT().Stop(gomock.Any()).DoAndReturn(record(calls, "docker stop"))
+				d.EXPECT().UninstallSteps().Return(steps(calls, "docker project"))
+				b.EXPECT().Provision(gomock.Any()).DoAndReturn(record(calls, "binary provision"))
+				b.EXPECT().Start(gomock.Any()).Return(errors.New("binary crashed"))
+				b.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				b.EXPECT().UninstallSteps().Return(steps(calls, "binary pid file"))
+				d.EXPECT().Provision(gomock.Any()).DoAndReturn(record(calls, "docker provision"))
+				d.EXPECT().Start(gomock.Any()).DoAndReturn(record(calls, "docker start"))
+			},
+			expectedError:     "rolled back to docker",
+			expectedRunMethod: config.RunMethod_RUN_METHOD_DOCKER,
+			expectedCalls: []string{
+				"docker stop", "docker project", "binary provision",
+				"binary pid file", "docker provision", "docker start",
+			},
+		},
+		{
+			name: "reports a failed rollback",
+			args: []string{"--non-interactive"},
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, calls *[]string) {
+				d.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				d.EXPECT().UninstallSteps().Return(steps(calls, "docker project"))
+				b.EXPECT().Provision(gomock.Any()).Return(errors.New("download failed"))
+				b.EXPECT().UninstallSteps().Return(steps(calls, "binary pid file"))
+				d.EXPECT().Provision(gomock.Any()).Return(errors.New("registry unreachable"))
+			},
+			expectedError:     "may need to be reinstalled",
+			expectedRunMethod: config.RunMethod_RUN_METHOD_DOCKER,
+			expectedCalls:     []string{"docker project", "binary pid file"},
+		},
+		{
+			name: "confirmation declined",
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, calls *[]string) {
+				d.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+				d.EXPECT().UninstallSteps().Return(steps(calls, "docker project"))
+			},
+			expectedRunMethod: config.RunMethod_RUN_METHOD_DOCKER,
+		},
+		{
+			name:    "confirmation accepted",
+			confirm: true,
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, calls *[]string) {
+				d.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				d.EXPECT().UninstallSteps().Return(steps(calls, "docker project"))
+				b.EXPECT().Provision(gomock.Any()).DoAndReturn(record(calls, "binary provision"))
+				b.EXPECT().Start(gomock.Any()).DoAndReturn(record(calls, "binary start"))
+			},
+			expectedRunMethod: config.RunMethod_RUN_METHOD_BINARY,
+			expectedCalls:     []string{"docker project", "binary provision", "binary start"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := test.SuppressOutput(t)
+			defer cleanup()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			configDir := t.TempDir()
+			require.NoError(t, os.WriteFile(
+				filepath.Join(configDir, "config.yaml"),
+				[]byte("version: 1.0.0\nrunMethod: RUN_METHOD_DOCKER\ncontributoorDirectory: "+configDir+"\n"),
+				0600,
+			))
+
+			sidecarCfg, err := sidecar.NewConfigService(logrus.New(), configDir)
+			require.NoError(t, err)
+
+			var (
+				calls      []string
+				mockDocker = mock.NewMockDockerSidecar(ctrl)
+				mockBinary = mock.NewMockBinarySidecar(ctrl)
+			)
+
+			tt.setupMocks(mockDocker, mockBinary, &calls)
+
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.Bool("non-interactive", false, "")
+			require.NoError(t, set.Parse(tt.args))
+
+			confirmResponse = tt.confirm
+
+			c := cli.NewContext(cli.NewApp(), set, nil)
+			c.Context = context.Background()
+
+			err = switchRunMethod(
+				c, logrus.New(), sidecarCfg, mockDocker, mockBinary,
+				sidecar.RunMode{Method: config.RunMethod_RUN_METHOD_DOCKER, Scope: installer.SystemdScopeSystem},
+				sidecar.RunMode{Method: config.RunMethod_RUN_METHOD_BINARY},
+			)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectedCalls, calls)
+
+			// The config on disk must agree with the backend left in place.
+			reloaded, err := sidecar.NewConfigService(logrus.New(), configDir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRunMethod, reloaded.Get().RunMethod)
+		})
+	}
+}
+
+func TestSwitchRunMethod_SystemdScope(t *testing.T) {
+	tests := []struct {
+		name          string
+		startErr      error
+		expectedError string
+		expectedScope string
+	}{
+		{
+			name:          "moves to a user unit",
+			expectedScope: "scope: user",
+		},
+		{
+			name:          "rolls back the scope",
+			startErr:      errors.New("failed to connect to bus"),
+			expectedError: "rolled back to systemd",
+			expectedScope: "scope: system",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := test.SuppressOutput(t)
+			defer cleanup()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			configDir := t.TempDir()
+			require.NoError(t, os.WriteFile(
+				filepath.Join(configDir, "config.yaml"),
+				[]byte("version: 1.0.0\nrunMethod: RUN_METHOD_SYSTEMD\ncontributoorDirectory: "+configDir+"\n"),
+				0600,
+			))
+
+			sidecarCfg, err := sidecar.NewConfigService(logrus.New(), configDir)
+			require.NoError(t, err)
+
+			var (
+				calls      []string
+				mockSystem = mock.NewMockSystemdSidecar(ctrl)
+				mockUser   = mock.NewMockSystemdSidecar(ctrl)
+			)
+
+			mockSystem.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+			mockSystem.EXPECT().UninstallSteps().Return(steps(&calls, "system unit"))
+			mockUser.EXPECT().Provision(gomock.Any()).DoAndReturn(record(&calls, "user provision"))
+			mockUser.EXPECT().Start(gomock.Any()).Return(tt.startErr)
+
+			if tt.startErr != nil {
+				mockUser.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				mockUser.EXPECT().UninstallSteps().Return(steps(&calls, "user unit"))
+				mockSystem.EXPECT().Provision(gomock.Any()).DoAndReturn(record(&calls, "system provision"))
+			}
+
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.Bool("non-interactive", true, "")
+
+			c := cli.NewContext(cli.NewApp(), set, nil)
+			c.Context = context.Background()
+
+			err = switchRunMethod(
+				c, logrus.New(), sidecarCfg, mockSystem, mockUser,
+				sidecar.RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: installer.SystemdScopeSystem},
+				sidecar.RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: installer.SystemdScopeUser},
+			)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(configDir, installer.ConfigFilename))
+			require.NoError(t, err)
+			assert.Contains(t, string(data), tt.expectedScope)
+		})
+	}
+}
+
+// record returns a runner method which records name when called.
+func record(calls *[]string, name string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		*calls = append(*calls, name)
+
+		return nil
+	}
+}
+
+// steps returns uninstall steps which record their names when run.
+func steps(calls *[]string, names ...string) []sidecar.UninstallStep {
+	out := make([]sidecar.UninstallStep, 0, len(names))
+	for _, name := range names {
+		out = append(out, sidecar.UninstallStep{Description: name, Run: record(calls, name)})
+	}
+
+	return out
+}
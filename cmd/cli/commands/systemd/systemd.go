@@ -0,0 +1,165 @@
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      opts.Name(),
+		Aliases:   opts.Aliases(),
+		Usage:     "Manage the systemd unit of the systemd run method",
+		UsageText: "contributoor systemd [command]",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "render-unit",
+				Usage:     "Print the systemd unit rendered from config.yaml and installer.yaml",
+				UsageText: "contributoor systemd render-unit",
+				Flags:     []cli.Flag{options.InstanceFlag()},
+				Action: func(c *cli.Context) error {
+					systemd, err := newSystemdSidecar(c, opts, false)
+					if err != nil {
+						return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+					}
+
+					return renderUnit(c, systemd)
+				},
+			},
+			{
+				Name:      "reinstall-unit",
+				Usage:     "Regenerate the installed systemd unit from config.yaml and installer.yaml",
+				UsageText: "contributoor systemd reinstall-unit [options]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Show how the installed unit differs, without reinstalling it",
+					},
+					options.InstanceFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					systemd, err := newSystemdSidecar(c, opts, true)
+					if err != nil {
+						return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+					}
+
+					return reinstallUnit(c, systemd)
+				},
+			},
+		},
+	})
+}
+
+// newSystemdSidecar returns the systemd sidecar of the instance. If requireRunMethod
+// is set, systemd must be its run method.
+func newSystemdSidecar(c *cli.Context, opts *options.CommandOpts, requireRunMethod bool) (sidecar.SystemdSidecar, error) {
+	configPath, err := opts.ConfigPath(c)
+	if err != nil {
+		return nil, err
+	}
+
+	sidecarCfg, err := sidecar.NewConfigService(opts.Logger(), configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveSystemd(opts.Logger(), sidecarCfg, opts.InstallerConfig(), requireRunMethod)
+}
+
+func resolveSystemd(
+	log *logrus.Logger,
+	sidecarCfg sidecar.ConfigManager,
+	installerCfg *installer.Config,
+	requireRunMethod bool,
+) (sidecar.SystemdSidecar, error) {
+	if requireRunMethod && sidecarCfg.Get().RunMethod != config.RunMethod_RUN_METHOD_SYSTEMD {
+		return nil, fmt.Errorf(
+			"contributoor isn't using the systemd run method, switch to it with 'contributoor switch-run-method systemd'",
+		)
+	}
+
+	return sidecar.NewSystemdSidecar(log, sidecarCfg, installerCfg)
+}
+
+// renderUnit prints the unit that reinstall-unit would install.
+func renderUnit(c *cli.Context, systemd sidecar.SystemdSidecar) error {
+	data, err := systemd.RenderUnit()
+	if err != nil {
+		return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+	}
+
+	if _, err := c.App.Writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write unit: %w", err)
+	}
+
+	return nil
+}
+
+// reinstallUnit shows how the installed unit differs from the rendered one, then
+// installs the rendered one. The service is left running with the old unit until
+// it's restarted.
+func reinstallUnit(c *cli.Context, systemd sidecar.SystemdSidecar) error {
+	w := c.App.Writer
+
+	drift, err := systemd.UnitDrift()
+
+	switch {
+	case errors.Is(err, sidecar.ErrUnitNotInstalled):
+		fmt.Fprintf(w, "%s%v, it will be installed%s\n", tui.TerminalColorYellow, err, tui.TerminalColorReset)
+	case err != nil:
+		return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+	case len(drift) == 0:
+		fmt.Fprintln(w, "The installed unit is up to date")
+	default:
+		fmt.Fprintf(w, "%sThe installed unit differs from the rendered unit:%s\n", tui.TerminalColorLightBlue, tui.TerminalColorReset)
+		printDrift(w, drift)
+	}
+
+	if c.Bool("dry-run") {
+		fmt.Fprintf(w, "\n%sDry run, the unit wasn't reinstalled%s\n", tui.TerminalColorYellow, tui.TerminalColorReset)
+
+		return nil
+	}
+
+	running, _ := systemd.IsRunning(c.Context)
+
+	if err := systemd.InstallUnit(c.Context); err != nil {
+		return fmt.Errorf("%sfailed to reinstall unit: %v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+	}
+
+	fmt.Fprintf(w, "%sReinstalled the service unit%s\n", tui.TerminalColorGreen, tui.TerminalColorReset)
+
+	if running && len(drift) > 0 {
+		fmt.Fprintf(
+			w,
+			"%sContributoor is still running with the old unit, run 'contributoor restart' to apply the changes%s\n",
+			tui.TerminalColorYellow,
+			tui.TerminalColorReset,
+		)
+	}
+
+	return nil
+}
+
+// printDrift prints the lines only in the installed unit in red, and those only in
+// the rendered unit in green.
+func printDrift(w io.Writer, drift []string) {
+	for _, line := range drift {
+		color := tui.TerminalColorGreen
+		if strings.HasPrefix(line, "-") {
+			color = tui.TerminalColorRed
+		}
+
+		fmt.Fprintf(w, "  %s%s%s\n", color, line, tui.TerminalColorReset)
+	}
+}
@@ -0,0 +1,144 @@
+package systemd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReinstallUnit(t *testing.T) {
+	tests := []struct {
+		name             string
+		dryRun           bool
+		setupMocks       func(m *mock.MockSystemdSidecar)
+		expectedOutput   []string
+		unexpectedOutput []string
+		expectedError    string
+	}{
+		{
+			name: "drift while running",
+			setupMocks: func(m *mock.MockSystemdSidecar) {
+				m.EXPECT().UnitDrift().Return([]string{"- MemoryMax=512M", "+ MemoryMax=1G"}, nil)
+				m.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+				m.EXPECT().InstallUnit(gomock.Any()).Return(nil)
+			},
+			expectedOutput: []string{
+				"differs from the rendered unit",
+				"- MemoryMax=512M",
+				"+ MemoryMax=1G",
+				"Reinstalled the service unit",
+				"run 'contributoor restart' to apply the changes",
+			},
+		},
+		{
+			name: "up to date",
+			setupMocks: func(m *mock.MockSystemdSidecar) {
+				m.EXPECT().UnitDrift().Return(nil, nil)
+				m.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+				m.EXPECT().InstallUnit(gomock.Any()).Return(nil)
+			},
+			expectedOutput:   []string{"The installed unit is up to date", "Reinstalled the service unit"},
+			unexpectedOutput: []string{"contributoor restart"},
+		},
+		{
+			name: "not installed",
+			setupMocks: func(m *mock.MockSystemdSidecar) {
+				m.EXPECT().UnitDrift().Return(nil, fmt.Errorf("%w: /etc/systemd/system/contributoor.service", sidecar.ErrUnitNotInstalled))
+				m.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				m.EXPECT().InstallUnit(gomock.Any()).Return(nil)
+			},
+			expectedOutput: []string{"it will be installed", "Reinstalled the service unit"},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			setupMocks: func(m *mock.MockSystemdSidecar) {
+				m.EXPECT().UnitDrift().Return([]string{"+ CPUQuota=50%"}, nil)
+			},
+			expectedOutput:   []string{"+ CPUQuota=50%", "Dry run, the unit wasn't reinstalled"},
+			unexpectedOutput: []string{"Reinstalled"},
+		},
+		{
+			name: "invalid settings",
+			setupMocks: func(m *mock.MockSystemdSidecar) {
+				m.EXPECT().UnitDrift().Return(nil, errors.New(`invalid systemd restart "sometimes"`))
+			},
+			expectedError: `invalid systemd restart "sometimes"`,
+		},
+		{
+			name: "install fails",
+			setupMocks: func(m *mock.MockSystemdSidecar) {
+				m.EXPECT().UnitDrift().Return(nil, nil)
+				m.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				m.EXPECT().InstallUnit(gomock.Any()).Return(errors.New("sudo: a password is required"))
+			},
+			expectedError: "failed to reinstall unit: sudo: a password is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSystemd := mock.NewMockSystemdSidecar(ctrl)
+			tt.setupMocks(mockSystemd)
+
+			var out bytes.Buffer
+
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.Bool("dry-run", tt.dryRun, "")
+
+			app := cli.NewApp()
+			app.Writer = &out
+
+			c := cli.NewContext(app, set, nil)
+			c.Context = context.Background()
+
+			err := reinstallUnit(c, mockSystemd)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			for _, expected := range tt.expectedOutput {
+				assert.Contains(t, out.String(), expected)
+			}
+
+			for _, unexpected := range tt.unexpectedOutput {
+				assert.NotContains(t, out.String(), unexpected)
+			}
+		})
+	}
+}
+
+func TestResolveSystemd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConfig := mock.NewMockConfigManager(ctrl)
+	mockConfig.EXPECT().Get().Return(&config.Config{RunMethod: config.RunMethod_RUN_METHOD_DOCKER}).AnyTimes()
+
+	_, err := resolveSystemd(logrus.New(), mockConfig, installer.NewConfig(), true)
+	assert.ErrorContains(t, err, "switch-run-method systemd")
+
+	// The unit can be previewed before switching to systemd.
+	_, err = resolveSystemd(logrus.New(), mockConfig, installer.NewConfig(), false)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,209 @@
+package uninstall
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/mitchellh/go-homedir"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      opts.Name(),
+		Aliases:   opts.Aliases(),
+		Usage:     "Uninstall Contributoor",
+		UsageText: "contributoor uninstall [options]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "keep-config",
+				Usage: "Keep the config directory (and config.yaml)",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "List what would be removed, without removing anything",
+			},
+			options.InstanceFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			var (
+				log          = opts.Logger()
+				installerCfg = opts.InstallerConfig()
+			)
+
+			configPath, err := opts.ConfigPath(c)
+			if err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+			}
+
+			sidecarCfg, err := sidecar.NewConfigService(log, configPath)
+			if err != nil {
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+			}
+
+			runner, err := sidecar.ResolveRunner(log, sidecarCfg, installerCfg)
+			if err != nil {
+				return err
+			}
+
+			return uninstallContributoor(c, log, sidecarCfg, runner)
+		},
+	})
+}
+
+func uninstallContributoor(
+	c *cli.Context,
+	log *logrus.Logger,
+	sidecarCfg sidecar.ConfigManager,
+	runner sidecar.SidecarRunner,
+) error {
+	steps, err := uninstallSteps(runner, sidecarCfg, c.Bool("keep-config"))
+	if err != nil {
+		return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+	}
+
+	fmt.Printf("%sThe following will be removed:%s\n", tui.TerminalColorLightBlue, tui.TerminalColorReset)
+
+	for _, step := range steps {
+		fmt.Printf(" • %s\n", step.Description)
+	}
+
+	if c.Bool("dry-run") {
+		fmt.Printf("\n%sDry run, nothing was removed%s\n", tui.TerminalColorYellow, tui.TerminalColorReset)
+
+		return nil
+	}
+
+	if !c.Bool("non-interactive") && !tui.Confirm("\nAre you sure you want to uninstall?") {
+		fmt.Printf("\nUninstall cancelled\n")
+
+		return nil
+	}
+
+	fmt.Printf("\n%sUninstalling Contributoor%s\n", tui.TerminalColorRed, tui.TerminalColorReset)
+
+	for _, step := range steps {
+		log.Debugf("running uninstall step: %s", step.Description)
+
+		// Don't start any further steps once we've been interrupted.
+		if err := c.Context.Err(); err != nil {
+			return fmt.Errorf("%suninstall interrupted: %v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
+		}
+
+		if err := step.Run(c.Context); err != nil {
+			return fmt.Errorf("%s%s: %v%s", tui.TerminalColorRed, step.Description, err, tui.TerminalColorReset)
+		}
+
+		fmt.Printf("%s✓%s %s\n", tui.TerminalColorGreen, tui.TerminalColorReset, step.Description)
+	}
+
+	fmt.Printf("\n%sContributoor has been uninstalled successfully%s\n", tui.TerminalColorGreen, tui.TerminalColorReset)
+
+	return nil
+}
+
+// uninstallSteps builds the full list of uninstall steps: stopping the service, the run
+// method specific cleanup, and then removal of the contributoor directories.
+func uninstallSteps(runner sidecar.SidecarRunner, sidecarCfg sidecar.ConfigManager, keepConfig bool) ([]sidecar.UninstallStep, error) {
+	contributoorDir, err := homedir.Expand(sidecarCfg.Get().ContributoorDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand contributoor directory: %w", err)
+	}
+
+	steps := []sidecar.UninstallStep{
+		{
+			Description: "Stop the contributoor service",
+			Run: func(ctx context.Context) error {
+				running, err := runner.IsRunning(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to check if service is running: %w", err)
+				}
+
+				if !running {
+					return nil
+				}
+
+				return runner.Stop(ctx)
+			},
+		},
+	}
+
+	steps = append(steps, runner.UninstallSteps()...)
+
+	// Named instances live under the default instance's config directory, and run
+	// with the contributoor CLI in its bin directory, so neither can be removed while
+	// they're installed.
+	configDir := filepath.Dir(sidecarCfg.GetConfigPath())
+
+	named, err := hasNamedInstances(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if named && !keepConfig {
+		return nil, fmt.Errorf(
+			"other instances are installed under %s, uninstall them first (see 'contributoor instances list') or use --keep-config",
+			configDir,
+		)
+	}
+
+	var dirs []string
+
+	if !named {
+		dirs = append(dirs, filepath.Join(contributoorDir, "releases"), filepath.Join(contributoorDir, "bin"))
+	}
+
+	if !keepConfig {
+		dirs = append(dirs, configDir)
+	}
+
+	for _, dir := range dirs {
+		if err := checkRemovable(dir); err != nil {
+			return nil, err
+		}
+
+		steps = append(steps, sidecar.UninstallStep{
+			Description: fmt.Sprintf("Remove directory %s", dir),
+			Run: func(ctx context.Context) error {
+				return os.RemoveAll(dir)
+			},
+		})
+	}
+
+	return steps, nil
+}
+
+// checkRemovable guards against recursively removing a directory we should never touch,
+// which could happen with a badly configured contributoor directory.
+func checkRemovable(dir string) error {
+	clean := filepath.Clean(dir)
+
+	home, _ := homedir.Dir()
+	if clean == string(filepath.Separator) || clean == "." || clean == filepath.Clean(home) {
+		return fmt.Errorf("refusing to remove directory %s", dir)
+	}
+
+	return nil
+}
+
+// hasNamedInstances reports whether configDir holds any named instances.
+func hasNamedInstances(configDir string) (bool, error) {
+	instances, err := sidecar.ListInstances(configDir)
+	if err != nil {
+		return false, err
+	}
+
+	for _, instance := range instances {
+		if instance.Name != "" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,241 @@
+package uninstall
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/test"
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/mock/gomock"
+)
+
+var confirmResponse bool
+
+// For obvious reasons, we need to mock the confirm prompt. Tests can't be interactive.
+func init() {
+	tui.Confirm = func(string) bool {
+		return confirmResponse
+	}
+}
+
+func TestUninstallContributoor(t *testing.T) {
+	tests := []struct {
+		name          string
+		runMethod     config.RunMethod
+		args          []string
+		confirm       bool
+		namedInstance bool
+		setupMocks    func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, removed *[]string)
+		expectedError string
+		expectRemoved bool
+		expectShared  bool
+		expectConfig  bool
+	}{
+		{
+			name:      "docker - removes everything",
+			runMethod: config.RunMethod_RUN_METHOD_DOCKER,
+			args:      []string{"--non-interactive"},
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, removed *[]string) {
+				d.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+				d.EXPECT().Stop(gomock.Any()).Return(nil)
+				d.EXPECT().UninstallSteps().Return(recordingSteps(removed, "compose project", "image"))
+			},
+			expectRemoved: true,
+		},
+		{
+			name:      "binary - keeps config",
+			runMethod: config.RunMethod_RUN_METHOD_BINARY,
+			args:      []string{"--non-interactive", "--keep-config"},
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, removed *[]string) {
+				b.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				b.EXPECT().UninstallSteps().Return(recordingSteps(removed, "pid file"))
+			},
+			expectRemoved: true,
+			expectConfig:  true,
+		},
+		{
+			name:      "dry run removes nothing",
+			runMethod: config.RunMethod_RUN_METHOD_DOCKER,
+			args:      []string{"--dry-run"},
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, removed *[]string) {
+				d.EXPECT().UninstallSteps().Return(recordingSteps(removed, "compose project"))
+			},
+			expectConfig: true,
+		},
+		{
+			name:      "confirmation declined",
+			runMethod: config.RunMethod_RUN_METHOD_DOCKER,
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, removed *[]string) {
+				d.EXPECT().UninstallSteps().Return(recordingSteps(removed, "compose project"))
+			},
+			expectConfig: true,
+		},
+		{
+			name:      "confirmation accepted",
+			runMethod: config.RunMethod_RUN_METHOD_BINARY,
+			confirm:   true,
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, removed *[]string) {
+				b.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				b.EXPECT().UninstallSteps().Return(recordingSteps(removed, "pid file"))
+			},
+			expectRemoved: true,
+		},
+		{
+			name:      "step failure aborts",
+			runMethod: config.RunMethod_RUN_METHOD_DOCKER,
+			args:      []string{"--non-interactive"},
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, removed *[]string) {
+				d.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+				d.EXPECT().Stop(gomock.Any()).Return(errors.New("stop failed"))
+				d.EXPECT().UninstallSteps().Return(recordingSteps(removed, "compose project"))
+			},
+			expectedError: "stop failed",
+			expectConfig:  true,
+		},
+		{
+			name:          "named instances block config removal",
+			runMethod:     config.RunMethod_RUN_METHOD_DOCKER,
+			args:          []string{"--non-interactive"},
+			namedInstance: true,
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, removed *[]string) {
+				d.EXPECT().UninstallSteps().Return(recordingSteps(removed, "compose project"))
+			},
+			expectedError: "other instances are installed",
+			expectConfig:  true,
+		},
+		{
+			name:          "named instances keep the shared directories",
+			runMethod:     config.RunMethod_RUN_METHOD_DOCKER,
+			args:          []string{"--non-interactive", "--keep-config"},
+			namedInstance: true,
+			setupMocks: func(d *mock.MockDockerSidecar, b *mock.MockBinarySidecar, removed *[]string) {
+				d.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
+				d.EXPECT().UninstallSteps().Return(recordingSteps(removed, "compose project"))
+			},
+			expectRemoved: true,
+			expectShared:  true,
+			expectConfig:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := test.SuppressOutput(t)
+			defer cleanup()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var (
+				tmpDir     = t.TempDir()
+				installDir = filepath.Join(tmpDir, ".contributoor")
+				configPath = filepath.Join(installDir, "config.yaml")
+				removed    []string
+			)
+
+			for _, dir := range []string{"bin", "releases", "logs"} {
+				require.NoError(t, os.MkdirAll(filepath.Join(installDir, dir), 0755))
+			}
+
+			require.NoError(t, os.WriteFile(configPath, []byte("version: latest\n"), 0600))
+
+			if tt.namedInstance {
+				instanceDir := sidecar.InstanceConfigPath(installDir, "holesky")
+				require.NoError(t, os.MkdirAll(instanceDir, 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(instanceDir, "config.yaml"), []byte("version: latest\n"), 0600))
+			}
+
+			mockConfig := mock.NewMockConfigManager(ctrl)
+			mockConfig.EXPECT().Get().Return(&config.Config{
+				RunMethod:             tt.runMethod,
+				ContributoorDirectory: installDir,
+			}).AnyTimes()
+			mockConfig.EXPECT().GetConfigPath().Return(configPath).AnyTimes()
+
+			mockDocker := mock.NewMockDockerSidecar(ctrl)
+			mockBinary := mock.NewMockBinarySidecar(ctrl)
+			mockSystemd := mock.NewMockSystemdSidecar(ctrl)
+
+			tt.setupMocks(mockDocker, mockBinary, &removed)
+
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.Bool("keep-config", false, "")
+			set.Bool("dry-run", false, "")
+			set.Bool("non-interactive", false, "")
+			require.NoError(t, set.Parse(tt.args))
+
+			confirmResponse = tt.confirm
+
+			app := cli.NewApp()
+
+			runner := map[config.RunMethod]sidecar.SidecarRunner{
+				config.RunMethod_RUN_METHOD_DOCKER:  mockDocker,
+				config.RunMethod_RUN_METHOD_SYSTEMD: mockSystemd,
+				config.RunMethod_RUN_METHOD_BINARY:  mockBinary,
+			}[tt.runMethod]
+
+			err := uninstallContributoor(cli.NewContext(app, set, nil), logrus.New(), mockConfig, runner)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.expectRemoved && !tt.expectShared {
+				assert.NotEmpty(t, removed)
+				assert.NoDirExists(t, filepath.Join(installDir, "bin"))
+				assert.NoDirExists(t, filepath.Join(installDir, "releases"))
+			} else {
+				assert.Equal(t, tt.expectRemoved, len(removed) > 0)
+				assert.DirExists(t, filepath.Join(installDir, "bin"))
+				assert.DirExists(t, filepath.Join(installDir, "releases"))
+			}
+
+			if tt.expectConfig {
+				assert.FileExists(t, configPath)
+			} else {
+				assert.NoDirExists(t, installDir)
+			}
+		})
+	}
+}
+
+func TestCheckRemovable(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	assert.Error(t, checkRemovable("/"))
+	assert.Error(t, checkRemovable(home))
+	assert.Error(t, checkRemovable(home+"/"))
+	assert.NoError(t, checkRemovable(filepath.Join(home, ".contributoor")))
+}
+
+// recordingSteps returns uninstall steps that record their description when run.
+func recordingSteps(removed *[]string, descriptions ...string) []sidecar.UninstallStep {
+	steps := make([]sidecar.UninstallStep, 0, len(descriptions))
+
+	for _, description := range descriptions {
+		steps = append(steps, sidecar.UninstallStep{
+			Description: description,
+			Run: func(ctx context.Context) error {
+				*removed = append(*removed, description)
+
+				return nil
+			},
+		})
+	}
+
+	return steps
+}
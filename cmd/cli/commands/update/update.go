@@ -18,30 +18,26 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 		Aliases:   opts.Aliases(),
 		Usage:     "Update Contributoor to the latest version",
 		UsageText: "contributoor update [options]",
+		Flags:     []cli.Flag{options.InstanceFlag()},
 		Action: func(c *cli.Context) error {
 			var (
 				log          = opts.Logger()
 				installerCfg = opts.InstallerConfig()
 			)
 
-			sidecarCfg, err := sidecar.NewConfigService(log, c.String("config-path"))
+			configPath, err := opts.ConfigPath(c)
 			if err != nil {
 				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			dockerSidecar, err := sidecar.NewDockerSidecar(log, sidecarCfg, installerCfg)
+			sidecarCfg, err := sidecar.NewConfigService(log, configPath)
 			if err != nil {
-				return fmt.Errorf("error creating docker sidecar service: %w", err)
-			}
-
-			systemdSidecar, err := sidecar.NewSystemdSidecar(log, sidecarCfg, installerCfg)
-			if err != nil {
-				return fmt.Errorf("error creating systemd sidecar service: %w", err)
+				return fmt.Errorf("%s%v%s", tui.TerminalColorRed, err, tui.TerminalColorReset)
 			}
 
-			binarySidecar, err := sidecar.NewBinarySidecar(log, sidecarCfg, installerCfg)
+			runner, err := sidecar.ResolveRunner(log, sidecarCfg, installerCfg)
 			if err != nil {
-				return fmt.Errorf("error creating binary sidecar service: %w", err)
+				return err
 			}
 
 			githubService, err := service.NewGitHubService(log, installerCfg)
@@ -49,7 +45,7 @@ func RegisterCommands(app *cli.App, opts *options.CommandOpts) {
 				return fmt.Errorf("error creating github service: %w", err)
 			}
 
-			return updateContributoor(c, log, sidecarCfg, dockerSidecar, systemdSidecar, binarySidecar, githubService)
+			return updateContributoor(c, log, sidecarCfg, runner, githubService)
 		},
 	})
 }
@@ -58,32 +54,18 @@ func updateContributoor(
 	c *cli.Context,
 	log *logrus.Logger,
 	sidecarCfg sidecar.ConfigManager,
-	docker sidecar.DockerSidecar,
-	systemd sidecar.SystemdSidecar,
-	binary sidecar.BinarySidecar,
+	runner sidecar.SidecarRunner,
 	github service.GitHubService,
 ) error {
 	var (
 		success bool
 		cfg     = sidecarCfg.Get()
 		err     error
-		runner  sidecar.SidecarRunner
 	)
 
 	fmt.Printf("%sUpdating Contributoor Version%s\n", tui.TerminalColorLightBlue, tui.TerminalColorReset)
 
-	switch cfg.RunMethod {
-	case config.RunMethod_RUN_METHOD_DOCKER:
-		runner = docker
-	case config.RunMethod_RUN_METHOD_SYSTEMD:
-		runner = systemd
-	case config.RunMethod_RUN_METHOD_BINARY:
-		runner = binary
-	default:
-		return fmt.Errorf("invalid sidecar run method: %s", cfg.RunMethod)
-	}
-
-	current, latest, needsUpdate, err := sidecar.CheckVersion(runner, github, cfg.Version)
+	current, latest, needsUpdate, err := sidecar.CheckVersion(c.Context, runner, github, cfg.Version)
 	if err != nil {
 		return err
 	}
@@ -122,7 +104,7 @@ func updateContributoor(
 	cfg = sidecarCfg.Get()
 
 	// Update the sidecar.
-	success, err = updateSidecar(c, log, cfg, docker, systemd, binary)
+	success, err = updateSidecar(c, log, cfg, runner)
 	if err != nil {
 		return err
 	}
@@ -130,83 +112,35 @@ func updateContributoor(
 	return nil
 }
 
+// updateSidecar updates the sidecar through its runner, whatever the run method. A
+// running sidecar is stopped so its binary or image can be replaced, and started
+// again once updated.
 func updateSidecar(
 	c *cli.Context,
 	log *logrus.Logger,
 	cfg *config.Config,
-	docker sidecar.DockerSidecar,
-	systemd sidecar.SystemdSidecar,
-	binary sidecar.BinarySidecar,
+	runner sidecar.SidecarRunner,
 ) (bool, error) {
-	switch cfg.RunMethod {
-	case config.RunMethod_RUN_METHOD_DOCKER:
-		return updateDocker(c, log, cfg, docker)
-	case config.RunMethod_RUN_METHOD_SYSTEMD:
-		return updateSystemd(c, log, cfg, systemd)
-	case config.RunMethod_RUN_METHOD_BINARY:
-		return updateBinary(c, log, cfg, binary)
-	default:
-		return false, fmt.Errorf("invalid sidecar run method: %s", cfg.RunMethod)
-	}
-}
-
-func updateSystemd(c *cli.Context, log *logrus.Logger, cfg *config.Config, systemd sidecar.SystemdSidecar) (bool, error) {
-	// Check if sidecar is currently running.
-	running, err := systemd.IsRunning()
-	if err != nil {
-		return false, err
-	}
-
-	// If the sidecar is running, we need to stop it before we can update the binary.
-	if running {
-		if err := systemd.Stop(); err != nil {
-			return false, fmt.Errorf("failed to stop sidecar: %w", err)
-		}
-	}
-
-	if err := systemd.Update(); err != nil {
-		return false, err
-	}
-
-	fmt.Printf("%sContributoor updated successfully to version %s%s\n", tui.TerminalColorGreen, cfg.Version, tui.TerminalColorReset)
-
-	// If it was running, start it again for them.
-	if running {
-		if c.Bool("non-interactive") || tui.Confirm("Would you like to restart Contributoor with the new version?") {
-			if err := systemd.Start(); err != nil {
-				return true, fmt.Errorf("failed to start sidecar: %w", err)
-			}
-		} else {
-			fmt.Printf("%sContributoor will remain stopped until manually started%s\n", tui.TerminalColorYellow, tui.TerminalColorReset)
-		}
-	}
-
-	return true, nil
-}
-
-func updateBinary(c *cli.Context, log *logrus.Logger, cfg *config.Config, binary sidecar.BinarySidecar) (bool, error) {
-	// Check if sidecar is currently running.
-	running, err := binary.IsRunning()
+	running, err := runner.IsRunning(c.Context)
 	if err != nil {
 		log.Errorf("could not check sidecar status: %v", err)
 
 		return false, err
 	}
 
-	// If the sidecar is running, we need to stop it before we can update the binary.
 	if running {
-		if c.Bool("non-interactive") || tui.Confirm("Contributoor is running. In order to update, it must be stopped. Would you like to stop it?") {
-			if err := binary.Stop(); err != nil {
-				return false, fmt.Errorf("failed to stop sidecar: %w", err)
-			}
-		} else {
+		if !c.Bool("non-interactive") && !tui.Confirm("Contributoor is running. In order to update, it must be stopped. Would you like to stop it?") {
 			fmt.Printf("%sUpdate process was cancelled%s\n", tui.TerminalColorRed, tui.TerminalColorReset)
 
 			return false, nil
 		}
+
+		if err := runner.Stop(c.Context); err != nil {
+			return false, fmt.Errorf("failed to stop sidecar: %w", err)
+		}
 	}
 
-	if err := binary.Update(); err != nil {
+	if err := runner.Update(c.Context); err != nil {
 		log.Errorf("could not update sidecar: %v", err)
 
 		return false, err
@@ -216,7 +150,7 @@ func updateBinary(c *cli.Context, log *logrus.Logger, cfg *config.Config, binary
 
 	// If it was running, start it again for them.
 	if running {
-		if err := binary.Start(); err != nil {
+		if err := runner.Start(c.Context); err != nil {
 			return true, fmt.Errorf("failed to start sidecar: %w", err)
 		}
 	}
@@ -224,49 +158,6 @@ func updateBinary(c *cli.Context, log *logrus.Logger, cfg *config.Config, binary
 	return true, nil
 }
 
-func updateDocker(c *cli.Context, log *logrus.Logger, cfg *config.Config, docker sidecar.DockerSidecar) (bool, error) {
-	if err := docker.Update(); err != nil {
-		log.Errorf("could not update service: %v", err)
-
-		return false, err
-	}
-
-	fmt.Printf("%sContributoor updated successfully to version %s%s", tui.TerminalColorGreen, cfg.Version, tui.TerminalColorReset)
-
-	// Check if service is currently running.
-	running, err := docker.IsRunning()
-	if err != nil {
-		log.Errorf("could not check sidecar status: %v", err)
-
-		return true, err
-	}
-
-	fmt.Printf("\n")
-
-	// If the service is running, we need to restart it with the new version.
-	if running {
-		if c.Bool("non-interactive") || tui.Confirm("Contributoor is running. Would you like to restart it with the new version?") {
-			if err := docker.Stop(); err != nil {
-				return true, fmt.Errorf("failed to stop sidecar: %w", err)
-			}
-
-			if err := docker.Start(); err != nil {
-				return true, fmt.Errorf("failed to start sidecar: %w", err)
-			}
-		} else {
-			fmt.Printf("%sContributoor will continue running with the previous version until next restart%s\n", tui.TerminalColorYellow, tui.TerminalColorReset)
-		}
-	} else {
-		if c.Bool("non-interactive") || tui.Confirm("Contributoor is not running. Would you like to start it?") {
-			if err := docker.Start(); err != nil {
-				return true, fmt.Errorf("failed to start service: %w", err)
-			}
-		}
-	}
-
-	return true, nil
-}
-
 func updateConfigVersion(sidecarCfg sidecar.ConfigManager, version string) error {
 	if err := sidecarCfg.Update(func(cfg *config.Config) {
 		cfg.Version = version
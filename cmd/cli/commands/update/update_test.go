@@ -7,6 +7,7 @@ import (
 
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
 	smock "github.com/ethpandaops/contributoor-installer/internal/service/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
 	"github.com/ethpandaops/contributoor-installer/internal/test"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
@@ -55,19 +56,17 @@ func TestUpdateContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
 					Version:   "v1.0.0",
 				}).Times(2)
-				g.EXPECT().GetLatestVersion().Return("v1.1.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.1.0", nil)
 
 				// Expect a call to update, which in-turn updates + saves the config.
-				d.EXPECT().Update().Return(nil)
 				cfg.EXPECT().Update(gomock.Any()).Return(nil)
 				cfg.EXPECT().Save().Return(nil)
 
-				// Finally, a call is made to see if the service is running.
-				d.EXPECT().IsRunning().Return(true, nil)
-
-				// If it is, we expect it to be stopped and started.
-				d.EXPECT().Stop().Return(nil)
-				d.EXPECT().Start().Return(nil)
+				// The running service is stopped for the update, then started again.
+				d.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+				d.EXPECT().Stop(gomock.Any()).Return(nil)
+				d.EXPECT().Update(gomock.Any()).Return(nil)
+				d.EXPECT().Start(gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -79,7 +78,7 @@ func TestUpdateContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
 					Version:   "v1.0.0",
 				}).Times(1)
-				g.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
 			},
 		},
 		{
@@ -92,10 +91,13 @@ func TestUpdateContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
 					Version:   "v1.0.0",
 				}).Times(2)
-				g.EXPECT().GetLatestVersion().Return("v1.1.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.1.0", nil)
+
+				// Expect check if service is running.
+				d.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
 
 				// Expect a call to update, which in-turn updates + saves the config.
-				d.EXPECT().Update().Return(errors.New("update failed"))
+				d.EXPECT().Update(gomock.Any()).Return(errors.New("update failed"))
 				cfg.EXPECT().Update(gomock.Any()).Return(nil)
 				cfg.EXPECT().Save().Return(nil)
 
@@ -114,7 +116,7 @@ func TestUpdateContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_BINARY,
 					Version:   "v1.0.0",
 				}).Times(1)
-				g.EXPECT().GetLatestVersion().Return("v1.0.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.0.0", nil)
 			},
 		},
 		{
@@ -127,13 +129,13 @@ func TestUpdateContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_BINARY,
 					Version:   "v1.0.0",
 				}).Times(2)
-				g.EXPECT().GetLatestVersion().Return("v1.1.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.1.0", nil)
 
 				// Expect check if service is running.
-				b.EXPECT().IsRunning().Return(false, nil)
+				b.EXPECT().IsRunning(gomock.Any()).Return(false, nil)
 
 				// Expect a call to update, which in-turn updates + saves the config.
-				b.EXPECT().Update().Return(errors.New("update failed"))
+				b.EXPECT().Update(gomock.Any()).Return(errors.New("update failed"))
 				cfg.EXPECT().Update(gomock.Any()).Return(nil)
 				cfg.EXPECT().Save().Return(nil)
 
@@ -143,6 +145,27 @@ func TestUpdateContributoor(t *testing.T) {
 			},
 			expectedError: "update failed",
 		},
+		{
+			name:           "binary - declining to stop cancels the update",
+			runMethod:      config.RunMethod_RUN_METHOD_BINARY,
+			confirmPrompt:  false,
+			nonInteractive: false,
+			setupMocks: func(cfg *mock.MockConfigManager, d *mock.MockDockerSidecar, s *mock.MockSystemdSidecar, b *mock.MockBinarySidecar, g *smock.MockGitHubService) {
+				cfg.EXPECT().Get().Return(&config.Config{
+					RunMethod: config.RunMethod_RUN_METHOD_BINARY,
+					Version:   "v1.0.0",
+				}).Times(2)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.1.0", nil)
+				cfg.EXPECT().Update(gomock.Any()).Return(nil)
+				cfg.EXPECT().Save().Return(nil)
+
+				b.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+
+				// Nothing was updated, so the config version is rolled back.
+				cfg.EXPECT().Update(gomock.Any()).Return(nil)
+				cfg.EXPECT().Save().Return(nil)
+			},
+		},
 		{
 			name:           "docker - non-interactive mode auto-restarts",
 			runMethod:      config.RunMethod_RUN_METHOD_DOCKER,
@@ -152,17 +175,17 @@ func TestUpdateContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
 					Version:   "v1.0.0",
 				}).Times(2)
-				g.EXPECT().GetLatestVersion().Return("v1.1.0", nil)
-				d.EXPECT().Update().Return(nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.1.0", nil)
 				cfg.EXPECT().Update(gomock.Any()).Return(nil)
 				cfg.EXPECT().Save().Return(nil)
 
 				// Check if service is running.
-				d.EXPECT().IsRunning().Return(true, nil)
+				d.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
 
-				// In non-interactive mode, should auto-restart.
-				d.EXPECT().Stop().Return(nil)
-				d.EXPECT().Start().Return(nil)
+				// In non-interactive mode, should stop, update and restart without asking.
+				d.EXPECT().Stop(gomock.Any()).Return(nil)
+				d.EXPECT().Update(gomock.Any()).Return(nil)
+				d.EXPECT().Start(gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -174,21 +197,21 @@ func TestUpdateContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_SYSTEMD,
 					Version:   "v1.0.0",
 				}).Times(2)
-				g.EXPECT().GetLatestVersion().Return("v1.1.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.1.0", nil)
 
 				// Check if service is running.
-				s.EXPECT().IsRunning().Return(true, nil)
+				s.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
 
 				// Should stop before update.
-				s.EXPECT().Stop().Return(nil)
+				s.EXPECT().Stop(gomock.Any()).Return(nil)
 
 				// Expect update.
-				s.EXPECT().Update().Return(nil)
+				s.EXPECT().Update(gomock.Any()).Return(nil)
 				cfg.EXPECT().Update(gomock.Any()).Return(nil)
 				cfg.EXPECT().Save().Return(nil)
 
 				// In non-interactive mode, should auto-restart.
-				s.EXPECT().Start().Return(nil)
+				s.EXPECT().Start(gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -200,21 +223,21 @@ func TestUpdateContributoor(t *testing.T) {
 					RunMethod: config.RunMethod_RUN_METHOD_BINARY,
 					Version:   "v1.0.0",
 				}).Times(2)
-				g.EXPECT().GetLatestVersion().Return("v1.1.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.1.0", nil)
 
 				// Check if service is running.
-				b.EXPECT().IsRunning().Return(true, nil)
+				b.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
 
 				// Should stop before update.
-				b.EXPECT().Stop().Return(nil)
+				b.EXPECT().Stop(gomock.Any()).Return(nil)
 
 				// Expect update.
-				b.EXPECT().Update().Return(nil)
+				b.EXPECT().Update(gomock.Any()).Return(nil)
 				cfg.EXPECT().Update(gomock.Any()).Return(nil)
 				cfg.EXPECT().Save().Return(nil)
 
 				// In non-interactive mode, should auto-restart.
-				b.EXPECT().Start().Return(nil)
+				b.EXPECT().Start(gomock.Any()).Return(nil)
 			},
 		},
 	}
@@ -252,7 +275,13 @@ func TestUpdateContributoor(t *testing.T) {
 
 			context := cli.NewContext(app, set, nil)
 
-			err := updateContributoor(context, logrus.New(), mockConfig, mockDocker, mockSystemd, mockBinary, mockGithub)
+			runner := map[config.RunMethod]sidecar.SidecarRunner{
+				config.RunMethod_RUN_METHOD_DOCKER:  mockDocker,
+				config.RunMethod_RUN_METHOD_SYSTEMD: mockSystemd,
+				config.RunMethod_RUN_METHOD_BINARY:  mockBinary,
+			}[tt.runMethod]
+
+			err := updateContributoor(context, logrus.New(), mockConfig, runner, mockGithub)
 
 			if tt.expectedError != "" {
 				assert.ErrorContains(t, err, tt.expectedError)
@@ -265,6 +294,36 @@ func TestUpdateContributoor(t *testing.T) {
 	}
 }
 
+func TestUpdateContributoor_RegisteredRunner(t *testing.T) {
+	cleanup := test.SuppressOutput(t)
+	defer cleanup()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A backend registered outside this package is updated through its runner like
+	// any other.
+	mockConfig := mock.NewMockConfigManager(ctrl)
+	mockConfig.EXPECT().Get().Return(&config.Config{RunMethod: config.RunMethod(100), Version: "v1.0.0"}).Times(2)
+	mockConfig.EXPECT().Update(gomock.Any()).Return(nil)
+	mockConfig.EXPECT().Save().Return(nil)
+
+	mockGithub := smock.NewMockGitHubService(ctrl)
+	mockGithub.EXPECT().GetLatestVersion(gomock.Any()).Return("v1.1.0", nil)
+
+	runner := mock.NewMockSidecarRunner(ctrl)
+	runner.EXPECT().IsRunning(gomock.Any()).Return(true, nil)
+	runner.EXPECT().Stop(gomock.Any()).Return(nil)
+	runner.EXPECT().Update(gomock.Any()).Return(nil)
+	runner.EXPECT().Start(gomock.Any()).Return(nil)
+
+	set := flag.NewFlagSet("test", 0)
+	set.Bool("non-interactive", true, "")
+
+	err := updateContributoor(cli.NewContext(cli.NewApp(), set, nil), logrus.New(), mockConfig, runner, mockGithub)
+	require.NoError(t, err)
+}
+
 func TestRegisterCommands(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -8,16 +9,23 @@ import (
 	"syscall"
 
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/config"
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/doctor"
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/install"
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/instances"
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/logs"
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/restart"
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/start"
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/status"
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/stop"
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/supervise"
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/switchrunmethod"
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/systemd"
+	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/uninstall"
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/commands/update"
 	"github.com/ethpandaops/contributoor-installer/cmd/cli/options"
 	"github.com/ethpandaops/contributoor-installer/internal/installer"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/mitchellh/go-homedir"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
@@ -45,14 +53,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	// Handle cleanup on exit.
+	// Cancel the root context on exit signals, so in-flight operations can clean up
+	// after themselves rather than being killed part way through.
 	go func() {
 		<-sigChan
-		log.Info("Received exit signal")
-		os.Exit(0)
+		log.Info("Received exit signal, cleaning up")
+		cancel()
+
+		// A second signal exits immediately.
+		signal.Reset(syscall.SIGINT, syscall.SIGTERM)
 	}()
 
 	cli.AppHelpTemplate = tui.AppHelpTemplate
@@ -70,6 +85,7 @@ func main() {
 			Name:  "non-interactive",
 			Usage: "Skip all interactive prompts and use default values",
 		},
+		options.InstanceFlag(),
 		&cli.BoolFlag{
 			Name:  "release, r",
 			Usage: "Print release and exit",
@@ -82,12 +98,19 @@ func main() {
 			os.Exit(0)
 		}
 
-		return nil
+		configDir, expandErr := homedir.Expand(c.String("config-path"))
+		if expandErr != nil {
+			return fmt.Errorf("failed to expand config path: %w", expandErr)
+		}
+
+		// Overlay any installer settings from the config directory, eg: containerRuntime.
+		return installerCfg.LoadFile(filepath.Join(configDir, installer.ConfigFilename))
 	}
 
 	install.RegisterCommands(app, options.NewCommandOpts(
 		options.WithName("install"),
 		options.WithLogger(log),
+		options.WithInstallerConfig(installerCfg),
 	))
 
 	start.RegisterCommands(app, options.NewCommandOpts(
@@ -123,11 +146,49 @@ func main() {
 	config.RegisterCommands(app, options.NewCommandOpts(
 		options.WithName("config"),
 		options.WithLogger(log),
+		options.WithInstallerConfig(installerCfg),
 	))
 
 	logs.RegisterCommands(app, options.NewCommandOpts(
 		options.WithName("logs"),
 		options.WithLogger(log),
+		options.WithInstallerConfig(installerCfg),
+	))
+
+	doctor.RegisterCommands(app, options.NewCommandOpts(
+		options.WithName("doctor"),
+		options.WithLogger(log),
+		options.WithInstallerConfig(installerCfg),
+	))
+
+	uninstall.RegisterCommands(app, options.NewCommandOpts(
+		options.WithName("uninstall"),
+		options.WithLogger(log),
+		options.WithInstallerConfig(installerCfg),
+	))
+
+	instances.RegisterCommands(app, options.NewCommandOpts(
+		options.WithName("instances"),
+		options.WithLogger(log),
+		options.WithInstallerConfig(installerCfg),
+	))
+
+	switchrunmethod.RegisterCommands(app, options.NewCommandOpts(
+		options.WithName("switch-run-method"),
+		options.WithLogger(log),
+		options.WithInstallerConfig(installerCfg),
+	))
+
+	systemd.RegisterCommands(app, options.NewCommandOpts(
+		options.WithName("systemd"),
+		options.WithLogger(log),
+		options.WithInstallerConfig(installerCfg),
+	))
+
+	supervise.RegisterCommands(app, options.NewCommandOpts(
+		options.WithName("supervise"),
+		options.WithLogger(log),
+		options.WithInstallerConfig(installerCfg),
 	))
 
 	// Handle normal exit.
@@ -137,8 +198,15 @@ func main() {
 
 	fmt.Println("")
 
-	if err := app.Run(os.Args); err != nil {
+	if err := app.RunContext(ctx, os.Args); err != nil {
 		log.Error(err)
+		fmt.Println("")
+
+		if ctx.Err() != nil {
+			os.Exit(130)
+		}
+
+		os.Exit(1)
 	}
 
 	fmt.Println("")
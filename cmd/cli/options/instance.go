@@ -0,0 +1,69 @@
+package options
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/validate"
+	"github.com/mitchellh/go-homedir"
+	"github.com/urfave/cli/v2"
+)
+
+// InstanceFlagName is the name of the flag selecting a named instance.
+const InstanceFlagName = "instance"
+
+// InstanceFlag returns the flag selecting a named instance. It's accepted both
+// before and after the command name.
+func InstanceFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    InstanceFlagName,
+		Usage:   "Manage the named contributoor `instance` instead of the default one",
+		EnvVars: []string{"CONTRIBUTOOR_INSTANCE"},
+	}
+}
+
+// InstanceName returns the instance selected with --instance, or "" for the default
+// instance. The flag on the command shadows the global one, so the first context
+// in the lineage where it's set wins.
+func InstanceName(c *cli.Context) string {
+	for _, ctx := range c.Lineage() {
+		if ctx.IsSet(InstanceFlagName) {
+			return ctx.String(InstanceFlagName)
+		}
+	}
+
+	return ""
+}
+
+// ConfigPath returns the config directory of the instance selected with --instance.
+// For named instances, it also records the instance on the installer config and
+// overlays the instance's own installer settings.
+func (o *CommandOpts) ConfigPath(c *cli.Context) (string, error) {
+	instance := InstanceName(c)
+	if instance == "" {
+		return c.String("config-path"), nil
+	}
+
+	if err := validate.ValidateInstanceName(instance); err != nil {
+		return "", err
+	}
+
+	configPath := sidecar.InstanceConfigPath(c.String("config-path"), instance)
+
+	if o.installerCfg != nil {
+		o.installerCfg.Instance = instance
+
+		dir, err := homedir.Expand(configPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to expand config path: %w", err)
+		}
+
+		if err := o.installerCfg.LoadFile(filepath.Join(dir, installer.ConfigFilename)); err != nil {
+			return "", err
+		}
+	}
+
+	return configPath, nil
+}
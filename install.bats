@@ -394,12 +394,6 @@ EOF
         touch "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/contributoor"
         chmod +x "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/contributoor"
         
-        # Create compose files
-        touch "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.yml"
-        touch "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.metrics.yml"
-        touch "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.health.yml"
-        touch "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.network.yml"
-        
         return 0
     }
 
@@ -409,14 +403,6 @@ EOF
             # Create the symlink target
             touch "$3"
             chmod +x "$3"
-            
-            # Also create compose files in the same directory if it's the binary symlink
-            if [[ "$3" == *"/bin/contributoor" ]]; then
-                cp "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.yml" "$(dirname "$3")/docker-compose.yml"
-                cp "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.metrics.yml" "$(dirname "$3")/docker-compose.metrics.yml"
-                cp "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.health.yml" "$(dirname "$3")/docker-compose.health.yml"
-                cp "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.network.yml" "$(dirname "$3")/docker-compose.network.yml"
-            fi
         fi
         return 0
     }
@@ -433,10 +419,6 @@ EOF
     [ -x "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/contributoor" ]
     [ -f "$CONTRIBUTOOR_PATH/bin/contributoor" ]
     [ -x "$CONTRIBUTOOR_PATH/bin/contributoor" ]
-    [ -f "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.yml" ]
-    [ -f "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.metrics.yml" ]
-    [ -f "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.health.yml" ]
-    [ -f "$CONTRIBUTOOR_PATH/releases/installer-${CONTRIBUTOOR_VERSION}/docker-compose.network.yml" ]
 }
 
 @test "setup_installer fails on checksum mismatch" {
@@ -732,35 +714,50 @@ EOF
     [ -z "$output" ]
 }
 
-@test "[linux] setup_systemd_contributoor creates service file correctly" {
+@test "[linux] setup_systemd_contributoor installs the unit with the contributoor CLI" {
     # Set platform for test
     function detect_platform() {
         echo "linux"
     }
     export -f detect_platform
 
-    # Mock sudo to capture commands
-    function sudo() {
-        case "$1" in
-            "tee")
-                # Capture service file content
-                cat > "$TEST_DIR/contributoor.service"
-                ;;
-            *) return 0 ;;
-        esac
-    }
+    function sudo() { return 0; }
     export -f sudo
 
+    # Fake CLI recording its arguments
+    mkdir -p "$CONTRIBUTOOR_BIN"
+    printf '#!/bin/sh\necho "$@" > "%s/args"\n' "$TEST_DIR" > "$CONTRIBUTOOR_BIN/contributoor"
+    chmod +x "$CONTRIBUTOOR_BIN/contributoor"
+
     run setup_systemd_contributoor
 
     # Check status
     [ "$status" -eq 0 ]
 
-    # Verify service file was created and has correct content
-    [ -f "$TEST_DIR/contributoor.service" ]
-    grep -q "Description=Contributoor Service" "$TEST_DIR/contributoor.service"
-    grep -q "User=$USER" "$TEST_DIR/contributoor.service"
-    grep -q "ExecStart=$CONTRIBUTOOR_BIN/sentry" "$TEST_DIR/contributoor.service"
+    # Verify the unit was rendered by the CLI for this config path
+    [ "$(cat "$TEST_DIR/args")" = "--config-path $CONTRIBUTOOR_PATH systemd reinstall-unit" ]
+    echo "$output" | grep -q "Created systemd service: /etc/systemd/system/contributoor.service"
+}
+
+@test "[linux] setup_systemd_contributoor fails when the CLI can't install the unit" {
+    # Set platform for test
+    function detect_platform() {
+        echo "linux"
+    }
+    export -f detect_platform
+
+    function sudo() { return 0; }
+    export -f sudo
+
+    # Fake CLI failing to render the unit
+    mkdir -p "$CONTRIBUTOOR_BIN"
+    printf '#!/bin/sh\nexit 1\n' > "$CONTRIBUTOOR_BIN/contributoor"
+    chmod +x "$CONTRIBUTOOR_BIN/contributoor"
+
+    run setup_systemd_contributoor
+
+    [ "$status" -eq 1 ]
+    echo "$output" | grep -q "Could not create the systemd service"
 }
 
 @test "[linux] setup_systemd_contributoor removes any existing systemd service before installing" {
@@ -784,6 +781,10 @@ EOF
     }
     export -f sudo
 
+    mkdir -p "$CONTRIBUTOOR_BIN"
+    printf '#!/bin/sh\nexit 0\n' > "$CONTRIBUTOOR_BIN/contributoor"
+    chmod +x "$CONTRIBUTOOR_BIN/contributoor"
+
     run setup_systemd_contributoor
 
     # Check status
@@ -793,6 +794,51 @@ EOF
     echo "$output" | grep -q "Stopped and disabled existing systemd service"
 }
 
+@test "[linux] setup_systemd_contributoor installs the user unit without sudo" {
+    # Set platform for test
+    function detect_platform() {
+        echo "linux"
+    }
+    export -f detect_platform
+
+    export SYSTEMD_SCOPE="user"
+    export XDG_CONFIG_HOME="$TEST_DIR/config"
+
+    # Fail if sudo is used at all
+    function sudo() { return 1; }
+    function systemctl() {
+        [ "$1" = "--user" ] || return 1
+        return 0
+    }
+    export -f sudo systemctl
+
+    # Fake CLI recording its arguments
+    mkdir -p "$CONTRIBUTOOR_BIN"
+    printf '#!/bin/sh\necho "$@" > "%s/args"\n' "$TEST_DIR" > "$CONTRIBUTOOR_BIN/contributoor"
+    chmod +x "$CONTRIBUTOOR_BIN/contributoor"
+
+    run setup_systemd_contributoor
+
+    # Check status
+    [ "$status" -eq 0 ]
+
+    # Verify the CLI installed the user unit, and lingering was explained
+    [ "$(cat "$TEST_DIR/args")" = "--config-path $CONTRIBUTOOR_PATH systemd reinstall-unit" ]
+    echo "$output" | grep -q "Created systemd user service: $XDG_CONFIG_HOME/systemd/user/contributoor.service"
+    echo "$output" | grep -q "loginctl enable-linger"
+}
+
+@test "[linux] check_systemd_user fails without a user service manager" {
+    # Mock commands to simulate systemd without a user session
+    function pidof() { echo "1"; }
+    function systemctl() { return 1; }
+    export -f pidof systemctl
+
+    run check_systemd_user
+    [ "$status" -eq 1 ]
+    echo "$output" | grep -q "The systemd user service manager isn't available"
+}
+
 @test "[linux] check_systemd validates systemd availability" {
     # Set platform for test
     function detect_platform() {
@@ -1132,6 +1178,49 @@ EOF
     [ ! -d "$TEST_DIR/.contributoor" ]
 }
 
+@test "uninstall delegates to the contributoor CLI" {
+    mkdir -p "$TEST_DIR/.contributoor/bin"
+    touch "$TEST_DIR/.contributoor/config.yaml"
+    echo "export PATH=\"\$PATH:$TEST_DIR/.contributoor/bin\"" > "$TEST_DIR/.zshrc"
+    export HOME="$TEST_DIR"
+
+    # The fake CLI records its arguments, and removes the install like the real one.
+    cat > "$TEST_DIR/.contributoor/bin/contributoor" << EOF
+#!/bin/bash
+echo "\$@" > "$TEST_DIR/cli-args"
+rm -rf "$TEST_DIR/.contributoor"
+EOF
+    chmod +x "$TEST_DIR/.contributoor/bin/contributoor"
+
+    run bash -c '
+        source ./install.sh
+        CONFIG_PATH="$TEST_DIR/.contributoor/config.yaml" uninstall
+    '
+
+    [ "$status" -eq 0 ]
+    [ "$(cat "$TEST_DIR/cli-args")" = "--config-path $TEST_DIR/.contributoor uninstall" ]
+    ! grep -q "contributoor" "$TEST_DIR/.zshrc"
+}
+
+@test "uninstall keeps PATH when the contributoor CLI keeps its bin directory" {
+    mkdir -p "$TEST_DIR/.contributoor/bin"
+    touch "$TEST_DIR/.contributoor/config.yaml"
+    echo "export PATH=\"\$PATH:$TEST_DIR/.contributoor/bin\"" > "$TEST_DIR/.zshrc"
+    export HOME="$TEST_DIR"
+
+    printf '#!/bin/bash\necho "Uninstall cancelled"\n' > "$TEST_DIR/.contributoor/bin/contributoor"
+    chmod +x "$TEST_DIR/.contributoor/bin/contributoor"
+
+    run bash -c '
+        source ./install.sh
+        CONFIG_PATH="$TEST_DIR/.contributoor/config.yaml" uninstall
+    '
+
+    [ "$status" -eq 0 ]
+    echo "$output" | grep -q "Uninstall cancelled"
+    grep -q "contributoor" "$TEST_DIR/.zshrc"
+}
+
 @test "uninstall handles darwin platform correctly" {
     # Create test environment
     mkdir -p "$TEST_DIR/.contributoor"
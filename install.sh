@@ -211,25 +211,7 @@ setup_installer() {
     
     chmod +x "$release_dir/contributoor"
 
-    [ -f "$release_dir/docker-compose.yml" ] && {
-        chmod 644 "$release_dir/docker-compose.yml"
-        chmod 755 "$release_dir"
-    } || fail "docker-compose.yml not found after extraction"
-
-    [ -f "$release_dir/docker-compose.metrics.yml" ] && {
-        chmod 644 "$release_dir/docker-compose.metrics.yml"
-        chmod 755 "$release_dir"
-    } || fail "docker-compose.metrics.yml not found after extraction"
-
-    [ -f "$release_dir/docker-compose.health.yml" ] && {
-        chmod 644 "$release_dir/docker-compose.health.yml"
-        chmod 755 "$release_dir"
-    } || fail "docker-compose.health.yml not found after extraction"
-
-    [ -f "$release_dir/docker-compose.network.yml" ] && {
-        chmod 644 "$release_dir/docker-compose.network.yml"
-        chmod 755 "$release_dir"
-    } || fail "docker-compose.network.yml not found after extraction"
+    chmod 755 "$release_dir"
     
     # Create/update symlink
     rm -f "$CONTRIBUTOOR_BIN/contributoor" # Remove existing symlink or file
@@ -242,11 +224,17 @@ setup_installer() {
 }
 
 setup_docker_contributoor() {
-    docker pull "ethpandaops/contributoor:${CONTRIBUTOOR_VERSION}" >/dev/null 2>&1 &
+    local runtime="${CONTAINER_RUNTIME:-docker}"
+    local image="ethpandaops/contributoor:${CONTRIBUTOOR_VERSION}"
+
+    # Podman won't resolve short image names without a terminal.
+    [ "$runtime" = "podman" ] && image="docker.io/$image"
+
+    $runtime pull "$image" >/dev/null 2>&1 &
     spinner $!
     wait $!
-    [ $? -ne 0 ] && fail "Failed to pull docker image"
-    success "Pulled docker image: ethpandaops/contributoor:${CONTRIBUTOOR_VERSION}"
+    [ $? -ne 0 ] && fail "Failed to pull $runtime image"
+    success "Pulled $runtime image: $image"
 }
 
 setup_binary_contributoor() {
@@ -320,7 +308,11 @@ setup_systemd_contributoor() {
             setup_macos_launchd
             ;;
         *)
-            setup_linux_systemd
+            if [ "${SYSTEMD_SCOPE:-}" = "user" ]; then
+                setup_linux_systemd_user
+            else
+                setup_linux_systemd
+            fi
             ;;
     esac
 }
@@ -424,56 +416,62 @@ setup_linux_systemd() {
         success "Stopped and disabled existing systemd service"
     fi
 
-    # Create systemd directory
-    sudo mkdir -p "/etc/systemd/system"
+    # The unit is rendered by contributoor from config.yaml and installer.yaml, which
+    # also reloads systemd and enables (but doesn't start) the service. It can then be
+    # regenerated later with 'contributoor systemd reinstall-unit'.
+    install_systemd_unit
 
-    # Create the service file
-    sudo tee "/etc/systemd/system/contributoor.service" >/dev/null << EOF
-[Unit]
-Description=Contributoor Service
-After=network-online.target
-Wants=network-online.target
-StartLimitIntervalSec=0
-
-[Service]
-Type=simple
-User=$USER
-Group=$USER
-ExecStart=$CONTRIBUTOOR_BIN/sentry --config $CONTRIBUTOOR_PATH/config.yaml
-WorkingDirectory=$CONTRIBUTOOR_PATH
-Restart=always
-RestartSec=5
-
-# Environment setup
-Environment=HOME=$HOME
-Environment=USER=$USER
-Environment=PATH=/usr/local/bin:/usr/bin:/bin
-
-# Hardening
-NoNewPrivileges=true
-ProtectSystem=full
-ProtectHome=read-only
-PrivateTmp=true
-
-[Install]
-WantedBy=multi-user.target
-EOF
+    success "Created systemd service: /etc/systemd/system/contributoor.service"
+    success "Service configured for manual start"
+}
 
-    # Set permissions
-    sudo chmod 644 "/etc/systemd/system/contributoor.service"
+# Render and install the systemd unit for the selected scope with the contributoor CLI
+install_systemd_unit() {
+    "$CONTRIBUTOOR_BIN/contributoor" --config-path "$CONTRIBUTOOR_PATH" systemd reinstall-unit >/dev/null \
+        || fail "Could not create the systemd service"
+}
+
+# Setup Linux systemd user service, managed with systemctl --user so no sudo is needed
+setup_linux_systemd_user() {
+    local unit_dir="${XDG_CONFIG_HOME:-$HOME/.config}/systemd/user"
 
-    # Reload systemd
-    sudo systemctl daemon-reload
+    # Stop and disable existing service if it exists
+    if systemctl --user list-unit-files | grep -q "contributoor.service"; then
+        systemctl --user stop contributoor.service
+        systemctl --user disable contributoor.service >/dev/null 2>&1
 
-    # Enable but don't start the service
-    sudo systemctl enable contributoor.service >/dev/null 2>&1
+        # Remove existing service file and any leftover runtime files
+        rm -f "$unit_dir/contributoor.service"
+        rm -rf "$unit_dir/contributoor.service.d"
+        rm -f "$unit_dir/default.target.wants/contributoor.service"
 
-    success "Created systemd service: /etc/systemd/system/contributoor.service"
+        # Reload systemd to recognize the removal
+        systemctl --user daemon-reload
+
+        success "Stopped and disabled existing systemd user service"
+    fi
+
+    # The unit is rendered by contributoor, same as a system unit.
+    install_systemd_unit
+
+    success "Created systemd user service: $unit_dir/contributoor.service"
     success "Service configured for manual start"
+
+    # Without lingering, the user's service manager (and contributoor) stops on logout.
+    if [ ! -e "/var/lib/systemd/linger/$USER" ]; then
+        warn "Lingering isn't enabled for $USER, so contributoor stops when you log out and doesn't start at boot."
+        warn "To keep it running, run (or ask an administrator to run): loginctl enable-linger $USER"
+    fi
 }
 
-# Check if docker is installed and running
+# Check if docker (or podman) is installed and running
 check_docker() {
+    # Fall back to podman on hosts without docker.
+    if ! command -v docker >/dev/null 2>&1 && command -v podman >/dev/null 2>&1; then
+        check_podman
+        return
+    fi
+
     # Check if docker command exists
     if ! command -v docker >/dev/null 2>&1; then
         fail "Docker is not installed. Please install Docker first: https://docs.docker.com/get-docker/"
@@ -490,6 +488,19 @@ check_docker() {
     fi
 }
 
+# Check if podman is running, with a compose implementation
+check_podman() {
+    if ! podman info >/dev/null 2>&1; then
+        fail "Podman is not working. Please check 'podman info' and try again."
+    fi
+
+    if ! (command -v podman-compose >/dev/null 2>&1 || podman compose version >/dev/null 2>&1); then
+        fail "No compose implementation found for podman. Please install podman-compose: https://github.com/containers/podman-compose"
+    fi
+
+    CONTAINER_RUNTIME="podman"
+}
+
 # Check if systemd/launchd is available and running
 check_systemd_or_launchd() {
     case "$(detect_platform)" in
@@ -524,6 +535,24 @@ check_systemd_or_launchd() {
     esac
 }
 
+# Check if the systemd user service manager is available
+check_systemd_user() {
+    # Check if systemd is the init system
+    if ! pidof systemd >/dev/null 2>&1; then
+        fail "Systemd is not available on this system. Please choose a different installation mode."
+    fi
+
+    # Check if systemctl is available
+    if ! command -v systemctl >/dev/null 2>&1; then
+        fail "Systemctl command not found. Please choose a different installation mode."
+    fi
+
+    # The user's service manager is only reachable from a login session, not via su or sudo
+    if ! systemctl --user status >/dev/null 2>&1; then
+        fail "The systemd user service manager isn't available. Log in as $USER directly (not via su or sudo) or choose a different installation mode."
+    fi
+}
+
 ###############################################################################
 # Version Management
 ###############################################################################
@@ -599,6 +628,19 @@ EOF
     }
 }
 
+# Remove the PATH entry for a bin directory from the shell configs.
+remove_path_entries() {
+    local bin_path="$1"
+    for rc in "$HOME/.bashrc" "$HOME/.zshrc" "$HOME/.bash_profile" "$HOME/.profile"; do
+        if [ -f "$rc" ]; then
+            temp_file=$(mktemp)
+            grep -v "export PATH=.*$bin_path" "$rc" > "$temp_file"
+            mv "$temp_file" "$rc"
+            success "Cleaned PATH from $rc"
+        fi
+    done
+}
+
 uninstall() {
     # Try to determine the installation directory
     local install_dir=""
@@ -626,6 +668,23 @@ uninstall() {
         fi
     fi
 
+    CONTRIBUTOOR_PATH="$install_dir"
+    CONTRIBUTOOR_BIN="$CONTRIBUTOOR_PATH/bin"
+
+    # Leave the uninstall to contributoor, which knows how its run method was installed.
+    # The cleanup below is only for installs without the contributoor binary.
+    if [ -x "$CONTRIBUTOOR_BIN/contributoor" ]; then
+        "$CONTRIBUTOOR_BIN/contributoor" --config-path "$CONTRIBUTOOR_PATH" uninstall || exit 1
+
+        # The bin directory is kept if the uninstall was cancelled, or named instances
+        # still use it.
+        if [ ! -d "$CONTRIBUTOOR_BIN" ]; then
+            remove_path_entries "$CONTRIBUTOOR_BIN"
+        fi
+
+        exit 0
+    fi
+
     printf "\n${COLOR_RED}Warning, this will:${COLOR_RESET}\n"
     printf " • Stop and remove any contributoor services (systemd/launchd)\n"
     printf " • Stop and remove any contributoor Docker containers and images\n"
@@ -665,6 +724,17 @@ uninstall() {
                 sudo systemctl daemon-reload
                 success "Removed systemd service files"
             fi
+
+            local user_unit_dir="${XDG_CONFIG_HOME:-$HOME/.config}/systemd/user"
+            if [ -f "$user_unit_dir/contributoor.service" ]; then
+                systemctl --user stop contributoor.service >/dev/null 2>&1
+                systemctl --user disable contributoor.service >/dev/null 2>&1
+                rm -f "$user_unit_dir/contributoor.service"
+                rm -rf "$user_unit_dir/contributoor.service.d"
+                rm -f "$user_unit_dir/default.target.wants/contributoor.service"
+                systemctl --user daemon-reload >/dev/null 2>&1
+                success "Removed systemd user service files"
+            fi
             ;;
     esac
 
@@ -690,15 +760,7 @@ uninstall() {
     fi
 
     # Remove PATH entry from shell config
-    local bin_path="$install_dir/bin"
-    for rc in "$HOME/.bashrc" "$HOME/.zshrc" "$HOME/.bash_profile" "$HOME/.profile"; do
-        if [ -f "$rc" ]; then
-            temp_file=$(mktemp)
-            grep -v "export PATH=.*$bin_path" "$rc" > "$temp_file"
-            mv "$temp_file" "$rc"
-            success "Cleaned PATH from $rc"
-        fi
-    done
+    remove_path_entries "$install_dir/bin"
 
     # Remove contributoor directory using detected path
     if [ -d "$install_dir" ]; then
@@ -782,6 +844,9 @@ main() {
     # Installation mode selection
     if [ "${TEST_MODE:-}" != "true" ]; then
         selected=1
+        # Linux also offers systemd user services, which don't need sudo.
+        max_selected=3
+        [ "$(detect_platform)" != "darwin" ] && max_selected=4
         
         # Check if tput works with current terminal
         if ! tput clear >/dev/null 2>&1; then
@@ -807,15 +872,16 @@ main() {
                     ;;
                 *)
                     printf "  %s systemd\n" "$([ "$selected" = 2 ] && echo ">" || echo " ")"
+                    printf "  %s systemd-user (${COLOR_CYAN}no sudo${COLOR_RESET})\n" "$([ "$selected" = 3 ] && echo ">" || echo " ")"
                     ;;
             esac
-            printf "  %s binary (development)\n" "$([ "$selected" = 3 ] && echo ">" || echo " ")"
+            printf "  %s binary (development)\n" "$([ "$selected" = "$max_selected" ] && echo ">" || echo " ")"
             printf "\nUse arrow keys (↑/↓) or j/k to select, Enter to confirm\n"
             
             read -r -n1 key
             case "$key" in
                 A|k) [ "$selected" -gt 1 ] && selected=$((selected - 1)) ;;
-                B|j) [ "$selected" -lt 3 ] && selected=$((selected + 1)) ;;
+                B|j) [ "$selected" -lt "$max_selected" ] && selected=$((selected + 1)) ;;
                 "")
                     tput cnorm
                     printf "Selected: "
@@ -829,6 +895,12 @@ main() {
                         # Check systemd is available
                         check_systemd_or_launchd
                         printf "${COLOR_GREEN}systemd${COLOR_RESET}"
+                    elif [ "$selected" = 3 ] && [ "$max_selected" = 4 ]; then
+                        INSTALL_MODE="RUN_METHOD_SYSTEMD"
+                        SYSTEMD_SCOPE="user"
+                        # Check the systemd user service manager is available
+                        check_systemd_user
+                        printf "${COLOR_GREEN}systemd-user${COLOR_RESET}"
                     else
                         INSTALL_MODE="RUN_METHOD_BINARY"
                         printf "${COLOR_GREEN}binary${COLOR_RESET}"
@@ -867,12 +939,9 @@ main() {
     if [ "$INSTALL_MODE" = "RUN_METHOD_BINARY" ] || [ "$INSTALL_MODE" = "RUN_METHOD_SYSTEMD" ]; then
         setup_binary_contributoor
     fi
-    
-    # Setup systemd service if needed
-    [ "$INSTALL_MODE" = "RUN_METHOD_SYSTEMD" ] && setup_systemd_contributoor
 
     # Docker cleanup if needed
-    if [ "$INSTALL_MODE" = "RUN_METHOD_DOCKER" ] && command -v docker >/dev/null 2>&1; then
+    if [ "$INSTALL_MODE" = "RUN_METHOD_DOCKER" ] && command -v "${CONTAINER_RUNTIME:-docker}" >/dev/null 2>&1; then
         setup_docker_contributoor
     fi
 
@@ -884,7 +953,15 @@ main() {
 
     # Run installer
     progress 8 "Run install wizard"
-    "$CONTRIBUTOOR_BIN/contributoor" --config-path "$CONTRIBUTOOR_PATH" install --version "$CONTRIBUTOOR_VERSION" --run-method "$INSTALL_MODE"
+    local run_method="$INSTALL_MODE"
+    [ "${SYSTEMD_SCOPE:-}" = "user" ] && run_method="systemd-user"
+    "$CONTRIBUTOOR_BIN/contributoor" --config-path "$CONTRIBUTOOR_PATH" install --version "$CONTRIBUTOOR_VERSION" --run-method "$run_method"
+
+    # Setup systemd service if needed, once the install wizard has recorded the run
+    # method and scope the unit is rendered from.
+    if [ "$INSTALL_MODE" = "RUN_METHOD_SYSTEMD" ]; then
+        setup_systemd_contributoor
+    fi
 
     # Ask user if they want to start the service
     printf "\nWould you like to start contributoor now? [y/N]: "
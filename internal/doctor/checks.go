@@ -0,0 +1,297 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/pidfile"
+	"github.com/ethpandaops/contributoor-installer/internal/service"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/validate"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	hintReconfigure   = "Run 'contributoor config' to correct your configuration"
+	hintReinstallUnit = "Run 'contributoor systemd reinstall-unit' to see the changes and regenerate it"
+)
+
+// ConfigCheck validates the configured output server, metrics and health check addresses.
+func ConfigCheck(cfg *config.Config) Check {
+	return NewCheck("Config", func(ctx context.Context) Result {
+		if cfg.OutputServer == nil || cfg.OutputServer.Address == "" {
+			return Fail("no output server configured", hintReconfigure)
+		}
+
+		isEthPandaOps := validate.IsEthPandaOpsServer(cfg.OutputServer.Address)
+
+		if !isEthPandaOps {
+			if err := validate.ValidateOutputServerAddress(cfg.OutputServer.Address); err != nil {
+				return Fail(fmt.Sprintf("invalid output server address: %v", err), hintReconfigure)
+			}
+		}
+
+		var username, password string
+
+		if cfg.OutputServer.Credentials != "" {
+			u, p, err := validate.DecodeCredentials(cfg.OutputServer.Credentials)
+			if err != nil {
+				return Fail(fmt.Sprintf("invalid output server credentials: %v", err), hintReconfigure)
+			}
+
+			username, password = u, p
+		}
+
+		if err := validate.ValidateOutputServerCredentials(username, password, isEthPandaOps); err != nil {
+			return Fail(fmt.Sprintf("invalid output server credentials: %v", err), hintReconfigure)
+		}
+
+		if err := validate.ValidateMetricsAddress(cfg.MetricsAddress); err != nil {
+			return Fail(err.Error(), hintReconfigure)
+		}
+
+		if err := validate.ValidateHealthCheckAddress(cfg.HealthCheckAddress); err != nil {
+			return Fail(err.Error(), hintReconfigure)
+		}
+
+		return Pass("configuration is valid")
+	})
+}
+
+// BeaconCheck checks the configured beacon node(s) are valid and, where reachable
+// from this host, healthy and synced.
+func BeaconCheck(cfg *config.Config, newBeaconService func(address string) service.BeaconService) Check {
+	return NewCheck("Beacon Node", func(ctx context.Context) Result {
+		if cfg.BeaconNodeAddress == "" {
+			return Fail("no beacon node address configured", hintReconfigure)
+		}
+
+		if err := validate.ValidateBeaconNodeAddress(cfg.BeaconNodeAddress); err != nil {
+			return Fail(err.Error(), "Check your beacon node is running, or run 'contributoor config' to correct its address")
+		}
+
+		var (
+			warnings []string
+			checked  int
+		)
+
+		for address := range strings.SplitSeq(cfg.BeaconNodeAddress, ",") {
+			address = strings.TrimSpace(address)
+
+			// Docker network hostnames aren't reachable from the host, so we can't check them.
+			if address == "" || !validate.IsLocalhostAddress(address) {
+				continue
+			}
+
+			checked++
+
+			info := newBeaconService(address).GetBeaconInfo(ctx)
+			if info.Error != nil {
+				return Fail(
+					fmt.Sprintf("%s: %v", address, info.Error),
+					"Check your beacon node is running and its REST API is enabled",
+				)
+			}
+
+			switch {
+			case info.Sync != nil && info.Sync.ELOffline:
+				warnings = append(warnings, fmt.Sprintf("%s: execution layer offline", address))
+			case info.Health != nil && info.Health.IsSyncing, info.Sync != nil && info.Sync.IsSyncing:
+				warnings = append(warnings, fmt.Sprintf("%s: syncing", address))
+			case info.Health != nil && !info.Health.IsHealthy:
+				warnings = append(warnings, fmt.Sprintf("%s: unhealthy (HTTP %d)", address, info.Health.StatusCode))
+			}
+		}
+
+		if len(warnings) > 0 {
+			return Warn(strings.Join(warnings, "; "), "Contributoor will not collect data until your beacon node is healthy and synced")
+		}
+
+		if checked == 0 {
+			return Pass("address is valid (not reachable from host, skipped health check)")
+		}
+
+		return Pass("reachable and synced")
+	})
+}
+
+// VersionCheck checks the running version matches the configured version, and whether
+// a newer release is available.
+func VersionCheck(cfg *config.Config, runner sidecar.SidecarRunner, github service.GitHubService) Check {
+	return NewCheck("Version", func(ctx context.Context) Result {
+		if cfg.Version != "latest" {
+			running, err := runner.Version(ctx)
+			if err == nil && running != cfg.Version {
+				return Fail(
+					fmt.Sprintf("running %s but config expects %s", running, cfg.Version),
+					"Run 'contributoor update' to install the configured version",
+				)
+			}
+		}
+
+		current, latest, needsUpdate, err := sidecar.CheckVersion(ctx, runner, github, cfg.Version)
+		if err != nil {
+			return Warn(fmt.Sprintf("unable to check for updates: %v", err), "Check this host can reach api.github.com")
+		}
+
+		if needsUpdate {
+			return Warn(
+				fmt.Sprintf("version %s is available (running %s)", latest, current),
+				"Run 'contributoor update' to upgrade",
+			)
+		}
+
+		return Pass(fmt.Sprintf("running %s", current))
+	})
+}
+
+// RunMethodCheck reports a failure to set up the configured run method.
+func RunMethodCheck(runMethod config.RunMethod, err error) Check {
+	return NewCheck("Run Method", func(ctx context.Context) Result {
+		if err != nil {
+			return Fail(err.Error(), "Run 'contributoor install' to repair your installation")
+		}
+
+		return Pass(runMethod.DisplayName())
+	})
+}
+
+// ContainerRuntimeCheck checks the configured container runtime (docker or podman)
+// is installed and its engine is accessible.
+func ContainerRuntimeCheck(preference string) Check {
+	return NewCheck("Container Runtime", func(ctx context.Context) Result {
+		runtime, err := sidecar.NewContainerRuntime(preference)
+		if err != nil {
+			return Fail(err.Error(), fmt.Sprintf("Set containerRuntime in %s to auto, docker or podman", installer.ConfigFilename))
+		}
+
+		version, err := runtime.ServerVersion(ctx)
+		if err != nil {
+			hint := "Start docker (eg: 'sudo systemctl start docker') and ensure your user can access it"
+			if runtime.Name() == sidecar.ContainerRuntimePodman {
+				hint = "Check 'podman info' runs without errors as your user"
+			}
+
+			return Fail(err.Error(), hint)
+		}
+
+		return Pass(fmt.Sprintf("%s %s (%s)", runtime.Name(), version, runtime.ComposeName()))
+	})
+}
+
+// ComposeCheck checks the compose project renders from the current config and
+// installer settings.
+func ComposeCheck(sidecarCfg sidecar.ConfigManager, installerCfg *installer.Config) Check {
+	return NewCheck("Compose Project", func(ctx context.Context) Result {
+		if _, err := sidecar.RenderCompose(sidecarCfg, installerCfg); err != nil {
+			return Fail(err.Error(), fmt.Sprintf("Fix the compose settings in %s", installer.ConfigFilename))
+		}
+
+		return Pass(fmt.Sprintf("renders %s", sidecar.ComposeFilename))
+	})
+}
+
+// SystemdUnitCheck checks the systemd unit (or launchd plist) is installed, and
+// matches the one rendered from the config and installer settings.
+func SystemdUnitCheck(systemd sidecar.SystemdSidecar) Check {
+	return NewCheck("Service Unit", func(ctx context.Context) Result {
+		if err := systemd.CheckInstalled(ctx); err != nil {
+			return Fail(err.Error(), "Run 'contributoor install' and select the systemd or systemd-user run method")
+		}
+
+		drift, err := systemd.UnitDrift()
+		if err != nil {
+			// The unit systemd found isn't the one contributoor manages.
+			if errors.Is(err, sidecar.ErrUnitNotInstalled) {
+				return Warn(err.Error(), hintReinstallUnit)
+			}
+
+			return Fail(err.Error(), fmt.Sprintf("Fix the systemd settings in %s", installer.ConfigFilename))
+		}
+
+		if len(drift) > 0 {
+			return Warn(
+				fmt.Sprintf("out of date, %s differ from the rendered unit", pluralise(len(drift), "line")),
+				hintReinstallUnit,
+			)
+		}
+
+		return Pass("installed and up to date")
+	})
+}
+
+// LingerCheck checks lingering is enabled for the user running a systemd user unit,
+// without which the unit stops when they log out and doesn't start at boot.
+func LingerCheck(username string, enabled func(username string) bool) Check {
+	return NewCheck("Lingering", func(ctx context.Context) Result {
+		if !enabled(username) {
+			return Warn(
+				fmt.Sprintf("not enabled for %s, contributoor stops when you log out", username),
+				fmt.Sprintf("Run 'loginctl enable-linger %s', or ask an administrator to", username),
+			)
+		}
+
+		return Pass(fmt.Sprintf("enabled for %s", username))
+	})
+}
+
+// PidFileCheck checks for a stale pid file left behind by the binary run method.
+func PidFileCheck(cfg *config.Config) Check {
+	return NewCheck("Pid File", func(ctx context.Context) Result {
+		dir, err := homedir.Expand(cfg.ContributoorDirectory)
+		if err != nil {
+			return Fail(fmt.Sprintf("failed to expand contributoor directory: %v", err), hintReconfigure)
+		}
+
+		pidFile := pidfile.New(filepath.Join(dir, "contributoor.pid"))
+
+		state, pid, err := pidFile.Check(ctx, func(cmdline []string) bool {
+			return sidecar.IsBinarySidecarProcess(cmdline, dir)
+		})
+		if err != nil {
+			if errors.Is(err, pidfile.ErrInvalid) {
+				return Fail(fmt.Sprintf("%s contains an invalid pid", pidFile.Path()), fmt.Sprintf("Remove %s", pidFile.Path()))
+			}
+
+			return Fail(fmt.Sprintf("failed to check %s: %v", pidFile.Path(), err), "Check the permissions of your contributoor directory")
+		}
+
+		switch state {
+		case pidfile.StateStopped:
+			return Pass("no pid file (not running)")
+		case pidfile.StateStale:
+			return Warn(
+				fmt.Sprintf("stale pid file, contributoor (pid %d) is not running", pid),
+				fmt.Sprintf("Remove %s, or run 'contributoor start'", pidFile.Path()),
+			)
+		}
+
+		return Pass(fmt.Sprintf("process %d is running", pid))
+	})
+}
+
+// OrphanCheck checks no sidecar is running under a run method other than the
+// configured one, which would send duplicate data.
+func OrphanCheck(findOrphans func(ctx context.Context) []sidecar.Orphan) Check {
+	return NewCheck("Orphaned Sidecars", func(ctx context.Context) Result {
+		orphans := findOrphans(ctx)
+		if len(orphans) == 0 {
+			return Pass("none found")
+		}
+
+		names := make([]string, 0, len(orphans))
+		for _, orphan := range orphans {
+			names = append(names, orphan.Name())
+		}
+
+		return Fail(
+			fmt.Sprintf("also running under %s", strings.Join(names, ", ")),
+			"Run 'contributoor status --cleanup-orphans' to stop and remove them",
+		)
+	})
+}
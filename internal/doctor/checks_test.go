@@ -0,0 +1,389 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/service"
+	servicemock "github.com/ethpandaops/contributoor-installer/internal/service/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/ethpandaops/contributoor-installer/internal/validate"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestConfigCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            *config.Config
+		expectedStatus Status
+		expectedMsg    string
+	}{
+		{
+			name: "valid ethpandaops config",
+			cfg: &config.Config{
+				OutputServer: &config.OutputServer{
+					Address:     tui.OutputServerProduction,
+					Credentials: validate.EncodeCredentials("user", "pass"),
+				},
+				MetricsAddress: ":9090",
+			},
+			expectedStatus: StatusPass,
+		},
+		{
+			name: "valid custom server without credentials",
+			cfg: &config.Config{
+				OutputServer: &config.OutputServer{Address: "https://xatu.example.com"},
+			},
+			expectedStatus: StatusPass,
+		},
+		{
+			name:           "missing output server",
+			cfg:            &config.Config{},
+			expectedStatus: StatusFail,
+			expectedMsg:    "no output server configured",
+		},
+		{
+			name: "missing ethpandaops credentials",
+			cfg: &config.Config{
+				OutputServer: &config.OutputServer{Address: tui.OutputServerProduction},
+			},
+			expectedStatus: StatusFail,
+			expectedMsg:    "invalid output server credentials",
+		},
+		{
+			name: "invalid metrics address",
+			cfg: &config.Config{
+				OutputServer:   &config.OutputServer{Address: "https://xatu.example.com"},
+				MetricsAddress: "http://127.0.0.1",
+			},
+			expectedStatus: StatusFail,
+			expectedMsg:    "metrics address must include a port",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ConfigCheck(tt.cfg).Run(context.Background())
+
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			assert.Contains(t, result.Message, tt.expectedMsg)
+
+			if tt.expectedStatus != StatusPass {
+				assert.NotEmpty(t, result.Hint)
+			}
+		})
+	}
+}
+
+func TestBeaconCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		address        string
+		info           *service.BeaconInfo
+		expectedStatus Status
+		expectedMsg    string
+	}{
+		{
+			name:    "healthy and synced",
+			address: "http://127.0.0.1:5052",
+			info: &service.BeaconInfo{
+				Health: &service.HealthStatus{StatusCode: http.StatusOK, IsHealthy: true},
+				Sync:   &service.SyncStatus{},
+			},
+			expectedStatus: StatusPass,
+			expectedMsg:    "reachable and synced",
+		},
+		{
+			name:    "syncing",
+			address: "http://127.0.0.1:5052",
+			info: &service.BeaconInfo{
+				Health: &service.HealthStatus{StatusCode: http.StatusPartialContent, IsSyncing: true},
+				Sync:   &service.SyncStatus{IsSyncing: true},
+			},
+			expectedStatus: StatusWarn,
+			expectedMsg:    "syncing",
+		},
+		{
+			name:    "execution layer offline",
+			address: "http://127.0.0.1:5052",
+			info: &service.BeaconInfo{
+				Health: &service.HealthStatus{StatusCode: http.StatusOK, IsHealthy: true},
+				Sync:   &service.SyncStatus{ELOffline: true},
+			},
+			expectedStatus: StatusWarn,
+			expectedMsg:    "execution layer offline",
+		},
+		{
+			name:           "unreachable",
+			address:        "http://127.0.0.1:5052",
+			info:           &service.BeaconInfo{Error: errors.New("beacon node unreachable")},
+			expectedStatus: StatusFail,
+			expectedMsg:    "beacon node unreachable",
+		},
+		{
+			name:           "docker network hostname is not checked",
+			address:        "http://beacon:5052",
+			expectedStatus: StatusPass,
+			expectedMsg:    "skipped health check",
+		},
+		{
+			name:           "invalid address",
+			address:        "beacon:5052",
+			expectedStatus: StatusFail,
+			expectedMsg:    "must start with http:// or https://",
+		},
+		{
+			name:           "no address",
+			expectedStatus: StatusFail,
+			expectedMsg:    "no beacon node address configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := tt.address
+
+			// Localhost addresses are probed by the validate package too, so stand up a
+			// real server for it to hit.
+			if validate.IsLocalhostAddress(address) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}))
+				defer server.Close()
+
+				address = server.URL
+			}
+
+			check := BeaconCheck(&config.Config{BeaconNodeAddress: address}, func(string) service.BeaconService {
+				return &stubBeaconService{info: tt.info}
+			})
+
+			result := check.Run(context.Background())
+
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			assert.Contains(t, result.Message, tt.expectedMsg)
+		})
+	}
+}
+
+func TestVersionCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		configVersion  string
+		setupMocks     func(*mock.MockBinarySidecar, *servicemock.MockGitHubService)
+		expectedStatus Status
+		expectedMsg    string
+	}{
+		{
+			name:          "up to date",
+			configVersion: "1.0.0",
+			setupMocks: func(r *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {
+				r.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("1.0.0", nil)
+			},
+			expectedStatus: StatusPass,
+			expectedMsg:    "running 1.0.0",
+		},
+		{
+			name:          "update available",
+			configVersion: "latest",
+			setupMocks: func(r *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("1.1.0", nil)
+				r.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+			},
+			expectedStatus: StatusWarn,
+			expectedMsg:    "version 1.1.0 is available",
+		},
+		{
+			name:          "version mismatch",
+			configVersion: "1.1.0",
+			setupMocks: func(r *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {
+				r.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+			},
+			expectedStatus: StatusFail,
+			expectedMsg:    "running 1.0.0 but config expects 1.1.0",
+		},
+		{
+			name:          "github unavailable",
+			configVersion: "1.0.0",
+			setupMocks: func(r *mock.MockBinarySidecar, g *servicemock.MockGitHubService) {
+				r.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("", errors.New("rate limited"))
+			},
+			expectedStatus: StatusWarn,
+			expectedMsg:    "unable to check for updates",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRunner := mock.NewMockBinarySidecar(ctrl)
+			mockGitHub := servicemock.NewMockGitHubService(ctrl)
+
+			tt.setupMocks(mockRunner, mockGitHub)
+
+			result := VersionCheck(&config.Config{Version: tt.configVersion}, mockRunner, mockGitHub).Run(context.Background())
+
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			assert.Contains(t, result.Message, tt.expectedMsg)
+		})
+	}
+}
+
+func TestPidFileCheck(t *testing.T) {
+	// Find a pid which is no longer running, by running a process to completion.
+	exited := exec.Command("true")
+	require.NoError(t, exited.Run())
+
+	// Run a process which looks like sentry.
+	sleepPath, err := exec.LookPath("sleep")
+	require.NoError(t, err)
+
+	sentry := &exec.Cmd{Path: sleepPath, Args: []string{"sentry", "30"}}
+	require.NoError(t, sentry.Start())
+
+	t.Cleanup(func() {
+		_ = sentry.Process.Kill()
+		_ = sentry.Wait()
+	})
+
+	tests := []struct {
+		name           string
+		pid            string
+		expectedStatus Status
+		expectedMsg    string
+	}{
+		{
+			name:           "no pid file",
+			expectedStatus: StatusPass,
+			expectedMsg:    "not running",
+		},
+		{
+			name:           "running process",
+			pid:            fmt.Sprintf("%d\n", sentry.Process.Pid),
+			expectedStatus: StatusPass,
+			expectedMsg:    "is running",
+		},
+		{
+			name:           "stale pid file",
+			pid:            fmt.Sprintf("%d", exited.Process.Pid),
+			expectedStatus: StatusWarn,
+			expectedMsg:    "stale pid file",
+		},
+		{
+			name:           "pid reused by another process",
+			pid:            fmt.Sprintf("%d", os.Getpid()),
+			expectedStatus: StatusWarn,
+			expectedMsg:    "stale pid file",
+		},
+		{
+			name:           "invalid pid",
+			pid:            "not-a-pid",
+			expectedStatus: StatusFail,
+			expectedMsg:    "invalid pid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			if tt.pid != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "contributoor.pid"), []byte(tt.pid), 0600))
+			}
+
+			result := PidFileCheck(&config.Config{ContributoorDirectory: dir}).Run(context.Background())
+
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			assert.Contains(t, result.Message, tt.expectedMsg)
+		})
+	}
+}
+
+func TestSystemdUnitCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSystemd := mock.NewMockSystemdSidecar(ctrl)
+
+	mockSystemd.EXPECT().CheckInstalled(gomock.Any()).Return(nil)
+	mockSystemd.EXPECT().UnitDrift().Return(nil, nil)
+	assert.Equal(t, StatusPass, SystemdUnitCheck(mockSystemd).Run(context.Background()).Status)
+
+	mockSystemd.EXPECT().CheckInstalled(gomock.Any()).Return(errors.New("service not installed"))
+	result := SystemdUnitCheck(mockSystemd).Run(context.Background())
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, "service not installed", result.Message)
+
+	// A unit which no longer matches installer.yaml should be regenerated.
+	mockSystemd.EXPECT().CheckInstalled(gomock.Any()).Return(nil)
+	mockSystemd.EXPECT().UnitDrift().Return([]string{"- MemoryMax=512M", "+ MemoryMax=1G"}, nil)
+	result = SystemdUnitCheck(mockSystemd).Run(context.Background())
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Equal(t, "out of date, 2 lines differ from the rendered unit", result.Message)
+	assert.Contains(t, result.Hint, "contributoor systemd reinstall-unit")
+
+	mockSystemd.EXPECT().CheckInstalled(gomock.Any()).Return(nil)
+	mockSystemd.EXPECT().UnitDrift().Return(nil, errors.New(`invalid systemd restart "sometimes"`))
+	result = SystemdUnitCheck(mockSystemd).Run(context.Background())
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Contains(t, result.Hint, "installer.yaml")
+}
+
+func TestLingerCheck(t *testing.T) {
+	result := LingerCheck("eth", func(username string) bool { return username == "eth" }).Run(context.Background())
+	assert.Equal(t, StatusPass, result.Status)
+
+	result = LingerCheck("eth", func(string) bool { return false }).Run(context.Background())
+	assert.Equal(t, StatusWarn, result.Status)
+	assert.Contains(t, result.Hint, "loginctl enable-linger eth")
+}
+
+func TestOrphanCheck(t *testing.T) {
+	result := OrphanCheck(func(context.Context) []sidecar.Orphan { return nil }).Run(context.Background())
+	assert.Equal(t, StatusPass, result.Status)
+
+	result = OrphanCheck(func(context.Context) []sidecar.Orphan {
+		return []sidecar.Orphan{
+			{RunMode: sidecar.RunMode{Method: config.RunMethod_RUN_METHOD_DOCKER}},
+			{RunMode: sidecar.RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: installer.SystemdScopeUser}},
+		}
+	}).Run(context.Background())
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, "also running under docker, systemd-user", result.Message)
+}
+
+func TestRunMethodCheck(t *testing.T) {
+	result := RunMethodCheck(config.RunMethod_RUN_METHOD_DOCKER, nil).Run(context.Background())
+	assert.Equal(t, StatusPass, result.Status)
+
+	result = RunMethodCheck(config.RunMethod_RUN_METHOD_DOCKER, errors.New("docker-compose.yml not found")).Run(context.Background())
+	assert.Equal(t, StatusFail, result.Status)
+	assert.Equal(t, "docker-compose.yml not found", result.Message)
+}
+
+// stubBeaconService returns canned beacon info.
+type stubBeaconService struct {
+	service.BeaconService
+	info *service.BeaconInfo
+}
+
+func (s *stubBeaconService) GetBeaconInfo(ctx context.Context) *service.BeaconInfo {
+	return s.info
+}
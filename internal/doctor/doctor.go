@@ -0,0 +1,113 @@
+package doctor
+
+import (
+	"context"
+)
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	// StatusPass indicates the check passed.
+	StatusPass Status = iota
+	// StatusWarn indicates something isn't quite right, but contributoor should still work.
+	StatusWarn
+	// StatusFail indicates something is broken.
+	StatusFail
+)
+
+// String returns the display name of the status.
+func (s Status) String() string {
+	switch s {
+	case StatusPass:
+		return "PASS"
+	case StatusWarn:
+		return "WARN"
+	case StatusFail:
+		return "FAIL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Result is the outcome of running a single check.
+type Result struct {
+	// Name is the name of the check that produced the result.
+	Name string
+	// Status is the outcome of the check.
+	Status Status
+	// Message describes what the check found.
+	Message string
+	// Hint suggests how to remediate a warning or failure.
+	Hint string
+}
+
+// Check is a single diagnostic check. New checks only need to implement this
+// interface and be added to the list of checks passed to Run.
+type Check interface {
+	// Name returns the name of the check.
+	Name() string
+	// Run runs the check.
+	Run(ctx context.Context) Result
+}
+
+// checkFunc adapts a function into a Check.
+type checkFunc struct {
+	name string
+	fn   func(ctx context.Context) Result
+}
+
+// NewCheck creates a Check from a function.
+func NewCheck(name string, fn func(ctx context.Context) Result) Check {
+	return &checkFunc{name: name, fn: fn}
+}
+
+// Name returns the name of the check.
+func (c *checkFunc) Name() string {
+	return c.name
+}
+
+// Run runs the check.
+func (c *checkFunc) Run(ctx context.Context) Result {
+	return c.fn(ctx)
+}
+
+// Pass returns a passing result.
+func Pass(message string) Result {
+	return Result{Status: StatusPass, Message: message}
+}
+
+// Warn returns a warning result, with a hint on how to remediate it.
+func Warn(message, hint string) Result {
+	return Result{Status: StatusWarn, Message: message, Hint: hint}
+}
+
+// Fail returns a failing result, with a hint on how to remediate it.
+func Fail(message, hint string) Result {
+	return Result{Status: StatusFail, Message: message, Hint: hint}
+}
+
+// Run runs each of the checks in order and returns their results.
+func Run(ctx context.Context, checks []Check) []Result {
+	results := make([]Result, 0, len(checks))
+
+	for _, check := range checks {
+		result := check.Run(ctx)
+		result.Name = check.Name()
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// HasFailures returns true if any of the results failed.
+func HasFailures(results []Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFail {
+			return true
+		}
+	}
+
+	return false
+}
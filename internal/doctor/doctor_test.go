@@ -0,0 +1,53 @@
+package doctor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	var ran []string
+
+	checks := []Check{
+		NewCheck("first", func(ctx context.Context) Result {
+			ran = append(ran, "first")
+
+			return Pass("ok")
+		}),
+		NewCheck("second", func(ctx context.Context) Result {
+			ran = append(ran, "second")
+
+			return Warn("hmm", "do something")
+		}),
+		NewCheck("third", func(ctx context.Context) Result {
+			ran = append(ran, "third")
+
+			return Fail("broken", "fix it")
+		}),
+	}
+
+	results := Run(context.Background(), checks)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"first", "second", "third"}, ran)
+
+	assert.Equal(t, Result{Name: "first", Status: StatusPass, Message: "ok"}, results[0])
+	assert.Equal(t, Result{Name: "second", Status: StatusWarn, Message: "hmm", Hint: "do something"}, results[1])
+	assert.Equal(t, Result{Name: "third", Status: StatusFail, Message: "broken", Hint: "fix it"}, results[2])
+}
+
+func TestHasFailures(t *testing.T) {
+	assert.False(t, HasFailures(nil))
+	assert.False(t, HasFailures([]Result{Pass("ok"), Warn("hmm", "")}))
+	assert.True(t, HasFailures([]Result{Pass("ok"), Fail("broken", "")}))
+}
+
+func TestStatusString(t *testing.T) {
+	assert.Equal(t, "PASS", StatusPass.String())
+	assert.Equal(t, "WARN", StatusWarn.String())
+	assert.Equal(t, "FAIL", StatusFail.String())
+	assert.Equal(t, "UNKNOWN", Status(99).String())
+}
@@ -0,0 +1,271 @@
+package doctor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/validate"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+)
+
+const (
+	// logScanWindow is how far back the sidecar's logs are scanned for known errors.
+	logScanWindow = time.Hour
+	// logScanLines caps how many lines of the sidecar's logs are scanned.
+	logScanLines = 5000
+	// logScanTimeout bounds how long reading the logs may take.
+	logScanTimeout = 15 * time.Second
+)
+
+// LogRule recognises a known error in the sidecar's logs.
+type LogRule struct {
+	// Name identifies the rule.
+	Name string
+	// Pattern matches the log lines reporting the error.
+	Pattern *regexp.Regexp
+	// Explain returns an explanation of a matching line and how to fix it, given the
+	// pattern's submatches. It returns false if the line isn't the error after all,
+	// eg: a connection refused by something other than the beacon node.
+	Explain func(cfg *config.Config, match []string) (message, hint string, ok bool)
+}
+
+// LogFinding is a known error found in the sidecar's logs.
+type LogFinding struct {
+	// Rule is the name of the rule which found the error.
+	Rule string
+	// Message explains the error.
+	Message string
+	// Hint suggests how to fix it.
+	Hint string
+	// Count is how many times the error was logged.
+	Count int
+	// LastSeen is when the error was last logged, if the log line says.
+	LastSeen time.Time
+	// Line is the last log line reporting the error.
+	Line string
+}
+
+// LogRules are the known errors the sidecar's logs are scanned for.
+var LogRules = []LogRule{
+	{
+		Name:    "output-server-unauthorized",
+		Pattern: regexp.MustCompile(`(?i)code = Unauthenticated|\b401\b.*unauthori[sz]ed|unauthori[sz]ed.*\b401\b|status(?: code)?[ :=]+401\b`),
+		Explain: func(_ *config.Config, _ []string) (string, string, bool) {
+			return "the output server rejected your credentials (401 Unauthorized)",
+				"Re-run 'contributoor config' → Output Server to update your credentials", true
+		},
+	},
+	{
+		Name:    "output-server-tls",
+		Pattern: regexp.MustCompile(`(?i)tls: [a-z]|x509: |authentication handshake failed`),
+		Explain: func(_ *config.Config, _ []string) (string, string, bool) {
+			return "the TLS handshake with the output server failed",
+				"Check the output server address and its TLS setting in 'contributoor config' → Output Server, " +
+					"and that this host's clock and CA certificates are up to date", true
+		},
+	},
+	{
+		Name:    "beacon-connection-refused",
+		Pattern: regexp.MustCompile(`dial tcp (\S+?):? connect: connection refused`),
+		Explain: func(cfg *config.Config, match []string) (string, string, bool) {
+			address, ok := beaconAddress(cfg, match[1])
+			if !ok {
+				return "", "", false
+			}
+
+			message := fmt.Sprintf("the beacon node at %s refused the connection", address)
+
+			// Inside a container, localhost is the container itself rather than the host.
+			if cfg.RunMethod == config.RunMethod_RUN_METHOD_DOCKER && validate.IsLocalhostAddress(address) {
+				return message,
+					fmt.Sprintf(
+						"The beacon node at %s is unreachable from inside the container; "+
+							"choose a Docker network in 'contributoor config' → Network Settings and use the beacon node's container name",
+						address,
+					), true
+			}
+
+			return message, "Check your beacon node is running and its REST API is enabled", true
+		},
+	},
+	{
+		Name:    "beacon-unresolvable",
+		Pattern: regexp.MustCompile(`dial tcp: lookup (\S+?)(?: on \S+)?: no such host`),
+		Explain: func(cfg *config.Config, match []string) (string, string, bool) {
+			address, ok := beaconAddress(cfg, match[1])
+			if !ok {
+				return "", "", false
+			}
+
+			hint := "Check the beacon node address in 'contributoor config' → Network Settings"
+			if cfg.RunMethod == config.RunMethod_RUN_METHOD_DOCKER {
+				hint = "If the beacon node runs in a container, choose the Docker network it's on in " +
+					"'contributoor config' → Network Settings"
+			}
+
+			return fmt.Sprintf("the beacon node host in %s can't be resolved", address), hint, true
+		},
+	},
+	{
+		Name:    "wrong-network",
+		Pattern: regexp.MustCompile(`(?i)network (?:mismatch|does not match|doesn't match)|unexpected network|wrong network`),
+		Explain: func(cfg *config.Config, _ []string) (string, string, bool) {
+			message := "the beacon node isn't on the expected network"
+			if cfg.NetworkName != "" {
+				message = fmt.Sprintf("the beacon node isn't on the configured network (%s)", cfg.NetworkName)
+			}
+
+			return message, "Point contributoor at a beacon node on the right network in 'contributoor config' → Network Settings", true
+		},
+	},
+}
+
+// beaconAddress returns the configured beacon node address with the given host, or
+// host:port as it appears in a dial error.
+func beaconAddress(cfg *config.Config, hostPort string) (string, bool) {
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		host = hostPort
+	}
+
+	for address := range strings.SplitSeq(cfg.BeaconNodeAddress, ",") {
+		address = strings.TrimSpace(address)
+
+		u, err := url.Parse(address)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+
+		// Go resolves localhost before dialling, so the error names its address.
+		sameHost := u.Hostname() == host ||
+			(u.Hostname() == "localhost" && (host == "127.0.0.1" || host == "::1"))
+
+		if sameHost && (port == "" || u.Port() == "" || u.Port() == port) {
+			return address, true
+		}
+	}
+
+	return "", false
+}
+
+// ScanLogs reads the last hour of the sidecar's logs through its runner and
+// returns the known errors found in them.
+func ScanLogs(ctx context.Context, runner sidecar.SidecarRunner, cfg *config.Config) ([]LogFinding, error) {
+	ctx, cancel := context.WithTimeout(ctx, logScanTimeout)
+	defer cancel()
+
+	var logs bytes.Buffer
+
+	err := runner.Logs(ctx, sidecar.LogOptions{
+		Tail:   logScanLines,
+		Since:  time.Now().Add(-logScanWindow),
+		Output: &logs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return MatchLogRules(&logs, cfg, LogRules), nil
+}
+
+// MatchLogRules returns the errors the rules find in logs, most recently logged
+// first. Each line is only matched by the first rule recognising it.
+func MatchLogRules(logs io.Reader, cfg *config.Config, rules []LogRule) []LogFinding {
+	var (
+		findings []LogFinding
+		index    = make(map[string]int, len(rules))
+		scanner  = bufio.NewScanner(logs)
+	)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		for _, rule := range rules {
+			match := rule.Pattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+
+			message, hint, ok := rule.Explain(cfg, match)
+			if !ok {
+				continue
+			}
+
+			i, seen := index[rule.Name]
+			if !seen {
+				i = len(findings)
+				index[rule.Name] = i
+
+				findings = append(findings, LogFinding{Rule: rule.Name})
+			}
+
+			finding := &findings[i]
+			finding.Message, finding.Hint, finding.Line = message, hint, line
+			finding.Count++
+
+			if entry, ok := sidecar.ParseLogLine(line); ok && !entry.Time.IsZero() {
+				finding.LastSeen = entry.Time
+			}
+
+			break
+		}
+	}
+
+	// Put the most recent errors first, as they're the most likely to be current.
+	slices.SortStableFunc(findings, func(a, b LogFinding) int {
+		return b.LastSeen.Compare(a.LastSeen)
+	})
+
+	return findings
+}
+
+// LogCheck checks the sidecar's recent logs for known errors.
+func LogCheck(runner sidecar.SidecarRunner, cfg *config.Config) Check {
+	return NewCheck("Sidecar Logs", func(ctx context.Context) Result {
+		findings, err := ScanLogs(ctx, runner, cfg)
+		if err != nil {
+			// There are no logs to scan until the sidecar has been started.
+			if errors.Is(err, sidecar.ErrContainerNotFound) {
+				return Pass("no logs to scan (not running)")
+			}
+
+			return Warn(fmt.Sprintf("unable to read logs: %v", err), "Check 'contributoor logs' runs without errors")
+		}
+
+		if len(findings) == 0 {
+			return Pass(fmt.Sprintf("no known errors in the last %s", logScanWindow))
+		}
+
+		messages := make([]string, 0, len(findings))
+		hints := make([]string, 0, len(findings))
+
+		for _, finding := range findings {
+			messages = append(messages, fmt.Sprintf("%s (%s)", finding.Message, pluralise(finding.Count, "time")))
+			hints = append(hints, finding.Hint)
+		}
+
+		return Fail(strings.Join(messages, "; "), strings.Join(hints, "; "))
+	})
+}
+
+// pluralise returns count followed by noun, pluralised if count isn't 1.
+func pluralise(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+
+	return fmt.Sprintf("%d %ss", count, noun)
+}
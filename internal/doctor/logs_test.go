@@ -0,0 +1,204 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMatchLogRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		runMethod     config.RunMethod
+		lines         []string
+		expectedRules []string
+		expectedHint  string
+	}{
+		{
+			name: "no known errors",
+			lines: []string{
+				`time="2024-01-01T00:00:00Z" level=info msg="event sent"`,
+				`time="2024-01-01T00:00:01Z" level=info msg="slot 401 processed"`,
+			},
+		},
+		{
+			name: "output server unauthorized",
+			lines: []string{
+				`time="2024-01-01T00:00:00Z" level=error msg="failed to export" error="rpc error: code = Unauthenticated desc = invalid credentials"`,
+				`time="2024-01-01T00:00:01Z" level=error msg="failed to export" error="unexpected status code: 401 Unauthorized"`,
+			},
+			expectedRules: []string{"output-server-unauthorized"},
+			expectedHint:  "Output Server to update your credentials",
+		},
+		{
+			name: "output server tls",
+			lines: []string{
+				`level=error msg="failed to export" error="transport: authentication handshake failed: x509: certificate signed by unknown authority"`,
+			},
+			expectedRules: []string{"output-server-tls"},
+		},
+		{
+			name:      "localhost beacon refused in a container",
+			runMethod: config.RunMethod_RUN_METHOD_DOCKER,
+			lines: []string{
+				`level=error msg="failed to connect" error="Get \"http://localhost:5052/eth/v1/node/syncing\": dial tcp 127.0.0.1:5052: connect: connection refused"`,
+			},
+			expectedRules: []string{"beacon-connection-refused"},
+			expectedHint:  "unreachable from inside the container; choose a Docker network",
+		},
+		{
+			name:      "beacon refused on the host",
+			runMethod: config.RunMethod_RUN_METHOD_BINARY,
+			lines: []string{
+				`level=error msg="failed to connect" error="dial tcp 127.0.0.1:5052: connect: connection refused"`,
+			},
+			expectedRules: []string{"beacon-connection-refused"},
+			expectedHint:  "Check your beacon node is running",
+		},
+		{
+			name:      "something other than the beacon refused",
+			runMethod: config.RunMethod_RUN_METHOD_BINARY,
+			lines: []string{
+				`level=error msg="failed to connect" error="dial tcp 10.0.0.9:8080: connect: connection refused"`,
+			},
+		},
+		{
+			name:      "beacon host unresolvable",
+			runMethod: config.RunMethod_RUN_METHOD_DOCKER,
+			lines: []string{
+				`level=error msg="failed to connect" error="dial tcp: lookup beacon on 127.0.0.11:53: no such host"`,
+			},
+			expectedRules: []string{"beacon-unresolvable"},
+			expectedHint:  "choose the Docker network it's on",
+		},
+		{
+			name: "wrong network",
+			lines: []string{
+				`level=fatal msg="network mismatch: beacon node is on holesky"`,
+			},
+			expectedRules: []string{"wrong-network"},
+		},
+		{
+			name: "most recent first",
+			lines: []string{
+				`time="2024-01-01T00:00:00Z" level=error msg="x509: certificate has expired"`,
+				`time="2024-01-01T00:00:05Z" level=error msg="rpc error: code = Unauthenticated"`,
+			},
+			expectedRules: []string{"output-server-unauthorized", "output-server-tls"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				RunMethod:         tt.runMethod,
+				BeaconNodeAddress: "http://localhost:5052,http://beacon:5052",
+			}
+
+			findings := MatchLogRules(strings.NewReader(strings.Join(tt.lines, "\n")), cfg, LogRules)
+
+			rules := make([]string, 0, len(findings))
+			for _, finding := range findings {
+				rules = append(rules, finding.Rule)
+			}
+
+			if len(tt.expectedRules) == 0 {
+				assert.Empty(t, rules)
+
+				return
+			}
+
+			assert.Equal(t, tt.expectedRules, rules)
+
+			if tt.expectedHint != "" {
+				assert.Contains(t, findings[0].Hint, tt.expectedHint)
+			}
+		})
+	}
+}
+
+func TestMatchLogRules_Counts(t *testing.T) {
+	logs := strings.Join([]string{
+		`time="2024-01-01T00:00:00Z" level=error msg="rpc error: code = Unauthenticated"`,
+		`time="2024-01-01T00:00:30Z" level=error msg="rpc error: code = Unauthenticated"`,
+	}, "\n")
+
+	findings := MatchLogRules(strings.NewReader(logs), &config.Config{}, LogRules)
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, 2, findings[0].Count)
+	assert.True(t, time.Date(2024, 1, 1, 0, 0, 30, 0, time.UTC).Equal(findings[0].LastSeen))
+	assert.Contains(t, findings[0].Line, "00:00:30")
+}
+
+func TestLogCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		logs           string
+		err            error
+		expectedStatus Status
+		expectedMsg    string
+	}{
+		{
+			name:           "no known errors",
+			logs:           "level=info msg=\"event sent\"\n",
+			expectedStatus: StatusPass,
+			expectedMsg:    "no known errors",
+		},
+		{
+			name:           "known error",
+			logs:           "level=error msg=\"rpc error: code = Unauthenticated\"\nlevel=error msg=\"rpc error: code = Unauthenticated\"\n",
+			expectedStatus: StatusFail,
+			expectedMsg:    "rejected your credentials (401 Unauthorized) (2 times)",
+		},
+		{
+			name:           "container not created yet",
+			err:            fmt.Errorf("get logs: %w", sidecar.ErrContainerNotFound),
+			expectedStatus: StatusPass,
+			expectedMsg:    "no logs to scan",
+		},
+		{
+			name:           "logs unreadable",
+			err:            errors.New("failed to read journal: exit status 1: sudo: a password is required"),
+			expectedStatus: StatusWarn,
+			expectedMsg:    "unable to read logs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			runner := mock.NewMockSidecarRunner(ctrl)
+			runner.EXPECT().Logs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts sidecar.LogOptions) error {
+				assert.False(t, opts.Since.IsZero())
+				assert.False(t, opts.Follow)
+
+				if tt.err != nil {
+					return tt.err
+				}
+
+				_, err := io.WriteString(opts.Output, tt.logs)
+
+				return err
+			})
+
+			result := LogCheck(runner, &config.Config{}).Run(context.Background())
+
+			assert.Equal(t, tt.expectedStatus, result.Status)
+			assert.Contains(t, result.Message, tt.expectedMsg)
+		})
+	}
+}
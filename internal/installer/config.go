@@ -1,6 +1,26 @@
 package installer
 
-import "github.com/sirupsen/logrus"
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFilename is the name of the optional installer config file, which lives
+// alongside the sidecar config.
+const ConfigFilename = "installer.yaml"
+
+// Scopes of the systemd run method's unit.
+const (
+	// SystemdScopeSystem runs contributoor as a system unit, managed with sudo systemctl.
+	SystemdScopeSystem = "system"
+	// SystemdScopeUser runs contributoor as a user unit, managed with systemctl --user.
+	SystemdScopeUser = "user"
+)
 
 // Config holds installer-specific configuration that isn't exposed to the sidecar.
 type Config struct {
@@ -16,6 +36,106 @@ type Config struct {
 	GithubContributoorRepo string
 	// GithubInstallerRepo is the repository name of the installer repository.
 	GithubInstallerRepo string
+	// SigningPublicKey is the base64 encoded ed25519 public key used to verify the
+	// signature of installer release checksums. The key the installer was built with
+	// can't be overridden, installer.yaml only provides one for builds without a key.
+	// Updates are refused if empty.
+	SigningPublicKey string
+	// ContainerRuntime is the container runtime used by the docker run method, one
+	// of "auto", "docker" or "podman".
+	ContainerRuntime string
+	// Instance is the name of the contributoor instance being managed, empty for the
+	// default instance.
+	Instance string
+	// Compose holds the settings used to render the compose project for the docker
+	// run method.
+	Compose ComposeConfig
+	// StopTimeout is how long the binary run method waits for the sidecar to exit
+	// after asking it to stop, before killing it.
+	StopTimeout time.Duration
+	// Logs holds the rotation settings of the binary run method's log files.
+	Logs LogsConfig
+	// Systemd holds the settings of the systemd run method.
+	Systemd SystemdConfig
+}
+
+// SystemdConfig holds the settings of the systemd run method.
+type SystemdConfig struct {
+	// Scope is whether the unit is a system unit or a user unit, one of "system" or
+	// "user". User units don't need sudo.
+	Scope string `yaml:"scope"`
+	// Restart is the restart policy of the unit, eg: "always" or "on-failure".
+	Restart string `yaml:"restart"`
+	// RestartSec is how long systemd waits before restarting the service.
+	RestartSec *time.Duration `yaml:"restartSec"`
+	// MemoryMax is the memory limit of the service, eg: "1024M" or "50%". "0" means
+	// no limit.
+	MemoryMax string `yaml:"memoryMax"`
+	// CPUQuota is the CPU time the service may use, relative to one CPU, eg: "50%" or
+	// "200%". "0" means no limit.
+	CPUQuota string `yaml:"cpuQuota"`
+	// Environment is extra environment variables to set for the service.
+	Environment map[string]string `yaml:"environment"`
+	// Hardening holds the sandboxing directives of the unit.
+	Hardening SystemdHardening `yaml:"hardening"`
+}
+
+// SystemdHardening holds the sandboxing directives of the systemd unit. Only
+// NoNewPrivileges applies to user units, the others need root.
+type SystemdHardening struct {
+	// NoNewPrivileges stops the service gaining privileges, eg: through setuid binaries.
+	NoNewPrivileges *bool `yaml:"noNewPrivileges"`
+	// ProtectSystem mounts the system directories read-only, one of "no", "yes",
+	// "full" or "strict".
+	ProtectSystem string `yaml:"protectSystem"`
+	// ProtectHome restricts access to home directories, one of "no", "yes",
+	// "read-only" or "tmpfs".
+	ProtectHome string `yaml:"protectHome"`
+	// PrivateTmp gives the service its own /tmp.
+	PrivateTmp *bool `yaml:"privateTmp"`
+}
+
+// LogsConfig holds the rotation settings of the log files written by the binary run
+// method.
+type LogsConfig struct {
+	// MaxSize is the size a log file is rotated at, eg: "100M". "0" disables size
+	// based rotation.
+	MaxSize string `yaml:"maxSize"`
+	// MaxAge is how long a log file is written to before it's rotated, eg: "24h".
+	// "0s" disables age based rotation.
+	MaxAge *time.Duration `yaml:"maxAge"`
+	// MaxFiles is how many rotated, gzip compressed, log files are kept. 0 keeps
+	// them all.
+	MaxFiles *int `yaml:"maxFiles"`
+}
+
+// ComposeConfig holds the container settings of the rendered compose project which
+// aren't part of the sidecar config.
+type ComposeConfig struct {
+	// CPUs is the CPU limit of the container, eg: "0.5". "0" means no limit.
+	CPUs string `yaml:"cpus"`
+	// Memory is the memory limit of the container, eg: "1024M". "0" means no limit.
+	Memory string `yaml:"memory"`
+	// Restart is the restart policy of the container, eg: "always" or "on-failure:5".
+	Restart string `yaml:"restart"`
+	// Environment is extra environment variables to set in the container.
+	Environment map[string]string `yaml:"environment"`
+	// Labels is extra labels to set on the container.
+	Labels map[string]string `yaml:"labels"`
+	// LogDriver is the logging driver of the container. Empty uses the engine default.
+	LogDriver string `yaml:"logDriver"`
+	// LogOptions is the options passed to the logging driver.
+	LogOptions map[string]string `yaml:"logOptions"`
+}
+
+// fileConfig is the subset of Config which can be set in the installer config file.
+type fileConfig struct {
+	SigningPublicKey string        `yaml:"signingPublicKey"`
+	ContainerRuntime string        `yaml:"containerRuntime"`
+	Compose          ComposeConfig `yaml:"compose"`
+	StopTimeout      time.Duration `yaml:"stopTimeout"`
+	Logs             LogsConfig    `yaml:"logs"`
+	Systemd          SystemdConfig `yaml:"systemd"`
 }
 
 // NewConfig returns the default installer configuration.
@@ -26,5 +146,222 @@ func NewConfig() *Config {
 		GithubOrg:              "ethpandaops",
 		GithubContributoorRepo: "contributoor",
 		GithubInstallerRepo:    "contributoor-installer",
+		SigningPublicKey:       SigningPublicKey,
+		ContainerRuntime:       "auto",
+		Compose: ComposeConfig{
+			CPUs:    "0.5",
+			Memory:  "1024M",
+			Restart: "always",
+		},
+		StopTimeout: 30 * time.Second,
+		Logs: LogsConfig{
+			MaxSize:  "100M",
+			MaxAge:   ptr(24 * time.Hour),
+			MaxFiles: ptr(7),
+		},
+		Systemd: SystemdConfig{
+			Scope:      SystemdScopeSystem,
+			Restart:    "always",
+			RestartSec: ptr(5 * time.Second),
+			MemoryMax:  "0",
+			CPUQuota:   "0",
+			Hardening: SystemdHardening{
+				NoNewPrivileges: ptr(true),
+				ProtectSystem:   "full",
+				ProtectHome:     "read-only",
+				PrivateTmp:      ptr(true),
+			},
+		},
+	}
+}
+
+// LoadFile overlays any settings in the installer config file at path onto the
+// config. A missing file is not an error.
+func (c *Config) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to read installer config: %w", err)
+	}
+
+	var file fileConfig
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse installer config %s: %w", path, err)
+	}
+
+	// A compiled in key wins, so a tampered installer.yaml can't swap it for its own.
+	if file.SigningPublicKey != "" && SigningPublicKey == "" {
+		c.SigningPublicKey = file.SigningPublicKey
+	}
+
+	if file.ContainerRuntime != "" {
+		c.ContainerRuntime = file.ContainerRuntime
+	}
+
+	if file.StopTimeout > 0 {
+		c.StopTimeout = file.StopTimeout
 	}
+
+	c.Compose.overlay(&file.Compose)
+	c.Logs.overlay(&file.Logs)
+	c.Systemd.overlay(&file.Systemd)
+
+	return nil
+}
+
+// SaveSystemdScope records the scope of the systemd run method in the installer config
+// file at path, creating it if needed. Any other settings in the file are kept.
+func SaveSystemdScope(path, scope string) error {
+	var doc yaml.Node
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read installer config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse installer config %s: %w", path, err)
+	}
+
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to parse installer config %s: not a mapping", path)
+	}
+
+	systemd := mappingValue(root, "systemd")
+	if systemd.Kind != yaml.MappingNode {
+		*systemd = yaml.Node{Kind: yaml.MappingNode}
+	}
+
+	*mappingValue(systemd, "scope") = yaml.Node{Kind: yaml.ScalarNode, Value: scope}
+
+	// Match the indentation of the hand written file.
+	var out bytes.Buffer
+
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode installer config: %w", err)
+	}
+
+	if err := os.WriteFile(path, out.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write installer config: %w", err)
+	}
+
+	return nil
+}
+
+// mappingValue returns the value of key in the mapping node, adding it if missing.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+
+	return value
+}
+
+// overlay copies any settings present in other onto c.
+func (c *ComposeConfig) overlay(other *ComposeConfig) {
+	if other.CPUs != "" {
+		c.CPUs = other.CPUs
+	}
+
+	if other.Memory != "" {
+		c.Memory = other.Memory
+	}
+
+	if other.Restart != "" {
+		c.Restart = other.Restart
+	}
+
+	if other.Environment != nil {
+		c.Environment = other.Environment
+	}
+
+	if other.Labels != nil {
+		c.Labels = other.Labels
+	}
+
+	if other.LogDriver != "" {
+		c.LogDriver = other.LogDriver
+	}
+
+	if other.LogOptions != nil {
+		c.LogOptions = other.LogOptions
+	}
+}
+
+// overlay copies any settings present in other onto c.
+func (c *LogsConfig) overlay(other *LogsConfig) {
+	if other.MaxSize != "" {
+		c.MaxSize = other.MaxSize
+	}
+
+	if other.MaxAge != nil {
+		c.MaxAge = other.MaxAge
+	}
+
+	if other.MaxFiles != nil {
+		c.MaxFiles = other.MaxFiles
+	}
+}
+
+// overlay copies any settings present in other onto c.
+func (c *SystemdConfig) overlay(other *SystemdConfig) {
+	if other.Scope != "" {
+		c.Scope = other.Scope
+	}
+
+	if other.Restart != "" {
+		c.Restart = other.Restart
+	}
+
+	if other.RestartSec != nil {
+		c.RestartSec = other.RestartSec
+	}
+
+	if other.MemoryMax != "" {
+		c.MemoryMax = other.MemoryMax
+	}
+
+	if other.CPUQuota != "" {
+		c.CPUQuota = other.CPUQuota
+	}
+
+	if other.Environment != nil {
+		c.Environment = other.Environment
+	}
+
+	if other.Hardening.NoNewPrivileges != nil {
+		c.Hardening.NoNewPrivileges = other.Hardening.NoNewPrivileges
+	}
+
+	if other.Hardening.ProtectSystem != "" {
+		c.Hardening.ProtectSystem = other.Hardening.ProtectSystem
+	}
+
+	if other.Hardening.ProtectHome != "" {
+		c.Hardening.ProtectHome = other.Hardening.ProtectHome
+	}
+
+	if other.Hardening.PrivateTmp != nil {
+		c.Hardening.PrivateTmp = other.Hardening.PrivateTmp
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
 }
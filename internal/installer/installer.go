@@ -12,6 +12,11 @@ var (
 	Implementation = "Contributoor-Installer"
 	GOOS           = runtime.GOOS
 	GOARCH         = runtime.GOARCH
+
+	// SigningPublicKey is the base64 encoded ed25519 key the release checksums are
+	// signed with, set at build time via ldflags from INSTALLER_SIGNING_PUBLIC_KEY in
+	// .goreleaser.yaml. Builds without one take signingPublicKey from installer.yaml.
+	SigningPublicKey = ""
 )
 
 func Full() string {
@@ -0,0 +1,307 @@
+// Package logrotate writes log files which are rotated by size and age, keeping a
+// limited number of gzip compressed segments, and reads them back.
+package logrotate
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// segmentTimeFormat is the timestamp added to the name of rotated segments. It
+	// sorts in the order the segments were rotated.
+	segmentTimeFormat = "20060102T150405.000"
+	// compressedExt is the extension of compressed segments.
+	compressedExt = ".gz"
+)
+
+// Options controls when a log file is rotated and how many segments are kept.
+type Options struct {
+	// MaxSize is the size in bytes the log file is rotated at. Zero disables size
+	// based rotation.
+	MaxSize int64
+	// MaxAge is how long a log file is written to before it's rotated. Zero disables
+	// age based rotation.
+	MaxAge time.Duration
+	// MaxFiles is how many rotated segments are kept. Zero keeps them all.
+	MaxFiles int
+}
+
+// Writer is an io.WriteCloser appending to a log file, which is rotated once it
+// reaches the configured size or age. Rotated segments are compressed in the
+// background. It's safe for concurrent use.
+type Writer struct {
+	path string
+	opts Options
+	now  func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	wg       sync.WaitGroup
+}
+
+// Open opens the log file at path for appending, creating it if needed.
+func Open(path string, opts Options) (*Writer, error) {
+	w := &Writer{path: path, opts: opts, now: time.Now}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Write appends p to the log file, rotating it first if it's full or too old. A
+// single write is never split across segments.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the log file, waiting for any segments still being compressed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var err error
+
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+
+	w.wg.Wait()
+
+	return err
+}
+
+// Rotate rotates the log file regardless of its size and age.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.rotate()
+}
+
+func (w *Writer) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil { //nolint:gosec // Logs are read by the user.
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // Logs are read by the user.
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	// We can't tell when an existing file was created, so its age counts from now.
+	w.file, w.size, w.openedAt = file, info.Size(), w.now()
+
+	return nil
+}
+
+func (w *Writer) shouldRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+
+	if w.opts.MaxSize > 0 && w.size+n > w.opts.MaxSize {
+		return true
+	}
+
+	return w.opts.MaxAge > 0 && w.now().Sub(w.openedAt) >= w.opts.MaxAge
+}
+
+func (w *Writer) rotate() error {
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return fmt.Errorf("failed to close log file: %w", err)
+		}
+
+		w.file = nil
+	}
+
+	segment := w.path + "." + w.now().UTC().Format(segmentTimeFormat)
+	if err := os.Rename(w.path, segment); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	// Compressing a large segment takes a while, so don't hold up writes.
+	w.wg.Add(1)
+
+	go func() {
+		defer w.wg.Done()
+
+		if err := compress(segment); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to compress log segment: %v\n", err)
+		}
+
+		if err := prune(w.path, w.opts.MaxFiles); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to remove old log segments: %v\n", err)
+		}
+	}()
+
+	return nil
+}
+
+// compress gzips the segment at path, replacing it with path.gz.
+func compress(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+
+	tmp := path + compressedExt + ".tmp"
+
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644) //nolint:gosec // Logs are read by the user.
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp)
+
+	gz := gzip.NewWriter(dst)
+
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		dst.Close()
+
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path+compressedExt); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// prune removes the oldest rotated segments of the log file at path, keeping
+// maxFiles of them.
+func prune(path string, maxFiles int) error {
+	if maxFiles <= 0 {
+		return nil
+	}
+
+	segments, err := Segments(path)
+	if err != nil {
+		return err
+	}
+
+	for len(segments) > maxFiles {
+		if err := os.Remove(segments[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		segments = segments[1:]
+	}
+
+	return nil
+}
+
+// Segments returns the rotated segments of the log file at path, oldest first. The
+// log file itself isn't included.
+func Segments(path string) ([]string, error) {
+	matches, err := filepath.Glob(globEscape(path) + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]string, 0, len(matches))
+
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, path+"."), compressedExt)
+		if _, err := time.Parse(segmentTimeFormat, stamp); err == nil {
+			segments = append(segments, match)
+		}
+	}
+
+	// Sort on the timestamp, as a segment may be part way through being compressed.
+	sort.Slice(segments, func(i, j int) bool {
+		return strings.TrimSuffix(segments[i], compressedExt) < strings.TrimSuffix(segments[j], compressedExt)
+	})
+
+	// A segment appears twice if it was compressed but the original not yet removed.
+	return dedupe(segments), nil
+}
+
+// dedupe drops uncompressed segments which have a compressed copy.
+func dedupe(segments []string) []string {
+	compressed := make(map[string]bool, len(segments))
+
+	for _, segment := range segments {
+		if strings.HasSuffix(segment, compressedExt) {
+			compressed[strings.TrimSuffix(segment, compressedExt)] = true
+		}
+	}
+
+	out := segments[:0]
+
+	for _, segment := range segments {
+		if !compressed[segment] {
+			out = append(out, segment)
+		}
+	}
+
+	return out
+}
+
+// globEscape escapes the glob metacharacters in path.
+func globEscape(path string) string {
+	var b strings.Builder
+
+	for _, r := range path {
+		if strings.ContainsRune(`*?[\`, r) {
+			b.WriteRune('\\')
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
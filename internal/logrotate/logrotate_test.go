@@ -0,0 +1,232 @@
+package logrotate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_RotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.log")
+
+	w, err := Open(path, Options{MaxSize: 20, MaxFiles: 2})
+	require.NoError(t, err)
+
+	clock := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	w.now = func() time.Time {
+		clock = clock.Add(time.Second)
+
+		return clock
+	}
+
+	for i := range 5 {
+		_, err := fmt.Fprintf(w, "line %d of the log\n", i)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+
+	// Each line fills a segment, only the newest two rotated segments are kept.
+	segments, err := Segments(path)
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+
+	for _, segment := range segments {
+		assert.True(t, strings.HasSuffix(segment, ".gz"), segment)
+	}
+
+	lines, err := Tail(path, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 2 of the log", "line 3 of the log", "line 4 of the log"}, lines)
+}
+
+func TestWriter_RotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.log")
+
+	w, err := Open(path, Options{MaxAge: time.Hour})
+	require.NoError(t, err)
+
+	clock := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	w.now = func() time.Time { return clock }
+	w.openedAt = clock
+
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	clock = clock.Add(30 * time.Minute)
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+
+	clock = clock.Add(30 * time.Minute)
+	_, err = w.Write([]byte("third\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, w.Close())
+
+	segments, err := Segments(path)
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(data))
+}
+
+func TestWriter_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "service.log")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("before\n"), 0600))
+
+	w, err := Open(path, Options{MaxSize: 1024})
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("after\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	_, err = w.Write([]byte("closed\n"))
+	require.ErrorIs(t, err, os.ErrClosed)
+
+	lines, err := Tail(path, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"before", "after"}, lines)
+}
+
+func TestTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.log")
+
+	w, err := Open(path, Options{})
+	require.NoError(t, err)
+
+	clock := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	w.now = func() time.Time {
+		clock = clock.Add(time.Second)
+
+		return clock
+	}
+
+	for i := range 10 {
+		_, err := fmt.Fprintf(w, "%d\n", i)
+		require.NoError(t, err)
+
+		if i%3 == 2 {
+			require.NoError(t, w.Rotate())
+		}
+	}
+
+	require.NoError(t, w.Close())
+
+	tests := []struct {
+		name     string
+		n        int
+		expected []string
+	}{
+		{name: "within the current file", n: 1, expected: []string{"9"}},
+		{name: "across segments", n: 5, expected: []string{"5", "6", "7", "8", "9"}},
+		{name: "more than there is", n: 100, expected: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}},
+		{name: "everything", n: 0, expected: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := Tail(path, tt.n, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, lines)
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		lines, err := Tail(filepath.Join(t.TempDir(), "missing.log"), 10, nil)
+		require.NoError(t, err)
+		assert.Empty(t, lines)
+	})
+}
+
+func TestSegments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "service.log")
+
+	for _, name := range []string{
+		"service.log",
+		"service.log.20250102T030405.000.gz",
+		"service.log.20250101T030405.000.gz",
+		// Compressed, but the original not removed yet.
+		"service.log.20250103T030405.000",
+		"service.log.20250103T030405.000.gz",
+		// Still being compressed.
+		"service.log.20250104T030405.000",
+		"service.log.20250104T030405.000.gz.tmp",
+		"service.log.old",
+		"debug.log.20250101T030405.000.gz",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0600))
+	}
+
+	segments, err := Segments(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "service.log.20250101T030405.000.gz"),
+		filepath.Join(dir, "service.log.20250102T030405.000.gz"),
+		filepath.Join(dir, "service.log.20250103T030405.000.gz"),
+		filepath.Join(dir, "service.log.20250104T030405.000"),
+	}, segments)
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.log")
+
+	w, err := Open(path, Options{})
+	require.NoError(t, err)
+
+	defer w.Close()
+
+	_, err = w.Write([]byte("already there\n"))
+	require.NoError(t, err)
+
+	var (
+		mu    sync.Mutex
+		lines []string
+		done  = make(chan error, 1)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		done <- Follow(ctx, path, func(line string) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			lines = append(lines, line)
+		})
+	}()
+
+	// Give Follow time to open the file before writing to it.
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = w.Write([]byte("before rotation\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Rotate())
+
+	_, err = w.Write([]byte("after rotation\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(lines) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Equal(t, []string{"before rotation", "after rotation"}, lines)
+}
@@ -0,0 +1,185 @@
+package logrotate
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// followPollInterval is how often a followed log file is checked for new lines.
+const followPollInterval = 250 * time.Millisecond
+
+// Tail returns the last n lines of the log file at path, reading back through its
+// rotated segments as needed. If n isn't positive, every line is returned. If keep
+// is set, only the lines it returns true for are counted. A missing log file has
+// no lines.
+func Tail(path string, n int, keep func(line string) bool) ([]string, error) {
+	segments, err := Segments(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+
+	for _, segment := range append(segments, path) {
+		err := readLines(segment, func(line string) {
+			if keep != nil && !keep(line) {
+				return
+			}
+
+			lines = append(lines, line)
+
+			// Only keep what we need, the segments can be large.
+			if n > 0 && len(lines) > 2*n {
+				lines = append(lines[:0], lines[len(lines)-n:]...)
+			}
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return lines, nil
+}
+
+// readLines calls fn with each line of the file at path, decompressing it if it's a
+// compressed segment.
+func readLines(path string, fn func(line string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	var r io.Reader = f
+
+	if strings.HasSuffix(path, compressedExt) {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("failed to decompress %s: %w", path, err)
+		}
+
+		defer gz.Close()
+
+		r = gz
+	}
+
+	reader := bufio.NewReader(r)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			fn(strings.TrimRight(line, "\r\n"))
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+}
+
+// Follow calls fn with each line appended to the log file at path until ctx is
+// cancelled, starting from its current end. It carries on across rotations, and
+// waits for the file to be created if it doesn't exist yet.
+func Follow(ctx context.Context, path string, fn func(line string)) error {
+	var (
+		f       *os.File
+		reader  *bufio.Reader
+		partial string
+	)
+
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+
+	// Skip whatever is already in the file, it's been shown by Tail.
+	if existing, err := os.Open(path); err == nil {
+		if _, err := existing.Seek(0, io.SeekEnd); err != nil {
+			existing.Close()
+
+			return fmt.Errorf("failed to seek %s: %w", path, err)
+		}
+
+		f, reader = existing, bufio.NewReader(existing)
+	}
+
+	ticker := time.NewTicker(followPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if reader != nil {
+			for {
+				line, err := reader.ReadString('\n')
+				partial += line
+
+				if err != nil {
+					break
+				}
+
+				fn(strings.TrimRight(partial, "\r\n"))
+				partial = ""
+			}
+		}
+
+		// Once the current file is drained, switch to the new one if it's been rotated.
+		if rotated(f, path) {
+			next, err := os.Open(path)
+			if err == nil {
+				if f != nil {
+					f.Close()
+				}
+
+				f, reader = next, bufio.NewReader(next)
+
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if partial != "" {
+				fn(partial)
+			}
+
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// rotated returns true if the file at path is no longer f, eg: because f has been
+// renamed to a segment and a new log file created.
+func rotated(f *os.File, path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	if f == nil {
+		return true
+	}
+
+	current, err := f.Stat()
+	if err != nil {
+		return true
+	}
+
+	return !os.SameFile(info, current)
+}
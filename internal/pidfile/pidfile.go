@@ -0,0 +1,225 @@
+// Package pidfile manages the pid files of processes running in the background.
+package pidfile
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+var (
+	// ErrInvalid is returned when a pid file doesn't contain a valid pid.
+	ErrInvalid = errors.New("invalid PID format")
+	// ErrLocked is returned when the pid file is locked by another process.
+	ErrLocked = errors.New("pid file is locked by another process")
+)
+
+// State is the state of the process recorded in a pid file.
+type State int
+
+const (
+	// StateStopped means there's no pid file.
+	StateStopped State = iota
+	// StateRunning means the process in the pid file is running.
+	StateRunning
+	// StateStale means the pid file was left behind, as its process has exited or
+	// the pid now belongs to another process.
+	StateStale
+)
+
+// String returns the lowercase name of the state.
+func (s State) String() string {
+	switch s {
+	case StateRunning:
+		return "running"
+	case StateStale:
+		return "stale"
+	default:
+		return "stopped"
+	}
+}
+
+// PidFile is a file holding the pid of a process running in the background.
+type PidFile struct {
+	path string
+}
+
+// New returns the pid file at path.
+func New(path string) *PidFile {
+	return &PidFile{path: path}
+}
+
+// Path returns the path of the pid file.
+func (p *PidFile) Path() string {
+	return p.path
+}
+
+// Read returns the pid in the pid file. Surrounding whitespace, eg: a trailing
+// newline written by another tool, is ignored.
+func (p *PidFile) Read() (int, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return 0, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, ErrInvalid
+	}
+
+	return pid, nil
+}
+
+// Write writes pid to the pid file. The file is replaced atomically, so readers
+// never see it part written.
+func (p *PidFile) Write(pid int) error {
+	tmp, err := os.CreateTemp(filepath.Dir(p.path), filepath.Base(p.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create pid file: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := fmt.Fprintf(tmp, "%d\n", pid); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("failed to write pid file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write pid file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), p.path); err != nil {
+		return fmt.Errorf("failed to write pid file: %w", err)
+	}
+
+	return nil
+}
+
+// Remove removes the pid file. A missing file is not an error.
+func (p *PidFile) Remove() error {
+	if err := os.Remove(p.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove pid file: %w", err)
+	}
+
+	return nil
+}
+
+// LockPath returns the path of the lock file guarding the pid file.
+func (p *PidFile) LockPath() string {
+	return p.path + ".lock"
+}
+
+// Lock takes an advisory lock guarding the pid file, so only one process at a time
+// can start the process it records. It fails with ErrLocked rather than waiting if
+// the lock is held. The lock is held until the returned function is called.
+func (p *PidFile) Lock() (func(), error) {
+	// The lock is on a separate file, as the pid file itself is replaced on write.
+	f, err := os.OpenFile(p.LockPath(), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrLocked
+		}
+
+		return nil, fmt.Errorf("failed to lock %s: %w", p.LockPath(), err)
+	}
+
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+// Check returns the state of the process in the pid file, along with its pid. If
+// match is set, the process is only considered running if match returns true for
+// its command line, so a pid reused by another process isn't mistaken for it.
+func (p *PidFile) Check(ctx context.Context, match func(cmdline []string) bool) (State, int, error) {
+	pid, err := p.Read()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return StateStopped, 0, nil
+		}
+
+		return StateStopped, 0, err
+	}
+
+	if !Alive(pid) {
+		return StateStale, pid, nil
+	}
+
+	if match == nil {
+		return StateRunning, pid, nil
+	}
+
+	cmdline, err := Cmdline(ctx, pid)
+	if err != nil {
+		// The process may have exited in the meantime.
+		if !Alive(pid) {
+			return StateStale, pid, nil
+		}
+
+		return StateStopped, pid, err
+	}
+
+	if !match(cmdline) {
+		return StateStale, pid, nil
+	}
+
+	return StateRunning, pid, nil
+}
+
+// Alive returns true if the process with the given pid is running. Zombies have
+// exited, they just haven't been reaped by their parent yet.
+func Alive(pid int) bool {
+	// Signal 0 only checks the process exists. EPERM means it exists, but belongs to
+	// another user.
+	if err := syscall.Kill(pid, 0); err != nil && !errors.Is(err, syscall.EPERM) {
+		return false
+	}
+
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return true
+	}
+
+	// The state follows the command name, which is in parentheses and may contain spaces.
+	if i := bytes.LastIndexByte(stat, ')'); i >= 0 && i+2 < len(stat) {
+		return stat[i+2] != 'Z'
+	}
+
+	return true
+}
+
+// Cmdline returns the command line of the process with the given pid. It's read
+// from /proc where available, and from ps otherwise, eg: on macOS.
+func Cmdline(ctx context.Context, pid int) ([]string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err == nil {
+		return strings.Split(strings.TrimRight(string(data), "\x00"), "\x00"), nil
+	}
+
+	if _, statErr := os.Stat("/proc/self"); statErr == nil {
+		return nil, fmt.Errorf("failed to read command line of process %d: %w", pid, err)
+	}
+
+	output, err := exec.CommandContext(ctx, "ps", "-o", "command=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read command line of process %d: %w", pid, err)
+	}
+
+	return strings.Fields(string(output)), nil
+}
@@ -0,0 +1,184 @@
+package pidfile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedPid   int
+		expectedError error
+	}{
+		{name: "plain", content: "1234", expectedPid: 1234},
+		{name: "trailing newline", content: "1234\n", expectedPid: 1234},
+		{name: "surrounding whitespace", content: " \t1234\r\n", expectedPid: 1234},
+		{name: "empty", content: "", expectedError: ErrInvalid},
+		{name: "not a number", content: "abc", expectedError: ErrInvalid},
+		{name: "negative", content: "-1", expectedError: ErrInvalid},
+		{name: "two pids", content: "12 34", expectedError: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.pid")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			pid, err := New(path).Read()
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPid, pid)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	p := New(filepath.Join(dir, "test.pid"))
+
+	require.NoError(t, p.Write(1234))
+	require.NoError(t, p.Write(5678))
+
+	pid, err := p.Read()
+	require.NoError(t, err)
+	assert.Equal(t, 5678, pid)
+
+	// No temporary files are left behind.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, p.Remove())
+	require.NoError(t, p.Remove())
+	assert.NoFileExists(t, p.Path())
+}
+
+func TestLock(t *testing.T) {
+	p := New(filepath.Join(t.TempDir(), "test.pid"))
+
+	unlock, err := p.Lock()
+	require.NoError(t, err)
+
+	// flock locks belong to the open file, so a second open conflicts even within
+	// the same process.
+	_, err = p.Lock()
+	require.ErrorIs(t, err, ErrLocked)
+
+	unlock()
+
+	unlock, err = p.Lock()
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestCheck(t *testing.T) {
+	// Find a pid which is no longer running, by running a process to completion.
+	exited := exec.Command("true")
+	require.NoError(t, exited.Run())
+
+	isTest := func(cmdline []string) bool {
+		return slices.Equal(cmdline, os.Args)
+	}
+
+	isOther := func(cmdline []string) bool {
+		return false
+	}
+
+	tests := []struct {
+		name          string
+		content       string
+		match         func(cmdline []string) bool
+		expectedState State
+		expectedError error
+	}{
+		{
+			name:          "no pid file",
+			expectedState: StateStopped,
+		},
+		{
+			name:          "running",
+			content:       fmt.Sprintf("%d\n", os.Getpid()),
+			match:         isTest,
+			expectedState: StateRunning,
+		},
+		{
+			name:          "running without identity check",
+			content:       fmt.Sprintf("%d", os.Getpid()),
+			expectedState: StateRunning,
+		},
+		{
+			name:          "process exited",
+			content:       fmt.Sprintf("%d", exited.Process.Pid),
+			match:         isTest,
+			expectedState: StateStale,
+		},
+		{
+			name:          "pid reused by another process",
+			content:       fmt.Sprintf("%d", os.Getpid()),
+			match:         isOther,
+			expectedState: StateStale,
+		},
+		{
+			name:          "invalid",
+			content:       "garbage",
+			expectedError: ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(filepath.Join(t.TempDir(), "test.pid"))
+
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(p.Path(), []byte(tt.content), 0600))
+			}
+
+			state, _, err := p.Check(context.Background(), tt.match)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedState, state)
+		})
+	}
+}
+
+func TestAlive(t *testing.T) {
+	assert.True(t, Alive(os.Getpid()))
+
+	// A process which has exited but not been reaped is a zombie.
+	cmd := exec.Command("true")
+	require.NoError(t, cmd.Start())
+
+	require.Eventually(t, func() bool {
+		return !Alive(cmd.Process.Pid)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, cmd.Wait())
+	assert.False(t, Alive(cmd.Process.Pid))
+}
+
+func TestCmdline(t *testing.T) {
+	cmdline, err := Cmdline(context.Background(), os.Getpid())
+	require.NoError(t, err)
+	assert.Equal(t, os.Args, cmdline)
+}
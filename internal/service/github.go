@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -45,10 +46,10 @@ var (
 // GitHubService defines the interface for GitHub operations.
 type GitHubService interface {
 	// GetLatestVersion returns the latest version tag (e.g., "0.0.1") from GitHub releases.
-	GetLatestVersion() (string, error)
+	GetLatestVersion(ctx context.Context) (string, error)
 
 	// VersionExists checks if a specific version exists in the GitHub releases.
-	VersionExists(version string) (bool, error)
+	VersionExists(ctx context.Context, version string) (bool, error)
 }
 
 // GitHubRelease is a struct that represents a GitHub release.
@@ -82,21 +83,10 @@ func NewGitHubService(log *logrus.Logger, installerCfg *installer.Config) (GitHu
 }
 
 // GetLatestVersion returns the latest version tag (e.g., "0.0.1") from GitHub releases.
-func (s *githubService) GetLatestVersion() (string, error) {
-	resp, err := s.client.Get(s.githubURL.String())
+func (s *githubService) GetLatestVersion(ctx context.Context) (string, error) {
+	releases, err := s.getReleases(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch releases: %w", err)
-	}
-
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
-	}
-
-	var releases []GitHubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
-		return "", fmt.Errorf("failed to parse releases response: %w", err)
+		return "", err
 	}
 
 	// Find highest version tag
@@ -161,21 +151,10 @@ func (s *githubService) GetLatestVersion() (string, error) {
 }
 
 // VersionExists checks if a specific version exists in the GitHub releases.
-func (s *githubService) VersionExists(version string) (bool, error) {
-	resp, err := s.client.Get(s.githubURL.String())
+func (s *githubService) VersionExists(ctx context.Context, version string) (bool, error) {
+	releases, err := s.getReleases(ctx)
 	if err != nil {
-		return false, fmt.Errorf("failed to fetch releases: %w", err)
-	}
-
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
-	}
-
-	var releases []GitHubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
-		return false, fmt.Errorf("failed to parse releases response: %w", err)
+		return false, err
 	}
 
 	// Add 'v' prefix if not present
@@ -193,3 +172,29 @@ func (s *githubService) VersionExists(version string) (bool, error) {
 
 	return false, nil
 }
+
+// getReleases fetches the list of releases from the GitHub API.
+func (s *githubService) getReleases(ctx context.Context) ([]GitHubRelease, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.githubURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var releases []GitHubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases response: %w", err)
+	}
+
+	return releases, nil
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -82,7 +84,7 @@ func TestGitHubService_GetLatestVersion(t *testing.T) {
 				return
 			}
 
-			got, err := svc.GetLatestVersion()
+			got, err := svc.GetLatestVersion(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetLatestVersion() error = %v, wantErr %v", err, tt.wantErr)
 
@@ -161,7 +163,7 @@ func TestGitHubService_VersionExists(t *testing.T) {
 				return
 			}
 
-			exists, err := svc.VersionExists(tt.version)
+			exists, err := svc.VersionExists(context.Background(), tt.version)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("VersionExists() error = %v, wantErr %v", err, tt.wantErr)
@@ -218,3 +220,21 @@ func TestValidateGitHubURL(t *testing.T) {
 		})
 	}
 }
+
+func TestGitHubService_Cancelled(t *testing.T) {
+	svc, err := NewGitHubService(logrus.New(), installer.NewConfig())
+	if err != nil {
+		t.Fatalf("NewGitHubService() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := svc.GetLatestVersion(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetLatestVersion() error = %v, want %v", err, context.Canceled)
+	}
+
+	if _, err := svc.VersionExists(ctx, "1.0.0"); !errors.Is(err, context.Canceled) {
+		t.Errorf("VersionExists() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -10,6 +10,7 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -40,31 +41,31 @@ func (m *MockGitHubService) EXPECT() *MockGitHubServiceMockRecorder {
 }
 
 // GetLatestVersion mocks base method.
-func (m *MockGitHubService) GetLatestVersion() (string, error) {
+func (m *MockGitHubService) GetLatestVersion(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLatestVersion")
+	ret := m.ctrl.Call(m, "GetLatestVersion", ctx)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLatestVersion indicates an expected call of GetLatestVersion.
-func (mr *MockGitHubServiceMockRecorder) GetLatestVersion() *gomock.Call {
+func (mr *MockGitHubServiceMockRecorder) GetLatestVersion(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestVersion", reflect.TypeOf((*MockGitHubService)(nil).GetLatestVersion))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestVersion", reflect.TypeOf((*MockGitHubService)(nil).GetLatestVersion), ctx)
 }
 
 // VersionExists mocks base method.
-func (m *MockGitHubService) VersionExists(version string) (bool, error) {
+func (m *MockGitHubService) VersionExists(ctx context.Context, version string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VersionExists", version)
+	ret := m.ctrl.Call(m, "VersionExists", ctx, version)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // VersionExists indicates an expected call of VersionExists.
-func (mr *MockGitHubServiceMockRecorder) VersionExists(version any) *gomock.Call {
+func (mr *MockGitHubServiceMockRecorder) VersionExists(ctx, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VersionExists", reflect.TypeOf((*MockGitHubService)(nil).VersionExists), version)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VersionExists", reflect.TypeOf((*MockGitHubService)(nil).VersionExists), ctx, version)
 }
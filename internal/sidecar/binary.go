@@ -1,18 +1,25 @@
 package sidecar
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"runtime"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/docker/go-units"
 	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/logrotate"
+	"github.com/ethpandaops/contributoor-installer/internal/pidfile"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
 	"github.com/mitchellh/go-homedir"
 	"github.com/sirupsen/logrus"
 )
@@ -28,48 +35,94 @@ type binarySidecar struct {
 	logger       *logrus.Logger
 	sidecarCfg   ConfigManager
 	installerCfg *installer.Config
-	stdout       *os.File
-	stderr       *os.File
+}
+
+func init() {
+	RegisterRunner(config.RunMethod_RUN_METHOD_BINARY, func(logger *logrus.Logger, sidecarCfg ConfigManager, installerCfg *installer.Config) (SidecarRunner, error) {
+		return NewBinarySidecar(logger, sidecarCfg, installerCfg)
+	})
 }
 
 // NewBinarySidecar creates a new BinarySidecar.
 func NewBinarySidecar(logger *logrus.Logger, sidecarCfg ConfigManager, installerCfg *installer.Config) (BinarySidecar, error) {
-	expandedDir, err := homedir.Expand(sidecarCfg.Get().ContributoorDirectory)
+	return &binarySidecar{
+		logger:       logger,
+		sidecarCfg:   sidecarCfg,
+		installerCfg: installerCfg,
+	}, nil
+}
+
+// BinaryLogs returns the paths of the log files sentry's stdout and stderr are
+// written to by the binary run method.
+func BinaryLogs(dir string) (stdout, stderr string) {
+	return filepath.Join(dir, "logs", "debug.log"), filepath.Join(dir, "logs", "service.log")
+}
+
+// OpenBinaryLogs opens the log files of the binary sidecar in dir, rotated as
+// configured in cfg.
+func OpenBinaryLogs(dir string, cfg installer.LogsConfig) (stdout, stderr *logrotate.Writer, err error) {
+	opts, err := logOptions(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to expand config path: %w", err)
+		return nil, nil, err
 	}
 
-	logsDir := filepath.Join(expandedDir, "logs")
+	stdoutPath, stderrPath := BinaryLogs(dir)
 
-	// Open log files
-	stdout, err := os.OpenFile(filepath.Join(logsDir, "debug.log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	stdout, err = logrotate.Open(stdoutPath, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open stdout log file: %w", err)
+		return nil, nil, fmt.Errorf("failed to open stdout log file: %w", err)
 	}
 
-	stderr, err := os.OpenFile(filepath.Join(logsDir, "service.log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	stderr, err = logrotate.Open(stderrPath, opts)
 	if err != nil {
 		stdout.Close()
 
-		return nil, fmt.Errorf("failed to open stderr log file: %w", err)
+		return nil, nil, fmt.Errorf("failed to open stderr log file: %w", err)
 	}
 
-	return &binarySidecar{
-		logger:       logger,
-		stdout:       stdout,
-		stderr:       stderr,
-		sidecarCfg:   sidecarCfg,
-		installerCfg: installerCfg,
-	}, nil
+	return stdout, stderr, nil
+}
+
+// logOptions returns the rotation options of the log files from the installer
+// settings.
+func logOptions(cfg installer.LogsConfig) (logrotate.Options, error) {
+	var opts logrotate.Options
+
+	if cfg.MaxSize != "" {
+		size, err := units.RAMInBytes(cfg.MaxSize)
+		if err != nil || size < 0 {
+			return opts, fmt.Errorf("invalid logs.maxSize %q, expected a size like 100M", cfg.MaxSize)
+		}
+
+		opts.MaxSize = size
+	}
+
+	if cfg.MaxAge != nil {
+		if *cfg.MaxAge < 0 {
+			return opts, fmt.Errorf("invalid logs.maxAge %s, must not be negative", *cfg.MaxAge)
+		}
+
+		opts.MaxAge = *cfg.MaxAge
+	}
+
+	if cfg.MaxFiles != nil {
+		if *cfg.MaxFiles < 0 {
+			return opts, fmt.Errorf("invalid logs.maxFiles %d, must not be negative", *cfg.MaxFiles)
+		}
+
+		opts.MaxFiles = *cfg.MaxFiles
+	}
+
+	return opts, nil
 }
 
 // Start starts the binary service.
-func (s *binarySidecar) Start() error {
+func (s *binarySidecar) Start(ctx context.Context) error {
 	if err := s.checkBinaryExists(); err != nil {
 		return wrapNotInstalledError(err, "binary")
 	}
 
-	if err := s.checkBinaryVersion(); err != nil {
+	if err := s.checkBinaryVersion(ctx); err != nil {
 		return fmt.Errorf("version check failed: %w", err)
 	}
 
@@ -86,34 +139,66 @@ func (s *binarySidecar) Start() error {
 		return fmt.Errorf("failed to expand config path: %w", err)
 	}
 
-	configPath := filepath.Join(expandedDir, "config.yaml")
-	cmd := exec.Command(binaryPath, "--config", configPath)
-	cmd.Stdout = s.stderr
-	cmd.Stderr = s.stdout
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find contributoor executable: %w", err)
+	}
 
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start binary: %w", err)
+	pidFile := s.pidFile(expandedDir)
+
+	// Hold the lock until the pid file is written, so a concurrent start can't launch
+	// a second supervisor.
+	unlock, err := pidFile.Lock()
+	if err != nil {
+		if errors.Is(err, pidfile.ErrLocked) {
+			return fmt.Errorf("contributoor is already being started")
+		}
+
+		return err
 	}
 
-	pidFile := filepath.Join(cfg.ContributoorDirectory, "contributoor.pid")
-	if err := os.WriteFile(pidFile, fmt.Appendf(nil, "%d", cmd.Process.Pid), 0600); err != nil {
-		return fmt.Errorf("failed to write pid file: %w", err)
+	defer unlock()
+
+	state, pid, err := pidFile.Check(ctx, s.isSidecarProcess(expandedDir))
+	if err != nil {
+		return fmt.Errorf("failed to check pid file: %w", err)
 	}
 
-	// Start a goroutine to wait for the process and clean up
-	go func() {
-		defer s.stdout.Close()
-		defer s.stderr.Close()
+	if state == pidfile.StateRunning {
+		return fmt.Errorf("contributoor is already running with pid %d", pid)
+	}
 
-		if err := cmd.Wait(); err != nil {
-			s.logger.Errorf("Process exited with error: %v", err)
-		}
+	// The supervisor writes the logs, but has nowhere to report bad settings.
+	if _, err := logOptions(s.installerCfg.Logs); err != nil {
+		return err
+	}
 
-		// Clean up pid file
-		if err := os.Remove(pidFile); err != nil {
-			s.logger.Errorf("Failed to remove pid file: %v", err)
-		}
-	}()
+	// Don't report the state left behind by the previous supervisor.
+	if err := os.Remove(filepath.Join(expandedDir, supervisorStateFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove supervisor state: %w", err)
+	}
+
+	// The sidecar outlives this command, so it's run by a supervisor in its own session,
+	// detached from the terminal, rather than being tied to ctx. The supervisor opens
+	// the log files itself, so it can rotate them.
+	cmd := exec.Command(executable, supervisorArgs(expandedDir, s.installerCfg.Instance)...) //nolint:gosec // our own executable.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start supervisor: %w", err)
+	}
+
+	// The supervisor removes the pid file when it exits.
+	if err := pidFile.Write(cmd.Process.Pid); err != nil {
+		// Without a pid file, the supervisor couldn't be stopped again.
+		_ = cmd.Process.Kill()
+
+		return err
+	}
+
+	if err := cmd.Process.Release(); err != nil {
+		return fmt.Errorf("failed to detach supervisor: %w", err)
+	}
 
 	fmt.Printf("%sContributoor started successfully%s\n", tui.TerminalColorGreen, tui.TerminalColorReset)
 
@@ -121,42 +206,49 @@ func (s *binarySidecar) Start() error {
 }
 
 // Stop stops the binary service.
-func (s *binarySidecar) Stop() error {
+func (s *binarySidecar) Stop(ctx context.Context) error {
 	if err := s.checkBinaryExists(); err != nil {
 		return wrapNotInstalledError(err, "binary")
 	}
 
-	cfg := s.sidecarCfg.Get()
+	expandedDir, err := homedir.Expand(s.sidecarCfg.Get().ContributoorDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to expand config path: %w", err)
+	}
 
-	pidFile := filepath.Join(cfg.ContributoorDirectory, "contributoor.pid")
+	pidFile := s.pidFile(expandedDir)
 
-	pidBytes, err := os.ReadFile(pidFile)
+	// Never signal a process which has since been given our pid.
+	state, pid, err := pidFile.Check(ctx, s.isSidecarProcess(expandedDir))
 	if err != nil {
-		return fmt.Errorf("failed to read pid file: %w", err)
+		return fmt.Errorf("failed to check pid file: %w", err)
 	}
 
-	pidStr := string(pidBytes)
-	if !regexp.MustCompile(`^\d+$`).MatchString(pidStr) {
-		return fmt.Errorf("invalid PID format")
-	}
+	switch state {
+	case pidfile.StateStopped:
+		return fmt.Errorf("contributoor isn't running")
+	case pidfile.StateStale:
+		if err := pidFile.Remove(); err != nil {
+			return err
+		}
 
-	//nolint:gosec // sanitized.
-	cmd := exec.Command("kill", string(pidBytes))
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to stop process: %w", err)
+		return fmt.Errorf("contributoor isn't running, removed stale pid file %s", pidFile.Path())
 	}
 
-	os.Remove(pidFile)
+	// The supervisor leads the process group of the sidecar, so killing the group
+	// doesn't leave sentry running without it. Sentry processes started before
+	// sidecars were supervised share the group of the command which started them.
+	pgid, err := syscall.Getpgid(pid)
+	if err != nil {
+		pgid = 0
+	}
 
-	// Close log files if they exist
-	if s.stdout != nil {
-		s.stdout.Close()
-		s.stdout = nil
+	if err := terminateProcess(ctx, pid, s.stopTimeout(), pgid == pid); err != nil {
+		return err
 	}
 
-	if s.stderr != nil {
-		s.stderr.Close()
-		s.stderr = nil
+	if err := pidFile.Remove(); err != nil {
+		return err
 	}
 
 	fmt.Printf("%sContributoor stopped successfully%s\n", tui.TerminalColorGreen, tui.TerminalColorReset)
@@ -164,73 +256,107 @@ func (s *binarySidecar) Stop() error {
 	return nil
 }
 
+// stopTimeout returns how long to wait for the sidecar to stop before killing it.
+func (s *binarySidecar) stopTimeout() time.Duration {
+	if s.installerCfg == nil || s.installerCfg.StopTimeout <= 0 {
+		return installer.NewConfig().StopTimeout
+	}
+
+	return s.installerCfg.StopTimeout
+}
+
+// supervisorArgs returns the arguments starting the supervisor of the sidecar in dir.
+// Named instances are selected from the root config path, as with any other command,
+// so the supervisor also picks up the root installer.yaml.
+func supervisorArgs(dir, instance string) []string {
+	if instance == "" {
+		return []string{"--config-path", dir, "supervise"}
+	}
+
+	root := filepath.Dir(filepath.Dir(dir))
+
+	return []string{"--config-path", root, "--instance", instance, "supervise"}
+}
+
+// pidFile returns the pid file of the sidecar in dir.
+func (s *binarySidecar) pidFile(dir string) *pidfile.PidFile {
+	return pidfile.New(filepath.Join(dir, "contributoor.pid"))
+}
+
+// isSidecarProcess returns a matcher for the command line of the sidecar in dir.
+func (s *binarySidecar) isSidecarProcess(dir string) func(cmdline []string) bool {
+	return func(cmdline []string) bool {
+		return IsBinarySidecarProcess(cmdline, dir)
+	}
+}
+
 // Status returns the current state of the binary process.
-func (s *binarySidecar) Status() (string, error) {
-	cfg := s.sidecarCfg.Get()
-	pidFile := filepath.Join(cfg.ContributoorDirectory, "contributoor.pid")
+func (s *binarySidecar) Status(ctx context.Context) (*Status, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
 
-	// If no PID file, process is not running.
-	pidBytes, err := os.ReadFile(pidFile)
+	expandedDir, err := homedir.Expand(s.sidecarCfg.Get().ContributoorDirectory)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return "stopped", nil
+		return nil, fmt.Errorf("failed to expand config path: %w", err)
+	}
+
+	pidFile := s.pidFile(expandedDir)
+
+	state, pid, err := pidFile.Check(ctx, s.isSidecarProcess(expandedDir))
+	if err != nil {
+		if errors.Is(err, pidfile.ErrInvalid) {
+			return &Status{State: StateUnknown, Detail: "unknown"}, err
 		}
 
-		return "", fmt.Errorf("failed to read pid file: %w", err)
+		return nil, fmt.Errorf("failed to check pid file: %w", err)
 	}
 
-	pidStr := string(pidBytes)
-	if !regexp.MustCompile(`^\d+$`).MatchString(pidStr) {
-		return "unknown", fmt.Errorf("invalid PID format")
+	switch state {
+	case pidfile.StateStopped:
+		return &Status{State: StateStopped, Detail: "stopped"}, nil
+	case pidfile.StateStale:
+		return &Status{State: StateStopped, Detail: "stale pid file"}, nil
 	}
 
-	// kill -0 just checks if process exists. It doesn't actually send a
-	// signal that affects the process.
-	cmd := exec.Command("kill", "-0", pidStr)
-	if err := cmd.Run(); err != nil {
-		os.Remove(pidFile)
+	status := &Status{State: StateRunning, Detail: "running", PID: pid}
 
-		//nolint:nilerr // We don't care about the error here.
-		return "stopped", nil
+	// The pid file is written when the process is started.
+	if info, err := os.Stat(pidFile.Path()); err == nil {
+		status.StartedAt = info.ModTime()
 	}
 
-	return "running", nil
+	// The pid file holds the supervisor, which records the state of the sidecar itself.
+	if supervisorState, err := readSupervisorState(expandedDir); err == nil {
+		applySupervisorState(status, supervisorState)
+	} else if !os.IsNotExist(err) {
+		s.logger.Debugf("failed to read supervisor state: %v", err)
+	}
+
+	status.setUptime(time.Now())
+
+	return status, nil
 }
 
 // IsRunning checks if the binary service is running.
-func (s *binarySidecar) IsRunning() (bool, error) {
-	cfg := s.sidecarCfg.Get()
+func (s *binarySidecar) IsRunning(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
 
-	pidFile := filepath.Join(cfg.ContributoorDirectory, "contributoor.pid")
-	if _, err := os.Stat(pidFile); os.IsNotExist(err) {
-		return false, nil
+	expandedDir, err := homedir.Expand(s.sidecarCfg.Get().ContributoorDirectory)
+	if err != nil {
+		return false, fmt.Errorf("failed to expand config path: %w", err)
 	}
 
-	pidBytes, err := os.ReadFile(pidFile)
+	state, _, err := s.pidFile(expandedDir).Check(ctx, s.isSidecarProcess(expandedDir))
 	if err != nil {
 		return false, err
 	}
 
-	pidStr := string(pidBytes)
-	if !regexp.MustCompile(`^\d+$`).MatchString(pidStr) {
-		return false, fmt.Errorf("invalid PID format")
-	}
-
-	// kill -0 just checks if process exists. It doesn't actually send a
-	// signal that affects the process.
-	cmd := exec.Command("kill", "-0", pidStr)
-	if err := cmd.Run(); err != nil {
-		os.Remove(pidFile)
-
-		//nolint:nilerr // We don't care about the error here.
-		return false, nil
-	}
-
-	return true, nil
+	return state == pidfile.StateRunning, nil
 }
 
 // Update updates the binary service.
-func (s *binarySidecar) Update() error {
+func (s *binarySidecar) Update(ctx context.Context) error {
 	if err := s.checkBinaryExists(); err != nil {
 		return wrapNotInstalledError(err, "binary")
 	}
@@ -238,12 +364,12 @@ func (s *binarySidecar) Update() error {
 	cfg := s.sidecarCfg.Get()
 
 	// Update installer first
-	if err := updateInstaller(cfg, s.installerCfg); err != nil {
+	if err := updateInstaller(ctx, cfg, s.installerCfg); err != nil {
 		return fmt.Errorf("failed to update installer: %w", err)
 	}
 
 	// Update sidecar
-	if err := s.updateSidecar(); err != nil {
+	if err := s.updateSidecar(ctx); err != nil {
 		return fmt.Errorf("failed to update sidecar: %w", err)
 	}
 
@@ -251,11 +377,22 @@ func (s *binarySidecar) Update() error {
 }
 
 // Logs shows the logs from the binary sidecar.
-func (s *binarySidecar) Logs(tailLines int, follow bool) error {
+func (s *binarySidecar) Logs(ctx context.Context, opts LogOptions) error {
 	if err := s.checkBinaryExists(); err != nil {
 		return wrapNotInstalledError(err, "binary")
 	}
 
+	printers := make([]*logPrinter, 2)
+
+	for i := range printers {
+		printer, err := newLogPrinter(opts)
+		if err != nil {
+			return err
+		}
+
+		printers[i] = printer
+	}
+
 	cfg := s.sidecarCfg.Get()
 
 	expandedDir, err := homedir.Expand(cfg.ContributoorDirectory)
@@ -264,35 +401,136 @@ func (s *binarySidecar) Logs(tailLines int, follow bool) error {
 	}
 
 	var (
-		debugLog   = filepath.Join(expandedDir, "logs", "debug.log")
-		serviceLog = filepath.Join(expandedDir, "logs", "service.log")
-		args       = make([]string, 0)
+		stdoutLog, stderrLog = BinaryLogs(expandedDir)
+		out, _               = opts.outputs()
 	)
 
-	if follow {
-		args = append(args, "-f")
+	for i, path := range []string{stdoutLog, stderrLog} {
+		// The time window is applied before taking the tail, as the log files aren't
+		// indexed by time like the journal and docker's logs are.
+		lines, err := logrotate.Tail(path, opts.Tail, printers[i].filter.withinWindow)
+		if err != nil {
+			return fmt.Errorf("failed to read logs: %w", err)
+		}
+
+		for _, line := range lines {
+			if err := printers[i].print(out, line); err != nil {
+				return err
+			}
+		}
 	}
 
-	if tailLines > 0 {
-		args = append(args, "-n", fmt.Sprintf("%d", tailLines))
+	if !opts.Follow {
+		return nil
 	}
 
-	args = append(args, debugLog, serviceLog)
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	errs := make([]error, 2)
+
+	for i, path := range []string{stdoutLog, stderrLog} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs[i] = logrotate.Follow(ctx, path, func(line string) {
+				if !printers[i].filter.withinWindow(line) {
+					return
+				}
 
-	cmd := exec.Command("tail", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+				mu.Lock()
+				defer mu.Unlock()
 
-	return cmd.Run()
+				_ = printers[i].print(out, line)
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
 }
 
 // Version returns the version of the currently running binary.
-func (s *binarySidecar) Version() (string, error) {
-	return s.getBinaryVersion()
+func (s *binarySidecar) Version(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	return s.getBinaryVersion(ctx)
+}
+
+// Provision downloads the sentry binary for the configured version, unless it's
+// already installed.
+func (s *binarySidecar) Provision(ctx context.Context) error {
+	if err := s.checkBinaryExists(); err == nil {
+		if version, err := s.getBinaryVersion(ctx); err == nil && version == s.sidecarCfg.Get().Version {
+			return nil
+		}
+	}
+
+	expandedDir, err := homedir.Expand(s.sidecarCfg.Get().ContributoorDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to expand config path: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(expandedDir, "bin"), 0755); err != nil {
+		return fmt.Errorf("failed to create bin directory: %w", err)
+	}
+
+	return s.updateSidecar(ctx)
+}
+
+// UninstallSteps returns the steps to remove the binary's pid file and supervisor state.
+func (s *binarySidecar) UninstallSteps() []UninstallStep {
+	expandedDir, err := homedir.Expand(s.sidecarCfg.Get().ContributoorDirectory)
+	if err != nil {
+		return []UninstallStep{{
+			Description: "Remove pid file and supervisor state",
+			Run: func(ctx context.Context) error {
+				return fmt.Errorf("failed to expand config path: %w", err)
+			},
+		}}
+	}
+
+	var (
+		pidFile   = s.pidFile(expandedDir)
+		stateFile = filepath.Join(expandedDir, supervisorStateFile)
+	)
+
+	return []UninstallStep{
+		{
+			Description: fmt.Sprintf("Remove pid file %s", pidFile.Path()),
+			Run: func(ctx context.Context) error {
+				if err := pidFile.Remove(); err != nil {
+					return err
+				}
+
+				if err := os.Remove(pidFile.LockPath()); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove pid lock file: %w", err)
+				}
+
+				return nil
+			},
+		},
+		{
+			Description: fmt.Sprintf("Remove supervisor state %s", stateFile),
+			Run: func(ctx context.Context) error {
+				if err := os.Remove(stateFile); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove supervisor state: %w", err)
+				}
+
+				return nil
+			},
+		},
+	}
 }
 
 // updateSidecar updates the sidecar binary to the specified version.
-func (s *binarySidecar) updateSidecar() error {
+func (s *binarySidecar) updateSidecar(ctx context.Context) error {
 	cfg := s.sidecarCfg.Get()
 
 	expandedDir, err := homedir.Expand(cfg.ContributoorDirectory)
@@ -305,7 +543,7 @@ func (s *binarySidecar) updateSidecar() error {
 	releaseDir := filepath.Join(expandedDir, "releases", fmt.Sprintf("contributoor-%s", cfg.Version))
 	releaseBinaryPath := filepath.Join(releaseDir, "sentry")
 
-	// Download and verify checksums.
+	// Download checksums.
 	checksumURL := fmt.Sprintf(
 		"https://github.com/%s/%s/releases/download/v%s/contributoor_%s_checksums.txt",
 		s.installerCfg.GithubOrg,
@@ -314,33 +552,28 @@ func (s *binarySidecar) updateSidecar() error {
 		cfg.Version,
 	)
 
-	//nolint:gosec // controlled url.
-	resp, err := http.Get(checksumURL)
-	if err != nil || resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to download checksums: %w", err)
+	checksums, err := downloadChecksums(ctx, checksumURL)
+	if err != nil {
+		return err
 	}
 
-	defer resp.Body.Close()
-
 	// Determine platform and arch.
 	var (
-		platform = runtime.GOOS
-		arch     = runtime.GOARCH
+		platform    = runtime.GOOS
+		arch        = runtime.GOARCH
+		archiveName = fmt.Sprintf("contributoor_%s_%s_%s.tar.gz", cfg.Version, platform, arch)
 	)
 
 	binaryURL := fmt.Sprintf(
-		"https://github.com/%s/%s/releases/download/v%s/contributoor_%s_%s_%s.tar.gz",
+		"https://github.com/%s/%s/releases/download/v%s/%s",
 		s.installerCfg.GithubOrg,
 		s.installerCfg.GithubContributoorRepo,
 		cfg.Version,
-		cfg.Version,
-		platform,
-		arch,
+		archiveName,
 	)
 
-	//nolint:gosec // controlled url.
-	resp, err = http.Get(binaryURL)
-	if err != nil || resp.StatusCode != http.StatusOK {
+	resp, err := getReleaseAsset(ctx, binaryURL)
+	if err != nil {
 		return fmt.Errorf("failed to download binary: %w", err)
 	}
 
@@ -351,21 +584,34 @@ func (s *binarySidecar) updateSidecar() error {
 	if err != nil {
 		return fmt.Errorf("failed to create temp file: %w", err)
 	}
+
 	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
 
-	// Copy download to temp file.
-	if _, ioerr := io.Copy(tmpFile, resp.Body); ioerr != nil {
+	// Copy download to temp file, hashing it as we go.
+	w, digest := hashingWriter(tmpFile)
+	if _, err := io.Copy(w, resp.Body); err != nil {
 		return fmt.Errorf("failed to write binary to temp file: %w", err)
 	}
 
+	// Refuse to go any further if the archive doesn't match the published checksum.
+	if err := verifyChecksum(checksums, archiveName, digest()); err != nil {
+		return fmt.Errorf("failed to verify binary: %w", err)
+	}
+
+	// Last chance to bail out before we start touching the installation.
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("update cancelled: %w", err)
+	}
+
 	// Stop service if running.
-	running, err := s.IsRunning()
+	running, err := s.IsRunning(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to check if service is running: %w", err)
 	}
 
 	if running {
-		if err := s.Stop(); err != nil {
+		if err := s.Stop(ctx); err != nil {
 			return fmt.Errorf("failed to stop service: %w", err)
 		}
 	}
@@ -376,7 +622,7 @@ func (s *binarySidecar) updateSidecar() error {
 	}
 
 	// Extract binary to release directory.
-	cmd := exec.Command("tar", "--no-same-owner", "-xzf", tmpFile.Name(), "-C", releaseDir) //nolint:gosec // controlled extraction.
+	cmd := exec.CommandContext(ctx, "tar", "--no-same-owner", "-xzf", tmpFile.Name(), "-C", releaseDir) //nolint:gosec // controlled extraction.
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to extract binary: %w", err)
 	}
@@ -387,17 +633,13 @@ func (s *binarySidecar) updateSidecar() error {
 	}
 
 	// Update symlink.
-	if err := os.Remove(symlinkPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove old symlink: %w", err)
-	}
-
-	if err := os.Symlink(releaseBinaryPath, symlinkPath); err != nil {
-		return fmt.Errorf("failed to create symlink: %w", err)
+	if err := swapSymlink(releaseBinaryPath, symlinkPath); err != nil {
+		return err
 	}
 
 	// Restart if it was running.
 	if running {
-		if err := s.Start(); err != nil {
+		if err := s.Start(ctx); err != nil {
 			return fmt.Errorf("failed to restart service: %w", err)
 		}
 	}
@@ -424,8 +666,8 @@ func (s *binarySidecar) checkBinaryExists() error {
 }
 
 // checkBinaryVersion checks if the binary version matches the config version.
-func (s *binarySidecar) checkBinaryVersion() error {
-	version, err := s.getBinaryVersion()
+func (s *binarySidecar) checkBinaryVersion(ctx context.Context) error {
+	version, err := s.getBinaryVersion(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to check binary version: %w", err)
 	}
@@ -440,7 +682,7 @@ func (s *binarySidecar) checkBinaryVersion() error {
 			tui.TerminalColorReset,
 		)
 
-		if err := s.Update(); err != nil {
+		if err := s.Update(ctx); err != nil {
 			return fmt.Errorf("failed to auto-update binary: %w", err)
 		}
 	}
@@ -449,11 +691,11 @@ func (s *binarySidecar) checkBinaryVersion() error {
 }
 
 // getBinaryVersion gets the version of the binary by running it with --release flag.
-func (s *binarySidecar) getBinaryVersion() (string, error) {
+func (s *binarySidecar) getBinaryVersion(ctx context.Context) (string, error) {
 	var (
 		cfg        = s.sidecarCfg.Get()
 		binaryPath = filepath.Join(cfg.ContributoorDirectory, "bin", "sentry")
-		cmd        = exec.Command(binaryPath, "--release")
+		cmd        = exec.CommandContext(ctx, binaryPath, "--release")
 	)
 
 	output, err := cmd.Output()
@@ -0,0 +1,226 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/logrotate"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/mitchellh/go-homedir"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinarySidecar_UpdateSidecar(t *testing.T) {
+	const version = "1.2.3"
+
+	var (
+		archive     = createMockTarGz(t, "sentry", []byte("genuine-sentry-binary"))
+		tampered    = createMockTarGz(t, "sentry", []byte("malicious-sentry-binary"))
+		archiveName = fmt.Sprintf("contributoor_%s_%s_%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+		basePath    = fmt.Sprintf("/ethpandaops/contributoor/releases/download/v%s/", version)
+	)
+
+	tests := []struct {
+		name          string
+		checksums     string
+		serveArchive  []byte
+		expectedError string
+	}{
+		{
+			name:         "valid checksum",
+			checksums:    fmt.Sprintf("%s  %s\n", sha256Hex(archive), archiveName),
+			serveArchive: archive,
+		},
+		{
+			name:          "tampered archive",
+			checksums:     fmt.Sprintf("%s  %s\n", sha256Hex(archive), archiveName),
+			serveArchive:  tampered,
+			expectedError: "checksum mismatch",
+		},
+		{
+			name:          "missing checksum entry",
+			checksums:     fmt.Sprintf("%s  contributoor_%s_plan9_mips.tar.gz\n", sha256Hex(archive), version),
+			serveArchive:  archive,
+			expectedError: "checksum not found",
+		},
+		{
+			name:          "checksums unavailable",
+			serveArchive:  archive,
+			expectedError: "failed to download checksums",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case basePath + fmt.Sprintf("contributoor_%s_checksums.txt", version):
+					if tt.checksums == "" {
+						w.WriteHeader(http.StatusNotFound)
+
+						return
+					}
+
+					_, _ = w.Write([]byte(tt.checksums))
+				case basePath + archiveName:
+					_, _ = w.Write(tt.serveArchive)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			useReleaseServer(t, server.URL)
+
+			tmpDir := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "bin"), 0755))
+
+			sidecarCfg := &configService{
+				logger:     logrus.New(),
+				configPath: filepath.Join(tmpDir, "config.yaml"),
+				config: &config.Config{
+					Version:               version,
+					ContributoorDirectory: tmpDir,
+					RunMethod:             config.RunMethod_RUN_METHOD_BINARY,
+				},
+			}
+
+			s := &binarySidecar{
+				logger:     logrus.New(),
+				sidecarCfg: sidecarCfg,
+				installerCfg: &installer.Config{
+					GithubOrg:              "ethpandaops",
+					GithubContributoorRepo: "contributoor",
+				},
+			}
+
+			var (
+				err         = s.updateSidecar(context.Background())
+				symlinkPath = filepath.Join(tmpDir, "bin", "sentry")
+				releaseDir  = filepath.Join(tmpDir, "releases", "contributoor-"+version)
+			)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				// Nothing should have been extracted or symlinked.
+				assert.NoDirExists(t, releaseDir)
+
+				_, lerr := os.Lstat(symlinkPath)
+				assert.True(t, os.IsNotExist(lerr))
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			target, err := os.Readlink(symlinkPath)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(target, releaseDir))
+
+			data, err := os.ReadFile(symlinkPath)
+			require.NoError(t, err)
+			assert.Equal(t, "genuine-sentry-binary", string(data))
+		})
+	}
+}
+
+func TestBinarySidecar_UninstallSteps(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	homedir.DisableCache = true
+	defer func() { homedir.DisableCache = false }()
+
+	dir := filepath.Join(home, ".contributoor")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	for _, name := range []string{"contributoor.pid", supervisorStateFile} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("1"), 0600))
+	}
+
+	s := &binarySidecar{
+		logger: logrus.New(),
+		sidecarCfg: &configService{
+			logger: logrus.New(),
+			config: &config.Config{ContributoorDirectory: "~/.contributoor"},
+		},
+	}
+
+	steps := s.UninstallSteps()
+	require.Len(t, steps, 2)
+	assert.Contains(t, steps[0].Description, filepath.Join(dir, "contributoor.pid"))
+
+	for _, step := range steps {
+		require.NoError(t, step.Run(context.Background()))
+	}
+
+	assert.NoFileExists(t, filepath.Join(dir, "contributoor.pid"))
+	assert.NoFileExists(t, filepath.Join(dir, supervisorStateFile))
+}
+
+func TestLogOptions(t *testing.T) {
+	var (
+		hour = time.Hour
+		zero = 0
+		neg  = -1
+	)
+
+	tests := []struct {
+		name          string
+		cfg           installer.LogsConfig
+		expected      logrotate.Options
+		expectedError string
+	}{
+		{
+			name:     "defaults",
+			cfg:      installer.NewConfig().Logs,
+			expected: logrotate.Options{MaxSize: 100 * 1024 * 1024, MaxAge: 24 * time.Hour, MaxFiles: 7},
+		},
+		{
+			name:     "rotation disabled",
+			cfg:      installer.LogsConfig{MaxSize: "0", MaxAge: new(time.Duration), MaxFiles: &zero},
+			expected: logrotate.Options{},
+		},
+		{
+			name:     "custom",
+			cfg:      installer.LogsConfig{MaxSize: "10m", MaxAge: &hour},
+			expected: logrotate.Options{MaxSize: 10 * 1024 * 1024, MaxAge: time.Hour},
+		},
+		{
+			name:          "invalid size",
+			cfg:           installer.LogsConfig{MaxSize: "lots"},
+			expectedError: "invalid logs.maxSize",
+		},
+		{
+			name:          "negative max files",
+			cfg:           installer.LogsConfig{MaxFiles: &neg},
+			expectedError: "invalid logs.maxFiles",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := logOptions(tt.cfg)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, opts)
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package sidecar
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ComposeFilename is the name of the compose file rendered into the config directory.
+	ComposeFilename = "docker-compose.yml"
+	// ComposeOverrideFilename is the name of an optional, user maintained compose file
+	// in the config directory which is applied on top of the rendered one.
+	ComposeOverrideFilename = "docker-compose.override.yml"
+	// composeServiceName is the name of the sidecar service in the compose project.
+	composeServiceName = "sentry"
+	// composeNetworkName is the name the external docker network is known by in the project.
+	composeNetworkName = "contributoor"
+)
+
+const composeHeader = `# Generated by contributoor from config.yaml and installer.yaml, do not edit.
+# Changes are overwritten on every start. Put customisations in ` + ComposeOverrideFilename + `.
+`
+
+// composeFile is the subset of the compose specification we render.
+type composeFile struct {
+	Name     string                    `yaml:"name"`
+	Services map[string]composeService `yaml:"services"`
+	Networks map[string]composeNetwork `yaml:"networks,omitempty"`
+}
+
+type composeService struct {
+	ContainerName string            `yaml:"container_name"` //nolint:tagliatelle // compose spec.
+	Image         string            `yaml:"image"`
+	Entrypoint    []string          `yaml:"entrypoint"`
+	Command       []string          `yaml:"command"`
+	Restart       string            `yaml:"restart,omitempty"`
+	Environment   map[string]string `yaml:"environment,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	ExtraHosts    []string          `yaml:"extra_hosts"` //nolint:tagliatelle // compose spec.
+	Volumes       []string          `yaml:"volumes"`
+	Ports         []string          `yaml:"ports,omitempty"`
+	Networks      []string          `yaml:"networks,omitempty"`
+	Logging       *composeLogging   `yaml:"logging,omitempty"`
+	Deploy        *composeDeploy    `yaml:"deploy,omitempty"`
+}
+
+type composeLogging struct {
+	Driver  string            `yaml:"driver"`
+	Options map[string]string `yaml:"options,omitempty"`
+}
+
+type composeDeploy struct {
+	Resources composeResources `yaml:"resources"`
+}
+
+type composeResources struct {
+	Limits composeLimits `yaml:"limits"`
+}
+
+type composeLimits struct {
+	CPUs   string `yaml:"cpus,omitempty"`
+	Memory string `yaml:"memory,omitempty"`
+}
+
+type composeNetwork struct {
+	Name     string `yaml:"name"`
+	External bool   `yaml:"external"`
+}
+
+// RenderCompose renders the compose project for the sidecar config and installer
+// settings, using the container runtime configured in the installer settings.
+func RenderCompose(sidecarCfg ConfigManager, installerCfg *installer.Config) ([]byte, error) {
+	runtime, err := NewContainerRuntime(installerCfg.ContainerRuntime)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		name  = ServiceName(installerCfg.Instance)
+		image = fmt.Sprintf("%s:%s", runtime.QualifyImage(installerCfg.DockerImage), sidecarCfg.Get().Version)
+	)
+
+	return renderCompose(name, sidecarCfg.Get(), &installerCfg.Compose, image, filepath.Dir(sidecarCfg.GetConfigPath()))
+}
+
+// renderCompose renders the compose project called name running image, with the
+// sidecar config mounted from configDir. The container is given the same name.
+func renderCompose(
+	name string,
+	cfg *config.Config,
+	settings *installer.ComposeConfig,
+	image, configDir string,
+) ([]byte, error) {
+	if err := validateComposeConfig(settings); err != nil {
+		return nil, err
+	}
+
+	service := composeService{
+		ContainerName: name,
+		Image:         escapeInterpolation(image),
+		Entrypoint:    []string{"/usr/local/bin/sentry"},
+		Command:       []string{"--config=/config/config.yaml"},
+		Restart:       settings.Restart,
+		Environment:   escapeInterpolationMap(settings.Environment),
+		Labels:        escapeInterpolationMap(settings.Labels),
+		ExtraHosts:    []string{"host.docker.internal:host-gateway"},
+		Volumes: []string{
+			escapeInterpolation(filepath.Join(configDir, "config.yaml")) + ":/config/config.yaml:ro",
+		},
+	}
+
+	// Only publish the metrics and health check ports when they're enabled.
+	if host, port := cfg.GetMetricsHostPort(); host != "" {
+		service.Ports = append(service.Ports, publishPort(host, port))
+	}
+
+	if host, port := cfg.GetHealthCheckHostPort(); host != "" {
+		service.Ports = append(service.Ports, publishPort(host, port))
+	}
+
+	if settings.LogDriver != "" {
+		service.Logging = &composeLogging{Driver: settings.LogDriver, Options: escapeInterpolationMap(settings.LogOptions)}
+	}
+
+	limits := composeLimits{CPUs: limit(settings.CPUs), Memory: limit(settings.Memory)}
+	if limits != (composeLimits{}) {
+		service.Deploy = &composeDeploy{Resources: composeResources{Limits: limits}}
+	}
+
+	project := composeFile{
+		Name:     name,
+		Services: map[string]composeService{composeServiceName: service},
+	}
+
+	if cfg.DockerNetwork != "" {
+		service.Networks = []string{composeNetworkName}
+		project.Services[composeServiceName] = service
+		project.Networks = map[string]composeNetwork{
+			composeNetworkName: {Name: escapeInterpolation(cfg.DockerNetwork), External: true},
+		}
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(composeHeader)
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(project); err != nil {
+		return nil, fmt.Errorf("failed to render compose file: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to render compose file: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeCompose writes a rendered compose file to path, replacing it atomically.
+func writeCompose(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create compose directory: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write compose file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+
+		return fmt.Errorf("failed to write compose file: %w", err)
+	}
+
+	return nil
+}
+
+// validateComposeConfig checks the compose settings before they're rendered, so
+// mistakes are reported against installer.yaml rather than by compose.
+func validateComposeConfig(settings *installer.ComposeConfig) error {
+	if settings.CPUs != "" {
+		if cpus, err := strconv.ParseFloat(settings.CPUs, 64); err != nil || cpus < 0 {
+			return fmt.Errorf("invalid compose cpus %q, must be a positive number", settings.CPUs)
+		}
+	}
+
+	if settings.Memory != "" {
+		if _, err := units.RAMInBytes(settings.Memory); err != nil {
+			return fmt.Errorf("invalid compose memory %q, must be a size like 512M or 1G", settings.Memory)
+		}
+	}
+
+	policy, retries, hasRetries := strings.Cut(settings.Restart, ":")
+
+	switch policy {
+	case "", "no", "always", "unless-stopped":
+		if hasRetries {
+			return fmt.Errorf("invalid compose restart %q, only on-failure takes a retry count", settings.Restart)
+		}
+	case "on-failure":
+		if n, err := strconv.Atoi(retries); hasRetries && (err != nil || n < 0) {
+			return fmt.Errorf("invalid compose restart %q, retry count must be a positive integer", settings.Restart)
+		}
+	default:
+		return fmt.Errorf(
+			"invalid compose restart %q, must be one of: no, always, unless-stopped, on-failure[:retries]",
+			settings.Restart,
+		)
+	}
+
+	for key := range settings.Environment {
+		if key == "" || strings.ContainsAny(key, "= ") {
+			return fmt.Errorf("invalid compose environment variable name %q", key)
+		}
+	}
+
+	return nil
+}
+
+// publishPort returns a compose port mapping publishing port on host.
+func publishPort(host, port string) string {
+	return fmt.Sprintf("%s:%s", net.JoinHostPort(host, port), port)
+}
+
+// limit returns value as a compose resource limit, where "0" means no limit.
+func limit(value string) string {
+	if n, err := strconv.ParseFloat(value, 64); err == nil && n == 0 {
+		return ""
+	}
+
+	return value
+}
+
+// escapeInterpolation escapes value so compose doesn't treat $ as a variable.
+func escapeInterpolation(value string) string {
+	return strings.ReplaceAll(value, "$", "$$")
+}
+
+func escapeInterpolationMap(values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	escaped := make(map[string]string, len(values))
+	for key, value := range values {
+		escaped[key] = escapeInterpolation(value)
+	}
+
+	return escaped
+}
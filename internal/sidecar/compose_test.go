@@ -0,0 +1,213 @@
+package sidecar
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCompose(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		data, err := renderCompose(
+			"contributoor",
+			&config.Config{Version: "1.0.0"},
+			&installer.NewConfig().Compose,
+			"ethpandaops/contributoor:1.0.0",
+			"/home/user/.contributoor",
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, composeHeader+`name: contributoor
+services:
+  sentry:
+    container_name: contributoor
+    image: ethpandaops/contributoor:1.0.0
+    entrypoint:
+      - /usr/local/bin/sentry
+    command:
+      - --config=/config/config.yaml
+    restart: always
+    extra_hosts:
+      - host.docker.internal:host-gateway
+    volumes:
+      - /home/user/.contributoor/config.yaml:/config/config.yaml:ro
+    deploy:
+      resources:
+        limits:
+          cpus: "0.5"
+          memory: 1024M
+`, string(data))
+	})
+
+	t.Run("all settings", func(t *testing.T) {
+		data, err := renderCompose(
+			"contributoor",
+			&config.Config{
+				Version:            "1.0.0",
+				MetricsAddress:     "0.0.0.0:9090",
+				HealthCheckAddress: ":9191",
+				DockerNetwork:      "rocketpool_net",
+			},
+			&installer.ComposeConfig{
+				CPUs:        "2",
+				Memory:      "0",
+				Restart:     "no",
+				Environment: map[string]string{"GOMAXPROCS": "2", "SECRET": "pa$word"},
+				Labels:      map[string]string{"com.example.team": "ops"},
+				LogDriver:   "json-file",
+				LogOptions:  map[string]string{"max-size": "10m"},
+			},
+			"docker.io/ethpandaops/contributoor:1.0.0",
+			"/srv/contributoor",
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, composeHeader+`name: contributoor
+services:
+  sentry:
+    container_name: contributoor
+    image: docker.io/ethpandaops/contributoor:1.0.0
+    entrypoint:
+      - /usr/local/bin/sentry
+    command:
+      - --config=/config/config.yaml
+    restart: "no"
+    environment:
+      GOMAXPROCS: "2"
+      SECRET: pa$$word
+    labels:
+      com.example.team: ops
+    extra_hosts:
+      - host.docker.internal:host-gateway
+    volumes:
+      - /srv/contributoor/config.yaml:/config/config.yaml:ro
+    ports:
+      - 0.0.0.0:9090:9090
+      - 127.0.0.1:9191:9191
+    networks:
+      - contributoor
+    logging:
+      driver: json-file
+      options:
+        max-size: 10m
+    deploy:
+      resources:
+        limits:
+          cpus: "2"
+networks:
+  contributoor:
+    name: rocketpool_net
+    external: true
+`, string(data))
+	})
+
+	t.Run("named instance", func(t *testing.T) {
+		data, err := renderCompose(
+			ServiceName("holesky"),
+			&config.Config{Version: "1.0.0"},
+			&installer.NewConfig().Compose,
+			"ethpandaops/contributoor:1.0.0",
+			"/home/user/.contributoor/instances/holesky",
+		)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "name: contributoor-holesky\n")
+		assert.Contains(t, string(data), "container_name: contributoor-holesky\n")
+		assert.Contains(t, string(data), "/home/user/.contributoor/instances/holesky/config.yaml:/config/config.yaml:ro")
+	})
+
+	t.Run("no limits", func(t *testing.T) {
+		data, err := renderCompose(
+			"contributoor",
+			&config.Config{Version: "1.0.0"},
+			&installer.ComposeConfig{CPUs: "0", Memory: "0", Restart: "always"},
+			"ethpandaops/contributoor:1.0.0",
+			"/srv/contributoor",
+		)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "deploy:")
+	})
+}
+
+func TestValidateComposeConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		settings      installer.ComposeConfig
+		expectedError string
+	}{
+		{
+			name:     "defaults",
+			settings: installer.NewConfig().Compose,
+		},
+		{
+			name:     "on-failure with retries",
+			settings: installer.ComposeConfig{Restart: "on-failure:5", Memory: "1.5g", CPUs: "0.25"},
+		},
+		{
+			name:          "invalid cpus",
+			settings:      installer.ComposeConfig{CPUs: "half"},
+			expectedError: `invalid compose cpus "half"`,
+		},
+		{
+			name:          "negative cpus",
+			settings:      installer.ComposeConfig{CPUs: "-1"},
+			expectedError: `invalid compose cpus "-1"`,
+		},
+		{
+			name:          "invalid memory",
+			settings:      installer.ComposeConfig{Memory: "lots"},
+			expectedError: `invalid compose memory "lots"`,
+		},
+		{
+			name:          "invalid restart",
+			settings:      installer.ComposeConfig{Restart: "sometimes"},
+			expectedError: `invalid compose restart "sometimes"`,
+		},
+		{
+			name:          "retries on always",
+			settings:      installer.ComposeConfig{Restart: "always:3"},
+			expectedError: "only on-failure takes a retry count",
+		},
+		{
+			name:          "invalid retries",
+			settings:      installer.ComposeConfig{Restart: "on-failure:many"},
+			expectedError: "retry count must be a positive integer",
+		},
+		{
+			name:          "invalid environment name",
+			settings:      installer.ComposeConfig{Environment: map[string]string{"A=B": "c"}},
+			expectedError: `invalid compose environment variable name "A=B"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateComposeConfig(&tt.settings)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWriteCompose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", ComposeFilename)
+
+	require.NoError(t, writeCompose(path, []byte("first")))
+	require.NoError(t, writeCompose(path, []byte("second")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -1,11 +1,12 @@
 package sidecar
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
+	"time"
 
 	"github.com/ethpandaops/contributoor-installer/internal/installer"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
@@ -17,95 +18,67 @@ import (
 
 type DockerSidecar interface {
 	SidecarRunner
-	GetComposeEnv() []string
+	// RenderCompose renders the compose project for the current config.
+	RenderCompose() ([]byte, error)
 }
 
 // dockerSidecar is a basic service for interacting with the docker container.
 type dockerSidecar struct {
-	logger             *logrus.Logger
-	composePath        string
-	composeMetricsPath string
-	composeHealthPath  string
-	composeNetworkPath string
-	configPath         string
-	sidecarCfg         ConfigManager
-	installerCfg       *installer.Config
+	logger       *logrus.Logger
+	runtime      *ContainerRuntime
+	name         string
+	composePath  string
+	configPath   string
+	sidecarCfg   ConfigManager
+	installerCfg *installer.Config
+}
+
+func init() {
+	RegisterRunner(config.RunMethod_RUN_METHOD_DOCKER, func(logger *logrus.Logger, sidecarCfg ConfigManager, installerCfg *installer.Config) (SidecarRunner, error) {
+		return NewDockerSidecar(logger, sidecarCfg, installerCfg)
+	})
 }
 
 // NewDockerSidecar creates a new DockerSidecar.
 func NewDockerSidecar(logger *logrus.Logger, sidecarCfg ConfigManager, installerCfg *installer.Config) (DockerSidecar, error) {
-	var (
-		composeFilename        = "docker-compose.yml"
-		composeMetricsFilename = "docker-compose.metrics.yml"
-		composeHealthFilename  = "docker-compose.health.yml"
-		composeNetworkFilename = "docker-compose.network.yml"
-	)
-
-	composePath, err := findComposeFile(composeFilename)
+	runtime, err := NewContainerRuntime(installerCfg.ContainerRuntime)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find %s: %w", composeFilename, err)
+		return nil, err
 	}
 
-	composeMetricsPath, err := findComposeFile(composeMetricsFilename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find %s: %w", composeMetricsFilename, err)
-	}
+	configPath := sidecarCfg.GetConfigPath()
 
-	composeHealthPath, err := findComposeFile(composeHealthFilename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find %s: %w", composeHealthFilename, err)
-	}
+	return &dockerSidecar{
+		logger:       logger,
+		runtime:      runtime,
+		name:         ServiceName(installerCfg.Instance),
+		composePath:  filepath.Join(filepath.Dir(configPath), ComposeFilename),
+		configPath:   configPath,
+		sidecarCfg:   sidecarCfg,
+		installerCfg: installerCfg,
+	}, nil
+}
 
-	composeNetworkPath, err := findComposeFile(composeNetworkFilename)
+// Start starts the docker container using docker-compose.
+func (s *dockerSidecar) Start(ctx context.Context) error {
+	engine, err := s.openEngine()
 	if err != nil {
-		return nil, fmt.Errorf("failed to find %s: %w", composeNetworkFilename, err)
-	}
-
-	if err := validateComposePath(composePath); err != nil {
-		return nil, fmt.Errorf("invalid %s file: %w", composeFilename, err)
+		return err
 	}
 
-	if err := validateComposePath(composeMetricsPath); err != nil {
-		return nil, fmt.Errorf("invalid %s file: %w", composeMetricsPath, err)
-	}
-
-	if err := validateComposePath(composeHealthPath); err != nil {
-		return nil, fmt.Errorf("invalid %s file: %w", composeHealthPath, err)
-	}
+	defer engine.Close()
 
-	if err := validateComposePath(composeNetworkPath); err != nil {
-		return nil, fmt.Errorf("invalid %s file: %w", composeNetworkFilename, err)
+	// Remove any existing container first, it may not belong to our compose project.
+	if err := engine.removeContainer(ctx, s.name); err != nil {
+		return fmt.Errorf("failed to remove existing container: %w", err)
 	}
 
-	return &dockerSidecar{
-		logger:             logger,
-		composePath:        filepath.Clean(composePath),
-		composeMetricsPath: filepath.Clean(composeMetricsPath),
-		composeNetworkPath: filepath.Clean(composeNetworkPath),
-		composeHealthPath:  filepath.Clean(composeHealthPath),
-		configPath:         sidecarCfg.GetConfigPath(),
-		sidecarCfg:         sidecarCfg,
-		installerCfg:       installerCfg,
-	}, nil
-}
-
-// Start starts the docker container using docker-compose.
-func (s *dockerSidecar) Start() error {
-	// Check if container exists and remove it first, if it does.
-	cmd := exec.Command("docker", "ps", "-aq", "-f", "name=contributoor")
-
-	output, err := cmd.Output()
-	if err == nil && len(strings.TrimSpace(string(output))) > 0 {
-		removeCmd := exec.Command("docker", "rm", "-f", "contributoor")
-		if output, err := removeCmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("failed to remove existing container: %w\nOutput: %s", err, string(output))
-		}
+	composeArgs, err := s.getComposeArgs()
+	if err != nil {
+		return err
 	}
 
-	args := append(s.getComposeArgs(), "up", "-d")
-
-	cmd = exec.Command("docker", args...)
-	cmd.Env = s.GetComposeEnv()
+	cmd := s.runtime.ComposeCommand(ctx, append(composeArgs, "up", "-d")...)
 
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to start containers: %w\nOutput: %s", err, string(output))
@@ -117,28 +90,35 @@ func (s *dockerSidecar) Start() error {
 }
 
 // Stop stops and removes the docker container using docker-compose.
-func (s *dockerSidecar) Stop() error {
+func (s *dockerSidecar) Stop(ctx context.Context) error {
 	// First try to stop via compose. If there has been any sort of configuration change
 	// between versions, then this will not stop the container.
-	args := append(s.getComposeArgs(), "down",
-		"--remove-orphans",
-		"-v",
-		"--rmi", "local",
-		"--timeout", "30")
+	composeArgs, err := s.getComposeArgs()
+	if err != nil {
+		return err
+	}
 
-	cmd := exec.Command("docker", args...)
-	cmd.Env = s.GetComposeEnv()
+	args := append(composeArgs, "down", "--remove-orphans", "-v", "--timeout", "30")
+	if s.runtime.composeDownSupportsRmi() {
+		args = append(args, "--rmi", "local")
+	}
 
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := s.runtime.ComposeCommand(ctx, args...).CombinedOutput(); err != nil {
 		// Don't return error here, try our fallback.
 		s.logger.Debugf("failed to stop via compose: %v\noutput: %s", err, string(output))
 	}
 
 	// Fallback in the case of a configuration change between versions, attempt to remove
 	// the container by name.
-	cmd = exec.Command("docker", "rm", "-f", "contributoor")
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to stop container: %w\nOutput: %s", err, string(output))
+	engine, err := s.openEngine()
+	if err != nil {
+		return err
+	}
+
+	defer engine.Close()
+
+	if err := engine.removeContainer(ctx, s.name); err != nil {
+		return fmt.Errorf("failed to stop container: %w", err)
 	}
 
 	fmt.Printf("%sContributoor stopped successfully%s\n", tui.TerminalColorGreen, tui.TerminalColorReset)
@@ -146,52 +126,42 @@ func (s *dockerSidecar) Stop() error {
 	return nil
 }
 
-// Status returns the current state of the docker container.
-func (s *dockerSidecar) Status() (string, error) {
-	// First check if container exists.
-	cmd := exec.Command("docker", "ps", "-a", "--filter", "name=contributoor", "--format", "{{.ID}}")
+// Status returns the current state of the container.
+func (s *dockerSidecar) Status(ctx context.Context) (*Status, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
 
-	output, err := cmd.Output()
-	if err != nil || len(strings.TrimSpace(string(output))) == 0 {
-		//nolint:nilerr // We don't care about the error here.
-		return "not running", nil
-	}
-
-	// Container exists, get its status.
-	cmd = exec.Command("docker", "inspect", "-f", "{{.State.Status}}", "contributoor")
-
-	output, err = cmd.Output()
+	engine, err := s.openEngine()
 	if err != nil {
-		return "", fmt.Errorf("failed to get container status: %w", err)
+		return nil, err
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	defer engine.Close()
+
+	return engine.containerStatus(ctx, s.name, time.Now())
 }
 
 // IsRunning checks if the docker container is running.
-func (s *dockerSidecar) IsRunning() (bool, error) {
-	cmd := exec.Command("docker", "inspect", "-f", "{{.State.Status}}", "contributoor")
-
-	output, err := cmd.Output()
+func (s *dockerSidecar) IsRunning(ctx context.Context) (bool, error) {
+	status, err := s.Status(ctx)
 	if err != nil {
-		//nolint:nilerr // We don't care about the error here.
-		return false, nil
+		return false, err
 	}
 
-	return strings.TrimSpace(string(output)) == "running", nil
+	return status.IsRunning(), nil
 }
 
 // Update pulls the latest image and restarts the container.
-func (s *dockerSidecar) Update() error {
+func (s *dockerSidecar) Update(ctx context.Context) error {
 	cfg := s.sidecarCfg.Get()
 
 	// Update installer first.
-	if err := updateInstaller(cfg, s.installerCfg); err != nil {
+	if err := updateInstaller(ctx, cfg, s.installerCfg); err != nil {
 		return fmt.Errorf("failed to update installer: %w", err)
 	}
 
 	// Update sidecar.
-	if err := s.updateSidecar(); err != nil {
+	if err := s.updateSidecar(ctx); err != nil {
 		return fmt.Errorf("failed to update sidecar: %w", err)
 	}
 
@@ -199,15 +169,21 @@ func (s *dockerSidecar) Update() error {
 }
 
 // updateSidecar updates the docker image to the specified version.
-func (s *dockerSidecar) updateSidecar() error {
+func (s *dockerSidecar) updateSidecar(ctx context.Context) error {
 	var (
 		cfg   = s.sidecarCfg.Get()
-		image = fmt.Sprintf("%s:%s", s.installerCfg.DockerImage, cfg.Version)
+		image = s.image(cfg.Version)
 	)
 
-	cmd := exec.Command("docker", "pull", image)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to pull image %s: %w\nOutput: %s", image, err, string(output))
+	engine, err := s.openEngine()
+	if err != nil {
+		return err
+	}
+
+	defer engine.Close()
+
+	if err := engine.pullImage(ctx, image); err != nil {
+		return err
 	}
 
 	fmt.Printf(
@@ -220,186 +196,161 @@ func (s *dockerSidecar) updateSidecar() error {
 	return nil
 }
 
-func validateComposePath(path string) error {
-	// Check if path exists and is a regular file
-	fi, err := os.Stat(path)
-	if err != nil {
-		return fmt.Errorf("invalid compose file path: %w", err)
-	}
-
-	if fi.IsDir() {
-		return fmt.Errorf("compose path is a directory, not a file")
-	}
-
-	// Ensure absolute path
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
-	}
-
-	// Verify file extension
-	if !strings.HasSuffix(strings.ToLower(absPath), ".yml") &&
-		!strings.HasSuffix(strings.ToLower(absPath), ".yaml") {
-		return fmt.Errorf("compose file must have .yml or .yaml extension")
-	}
-
-	return nil
+// Provision pulls the sidecar image for the configured version.
+func (s *dockerSidecar) Provision(ctx context.Context) error {
+	return s.updateSidecar(ctx)
 }
 
-// GetComposeEnv returns the environment variables for docker-compose.
-func (s *dockerSidecar) GetComposeEnv() []string {
-	cfg := s.sidecarCfg.Get()
-
-	env := append(
-		os.Environ(),
-		fmt.Sprintf("CONTRIBUTOOR_CONFIG_PATH=%s", filepath.Dir(s.configPath)),
-		fmt.Sprintf("CONTRIBUTOOR_VERSION=%s", cfg.Version),
+// RenderCompose renders the compose project for the current config.
+func (s *dockerSidecar) RenderCompose() ([]byte, error) {
+	return renderCompose(
+		s.name,
+		s.sidecarCfg.Get(),
+		&s.installerCfg.Compose,
+		s.image(s.sidecarCfg.Get().Version),
+		filepath.Dir(s.configPath),
 	)
+}
 
-	// Add docker network if using docker
-	if cfg.RunMethod == config.RunMethod_RUN_METHOD_DOCKER && cfg.DockerNetwork != "" {
-		env = append(env, fmt.Sprintf("CONTRIBUTOOR_DOCKER_NETWORK=%s", cfg.DockerNetwork))
-	}
-
-	// Handle metrics address (only added if set).
-	if metricsHost, metricsPort := cfg.GetMetricsHostPort(); metricsHost != "" {
-		env = append(
-			env,
-			fmt.Sprintf("CONTRIBUTOOR_METRICS_ADDRESS=%s", metricsHost),
-			fmt.Sprintf("CONTRIBUTOOR_METRICS_PORT=%s", metricsPort),
-		)
-	}
-
-	// Handle health address (only added if set).
-	if healthHost, healthPort := cfg.GetHealthCheckHostPort(); healthHost != "" {
-		env = append(
-			env,
-			fmt.Sprintf("CONTRIBUTOOR_HEALTH_ADDRESS=%s", healthHost),
-			fmt.Sprintf("CONTRIBUTOOR_HEALTH_PORT=%s", healthPort),
-		)
+// Logs shows the logs from the docker container.
+func (s *dockerSidecar) Logs(ctx context.Context, opts LogOptions) error {
+	printer, err := newLogPrinter(opts)
+	if err != nil {
+		return err
 	}
 
-	// Handle pprof address (only added if set).
-	if pprofHost, pprofPort := cfg.GetPprofHostPort(); pprofHost != "" {
-		env = append(
-			env,
-			fmt.Sprintf("CONTRIBUTOOR_PPROF_ADDRESS=%s", pprofHost),
-			fmt.Sprintf("CONTRIBUTOOR_PPROF_PORT=%s", pprofPort),
-		)
+	engine, err := s.openEngine()
+	if err != nil {
+		return err
 	}
 
-	return env
-}
+	defer engine.Close()
 
-// Logs shows the logs from the docker container.
-func (s *dockerSidecar) Logs(tailLines int, follow bool) error {
-	args := append(s.getComposeArgs(), "logs")
+	out, errOut := opts.outputs()
 
-	if tailLines > 0 {
-		args = append(args, "--tail", fmt.Sprintf("%d", tailLines))
+	if !printer.rewrites() {
+		return engine.streamLogs(ctx, s.name, opts, out, errOut)
 	}
 
-	if follow {
-		args = append(args, "-f")
-	}
+	stderrPrinter, _ := newLogPrinter(opts)
 
-	cmd := exec.Command("docker", args...)
-	cmd.Env = s.GetComposeEnv()
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Dir = filepath.Dir(s.composePath)
+	var (
+		stdout = newLogWriter(out, printer)
+		stderr = newLogWriter(errOut, stderrPrinter)
+	)
+
+	err = engine.streamLogs(ctx, s.name, opts, stdout, stderr)
 
-	return cmd.Run()
+	return errors.Join(err, stdout.Flush(), stderr.Flush())
 }
 
 // Version returns the version of the currently running container or local image.
-func (s *dockerSidecar) Version() (string, error) {
-	// First try to get version from running container.
-	cmd := exec.Command("docker", "inspect", "-f", "{{index .Config.Labels \"org.opencontainers.image.version\"}}", "contributoor")
+func (s *dockerSidecar) Version(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
 
-	output, err := cmd.Output()
-	if err == nil {
-		return strings.TrimPrefix(strings.TrimSpace(string(output)), "v"), nil
+	engine, err := s.openEngine()
+	if err != nil {
+		return "", err
 	}
 
-	// If container not running, check local image version.
-	var (
-		cfg   = s.sidecarCfg.Get()
-		image = fmt.Sprintf("%s:%s", s.installerCfg.DockerImage, cfg.Version)
-	)
+	defer engine.Close()
 
-	cmd = exec.Command("docker", "inspect", "-f", "{{index .Config.Labels \"org.opencontainers.image.version\"}}", image)
+	// First try to get version from the container.
+	version, err := engine.containerVersion(ctx, s.name)
+	if !errors.Is(err, ErrContainerNotFound) {
+		return version, err
+	}
 
-	output, err = cmd.Output()
+	// If there's no container, check local image version.
+	version, err = engine.imageVersion(ctx, s.image(s.sidecarCfg.Get().Version))
 	if err != nil {
 		return "", fmt.Errorf("failed to get image version: %w", err)
 	}
 
-	return strings.TrimPrefix(strings.TrimSpace(string(output)), "v"), nil
+	return version, nil
 }
 
-// getComposeArgs returns the consistent set of compose arguments including file paths.
-func (s *dockerSidecar) getComposeArgs() []string {
-	var additionalArgs []string
-
-	if metricsHost, _ := s.sidecarCfg.Get().GetMetricsHostPort(); metricsHost != "" {
-		additionalArgs = append(additionalArgs, "-f", s.composeMetricsPath)
-	}
-
-	if healthHost, _ := s.sidecarCfg.Get().GetHealthCheckHostPort(); healthHost != "" {
-		additionalArgs = append(additionalArgs, "-f", s.composeHealthPath)
+// UninstallSteps returns the steps to remove the compose project and sidecar image.
+func (s *dockerSidecar) UninstallSteps() []UninstallStep {
+	image := s.image(s.sidecarCfg.Get().Version)
+
+	return []UninstallStep{
+		{
+			Description: fmt.Sprintf("Remove %s project (containers, volumes and networks)", s.runtime.ComposeName()),
+			Run: func(ctx context.Context) error {
+				composeArgs, err := s.getComposeArgs()
+				if err != nil {
+					return err
+				}
+
+				args := append(composeArgs, "down", "--remove-orphans", "-v", "--timeout", "30")
+
+				if output, err := s.runtime.ComposeCommand(ctx, args...).CombinedOutput(); err != nil {
+					return fmt.Errorf("failed to remove compose project: %w\nOutput: %s", err, string(output))
+				}
+
+				engine, err := s.openEngine()
+				if err != nil {
+					return err
+				}
+
+				defer engine.Close()
+
+				// Catch any container left behind by a configuration change between versions.
+				return engine.removeContainer(ctx, s.name)
+			},
+		},
+		{
+			Description: fmt.Sprintf("Remove %s image %s", s.runtime.Name(), image),
+			Run: func(ctx context.Context) error {
+				engine, err := s.openEngine()
+				if err != nil {
+					return err
+				}
+
+				defer engine.Close()
+
+				return engine.removeImage(ctx, image)
+			},
+		},
 	}
-
-	if s.sidecarCfg.Get().RunMethod == config.RunMethod_RUN_METHOD_DOCKER && s.sidecarCfg.Get().DockerNetwork != "" {
-		additionalArgs = append(additionalArgs, "-f", s.composeNetworkPath)
-	}
-
-	return append([]string{"compose", "-f", s.composePath}, additionalArgs...)
 }
 
-// findComposeFile finds the docker-compose file based on the OS.
-func findComposeFile(filename string) (string, error) {
-	// Get binary directory.
-	ex, err := os.Executable()
+// openEngine returns a client of the container engine, which the caller must close
+// so its connections aren't leaked.
+func (s *dockerSidecar) openEngine() (*dockerEngine, error) {
+	apiClient, err := s.runtime.newEngineClient()
 	if err != nil {
-		return "", fmt.Errorf("could not get executable path: %w", err)
+		return nil, err
 	}
 
-	binDir := filepath.Dir(ex)
+	return newDockerEngine(apiClient), nil
+}
 
-	// Get the actual binary path (resolve symlink).
-	actualBin, err := filepath.EvalSymlinks(ex)
+// getComposeArgs renders the compose file for the current config, and returns the
+// compose arguments selecting it along with any user override file.
+func (s *dockerSidecar) getComposeArgs() ([]string, error) {
+	data, err := s.RenderCompose()
 	if err != nil {
-		return "", fmt.Errorf("could not resolve symlink: %w", err)
+		return nil, err
 	}
 
-	releaseDir := filepath.Dir(actualBin)
-
-	// First check release directory (next to actual binary).
-	composePath := filepath.Join(releaseDir, filename)
-	if _, e := os.Stat(composePath); e == nil {
-		return composePath, nil
+	if err := writeCompose(s.composePath, data); err != nil {
+		return nil, err
 	}
 
-	// Fallback to bin directory for backward compatibility.
-	if _, statErr := os.Stat(filepath.Join(binDir, filename)); statErr == nil {
-		return filepath.Join(binDir, filename), nil
-	}
+	args := []string{"-p", s.name, "-f", s.composePath}
 
-	// Try current directory.
-	cwd, err := os.Getwd()
-	if err != nil {
-		return "", fmt.Errorf("could not get working directory: %w", err)
-	}
-
-	if _, err := os.Stat(filepath.Join(cwd, filename)); err == nil {
-		return filepath.Join(cwd, filename), nil
+	overridePath := filepath.Join(filepath.Dir(s.composePath), ComposeOverrideFilename)
+	if _, err := os.Stat(overridePath); err == nil {
+		args = append(args, "-f", overridePath)
 	}
 
-	// Try repo root
-	if _, err := os.Stat(filepath.Join(cwd, "..", "..", filename)); err == nil {
-		return filepath.Join(cwd, "..", "..", filename), nil
-	}
+	return args, nil
+}
 
-	return "", fmt.Errorf("%s not found", filename)
+// image returns the sidecar image reference for version.
+func (s *dockerSidecar) image(version string) string {
+	return fmt.Sprintf("%s:%s", s.runtime.QualifyImage(s.installerCfg.DockerImage), version)
 }
@@ -6,7 +6,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
 	"testing"
 	"time"
 
@@ -20,14 +19,17 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"go.uber.org/mock/gomock"
+	"gopkg.in/yaml.v3"
 )
 
-const composeFile = `
+// composeOverrideFile swaps the sidecar for busybox, so the rendered project can be
+// run without pulling the real image.
+const composeOverrideFile = `
 services:
   sentry:
-    container_name: contributoor
     image: busybox
-    command: ["sh", "-c", "while true; do echo 'Container is running'; sleep 0.1; done"]
+    entrypoint: ["sh", "-c"]
+    command: ["while true; do echo 'Container is running'; sleep 0.1; done"]
     healthcheck:
       test: ["CMD-SHELL", "ps aux | grep -v grep | grep 'sleep' || exit 1"]
       interval: 100ms
@@ -36,25 +38,6 @@ services:
       start_period: 100ms
 `
 
-const composePortsFile = `
-services:
-  test:
-    ports:
-      - "9090:9090"
-`
-
-const composeNetworkFile = `
-services:
-  sentry:
-    networks:
-      - contributoor
-
-networks:
-  contributoor:
-    name: ${CONTRIBUTOOR_DOCKER_NETWORK}
-    external: true
-`
-
 // TestDockerService_Integration tests the docker sidecar.
 // We use test-containers to boot an instance of docker-in-docker.
 // We can then use this to test our docker service in isolation.
@@ -86,14 +69,8 @@ func TestDockerService_Integration(t *testing.T) {
 	mockSidecarConfig.EXPECT().Get().Return(cfg).AnyTimes()
 	mockSidecarConfig.EXPECT().GetConfigPath().Return(filepath.Join(tmpDir, "config.yaml")).AnyTimes()
 
-	// Write out compose files first.
-	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docker-compose.yml"), []byte(composeFile), 0644))
-	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docker-compose.metrics.yml"), []byte(composePortsFile), 0644))
-	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docker-compose.health.yml"), []byte(composePortsFile), 0644))
-	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "docker-compose.network.yml"), []byte(composeNetworkFile), 0644))
-
-	// Change working directory to our test directory before creating DockerSidecar.
-	require.NoError(t, os.Chdir(tmpDir))
+	// Write out the override file, which is applied on top of the rendered project.
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, sidecar.ComposeOverrideFilename), []byte(composeOverrideFile), 0644))
 
 	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
 		ContainerRequest: testcontainers.ContainerRequest{
@@ -127,19 +104,20 @@ func TestDockerService_Integration(t *testing.T) {
 	containerPort, err := container.MappedPort(ctx, nat.Port(fmt.Sprintf("%d/tcp", port)))
 	require.NoError(t, err)
 
+	// Set docker host to test container. This must happen before creating the
+	// sidecar, as its engine client reads DOCKER_HOST on creation.
+	t.Setenv("DOCKER_HOST", fmt.Sprintf("tcp://localhost:%s", containerPort.Port()))
+	t.Setenv("CONTRIBUTOOR_CONFIG_PATH", tmpDir)
+
 	// Create docker service with mock config.
 	ds, err := sidecar.NewDockerSidecar(logger, mockSidecarConfig, mockInstallerConfig)
 	require.NoError(t, err)
 
-	// Set docker host to test container.
-	t.Setenv("DOCKER_HOST", fmt.Sprintf("tcp://localhost:%s", containerPort.Port()))
-	t.Setenv("CONTRIBUTOOR_CONFIG_PATH", tmpDir)
-
 	// Helper function for container health check.
 	checkContainerHealth := func(t *testing.T, ds sidecar.DockerSidecar, expectRunning bool) {
 		t.Helper()
 		// Single check if container is running - docker-compose defines a healthcheck which will cover us.
-		running, err := ds.IsRunning()
+		running, err := ds.IsRunning(ctx)
 		require.NoError(t, err)
 		require.Equal(t, expectRunning, running, "Container running state does not match expected state")
 	}
@@ -156,14 +134,14 @@ func TestDockerService_Integration(t *testing.T) {
 
 	t.Run("lifecycle_without_metrics", func(t *testing.T) {
 		// Boot up the container.
-		require.NoError(t, ds.Start())
+		require.NoError(t, ds.Start(ctx))
 		checkContainerHealth(t, ds, true)
 
 		// Verify the container is using the default network.
 		verifyContainerNetwork(t, ds, "_default")
 
 		// Cleanup
-		require.NoError(t, ds.Stop())
+		require.NoError(t, ds.Stop(ctx))
 		checkContainerHealth(t, ds, false)
 	})
 	t.Run("lifecycle_with_metrics", func(t *testing.T) {
@@ -177,14 +155,14 @@ func TestDockerService_Integration(t *testing.T) {
 		mockSidecarConfig.EXPECT().Get().Return(cfgWithMetrics).AnyTimes()
 
 		// Boot up the container.
-		require.NoError(t, ds.Start())
+		require.NoError(t, ds.Start(ctx))
 		checkContainerHealth(t, ds, true)
 
 		// Verify the container is using the default network.
 		verifyContainerNetwork(t, ds, "_default")
 
 		// Cleanup.
-		require.NoError(t, ds.Stop())
+		require.NoError(t, ds.Stop(ctx))
 		checkContainerHealth(t, ds, false)
 	})
 
@@ -200,20 +178,20 @@ func TestDockerService_Integration(t *testing.T) {
 		checkContainerHealth(t, ds, true)
 
 		// Stop should be able to handle the external container.
-		require.NoError(t, ds.Stop())
+		require.NoError(t, ds.Stop(ctx))
 
 		// Verify container is stopped.
 		checkContainerHealth(t, ds, false)
 
 		// Finally, test normal compose lifecycle works after cleaning up external container.
-		require.NoError(t, ds.Start())
+		require.NoError(t, ds.Start(ctx))
 		checkContainerHealth(t, ds, true)
 
 		// Verify the container is using the default network.
 		verifyContainerNetwork(t, ds, "_default")
 
 		// Cleanup.
-		require.NoError(t, ds.Stop())
+		require.NoError(t, ds.Stop(ctx))
 		checkContainerHealth(t, ds, false)
 	})
 
@@ -242,19 +220,19 @@ func TestDockerService_Integration(t *testing.T) {
 		require.NoError(t, err)
 
 		// Boot up the container.
-		require.NoError(t, dsCustom.Start())
+		require.NoError(t, dsCustom.Start(ctx))
 		checkContainerHealth(t, dsCustom, true)
 
 		// Verify the container is using the custom network.
 		verifyContainerNetwork(t, dsCustom, customNetwork)
 
 		// Cleanup.
-		require.NoError(t, dsCustom.Stop())
+		require.NoError(t, dsCustom.Stop(ctx))
 		checkContainerHealth(t, dsCustom, false)
 	})
 }
 
-func TestGetComposeEnv(t *testing.T) {
+func TestDockerSidecar_RenderCompose(t *testing.T) {
 	logger := logrus.New()
 
 	mockCtrl := gomock.NewController(t)
@@ -264,90 +242,82 @@ func TestGetComposeEnv(t *testing.T) {
 	tests := []struct {
 		name            string
 		config          *config.Config
-		expectedEnvVars map[string]string
+		expectedImage   string
+		expectedPorts   []any
+		expectedNetwork string
 	}{
 		{
 			name: "basic config",
 			config: &config.Config{
-				Version:               "latest",
-				ContributoorDirectory: t.TempDir(),
-				RunMethod:             config.RunMethod_RUN_METHOD_DOCKER,
-			},
-			expectedEnvVars: map[string]string{
-				"CONTRIBUTOOR_VERSION": "latest",
+				Version:   "latest",
+				RunMethod: config.RunMethod_RUN_METHOD_DOCKER,
 			},
+			expectedImage: "ethpandaops/contributoor:latest",
 		},
 		{
 			name: "with metrics",
 			config: &config.Config{
-				Version:               "v1.0.0",
-				ContributoorDirectory: t.TempDir(),
-				RunMethod:             config.RunMethod_RUN_METHOD_DOCKER,
-				MetricsAddress:        "0.0.0.0:9090",
-			},
-			expectedEnvVars: map[string]string{
-				"CONTRIBUTOOR_VERSION":         "v1.0.0",
-				"CONTRIBUTOOR_METRICS_ADDRESS": "0.0.0.0",
-				"CONTRIBUTOOR_METRICS_PORT":    "9090",
+				Version:        "v1.0.0",
+				RunMethod:      config.RunMethod_RUN_METHOD_DOCKER,
+				MetricsAddress: "0.0.0.0:9090",
 			},
+			expectedImage: "ethpandaops/contributoor:v1.0.0",
+			expectedPorts: []any{"0.0.0.0:9090:9090"},
 		},
 		{
 			name: "with docker network",
 			config: &config.Config{
-				Version:               "v1.0.0",
-				ContributoorDirectory: t.TempDir(),
-				RunMethod:             config.RunMethod_RUN_METHOD_DOCKER,
-				DockerNetwork:         "custom_network",
-			},
-			expectedEnvVars: map[string]string{
-				"CONTRIBUTOOR_VERSION":        "v1.0.0",
-				"CONTRIBUTOOR_DOCKER_NETWORK": "custom_network",
+				Version:       "v1.0.0",
+				RunMethod:     config.RunMethod_RUN_METHOD_DOCKER,
+				DockerNetwork: "custom_network",
 			},
+			expectedImage:   "ethpandaops/contributoor:v1.0.0",
+			expectedNetwork: "custom_network",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockSidecarConfig := mock.NewMockConfigManager(mockCtrl)
-			mockInstallerConfig := installer.NewConfig()
-
-			// Write out compose files first
-			require.NoError(t, os.WriteFile(filepath.Join(tt.config.ContributoorDirectory, "docker-compose.yml"), []byte(composeFile), 0644))
-			require.NoError(t, os.WriteFile(filepath.Join(tt.config.ContributoorDirectory, "docker-compose.metrics.yml"), []byte(composePortsFile), 0644))
-			require.NoError(t, os.WriteFile(filepath.Join(tt.config.ContributoorDirectory, "docker-compose.health.yml"), []byte(composePortsFile), 0644))
-			require.NoError(t, os.WriteFile(filepath.Join(tt.config.ContributoorDirectory, "docker-compose.network.yml"), []byte(composeNetworkFile), 0644))
+			var (
+				tmpDir              = t.TempDir()
+				mockSidecarConfig   = mock.NewMockConfigManager(mockCtrl)
+				mockInstallerConfig = installer.NewConfig()
+			)
 
-			// Change working directory to test directory
-			require.NoError(t, os.Chdir(tt.config.ContributoorDirectory))
+			mockInstallerConfig.ContainerRuntime = sidecar.ContainerRuntimeDocker
 
-			// Set up mock expectations before creating DockerSidecar
 			mockSidecarConfig.EXPECT().Get().Return(tt.config).AnyTimes()
-			mockSidecarConfig.EXPECT().GetConfigPath().Return(filepath.Join(tt.config.ContributoorDirectory, "config.yaml")).AnyTimes()
+			mockSidecarConfig.EXPECT().GetConfigPath().Return(filepath.Join(tmpDir, "config.yaml")).AnyTimes()
 
 			ds, err := sidecar.NewDockerSidecar(logger, mockSidecarConfig, mockInstallerConfig)
 			require.NoError(t, err)
 
-			env := ds.GetComposeEnv()
-			require.NotNil(t, env)
-
-			// Convert env slice to map for easier testing
-			envMap := make(map[string]string)
+			data, err := ds.RenderCompose()
+			require.NoError(t, err)
 
-			for _, e := range env {
-				parts := strings.SplitN(e, "=", 2)
-				if len(parts) == 2 {
-					envMap[parts[0]] = parts[1]
-				}
+			var project struct {
+				Services map[string]map[string]any `yaml:"services"`
+				Networks map[string]map[string]any `yaml:"networks"`
 			}
 
-			// Check config path is set and points to correct directory
-			configPath := envMap["CONTRIBUTOOR_CONFIG_PATH"]
-			require.Equal(t, filepath.Dir(filepath.Join(tt.config.ContributoorDirectory, "config.yaml")), configPath)
+			require.NoError(t, yaml.Unmarshal(data, &project))
+
+			service := project.Services["sentry"]
+			require.NotNil(t, service)
+			require.Equal(t, tt.expectedImage, service["image"])
+			require.Equal(t, []any{filepath.Join(tmpDir, "config.yaml") + ":/config/config.yaml:ro"}, service["volumes"])
 
-			// Check all expected env vars are present with correct values
-			for k, v := range tt.expectedEnvVars {
-				require.Equal(t, v, envMap[k], "Environment variable %s has incorrect value", k)
+			ports, _ := service["ports"].([]any)
+			require.Equal(t, tt.expectedPorts, ports)
+
+			if tt.expectedNetwork == "" {
+				require.Empty(t, project.Networks)
+
+				return
 			}
+
+			require.Equal(t, tt.expectedNetwork, project.Networks["contributoor"]["name"])
+			require.Equal(t, true, project.Networks["contributoor"]["external"])
 		})
 	}
 }
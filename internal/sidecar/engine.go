@@ -0,0 +1,293 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+var (
+	// ErrEngineUnavailable is returned when the container engine can't be reached.
+	ErrEngineUnavailable = errors.New("container engine is not reachable")
+	// ErrContainerNotFound is returned when there is no container with the given name.
+	ErrContainerNotFound = errors.New("container not found")
+	// ErrImageNotFound is returned when the image isn't present locally.
+	ErrImageNotFound = errors.New("image not found")
+)
+
+// dockerEngine drives containers and images through the Engine API, which podman
+// also serves a compatible version of.
+type dockerEngine struct {
+	client client.APIClient
+}
+
+func newDockerEngine(apiClient client.APIClient) *dockerEngine {
+	return &dockerEngine{client: apiClient}
+}
+
+// Close closes the Engine API client.
+func (e *dockerEngine) Close() error {
+	return e.client.Close()
+}
+
+// wrapError maps Engine API errors onto our typed errors.
+func (e *dockerEngine) wrapError(err error, notFound error, format string, args ...any) error {
+	switch {
+	case client.IsErrConnectionFailed(err):
+		return fmt.Errorf("%w: %w", ErrEngineUnavailable, err)
+	case notFound != nil && cerrdefs.IsNotFound(err):
+		return fmt.Errorf("%w: %s", notFound, fmt.Sprintf(format, args...))
+	default:
+		return fmt.Errorf("failed to %s: %w", fmt.Sprintf(format, args...), err)
+	}
+}
+
+// inspectContainer returns the container with exactly the given name. Unlike the
+// `name=` filter, which matches substrings, this won't match eg: contributoor-old.
+func (e *dockerEngine) inspectContainer(ctx context.Context, name string) (container.InspectResponse, error) {
+	resp, err := e.client.ContainerInspect(ctx, name)
+	if err != nil {
+		return container.InspectResponse{}, e.wrapError(err, ErrContainerNotFound, "inspect container %s", name)
+	}
+
+	// Inspect also resolves ID prefixes, so make sure we found it by name.
+	if resp.ContainerJSONBase == nil || strings.TrimPrefix(resp.Name, "/") != name {
+		return container.InspectResponse{}, fmt.Errorf("%w: %s", ErrContainerNotFound, name)
+	}
+
+	return resp, nil
+}
+
+// removeContainer force removes the container with exactly the given name, if it exists.
+func (e *dockerEngine) removeContainer(ctx context.Context, name string) error {
+	resp, err := e.inspectContainer(ctx, name)
+	if err != nil {
+		if errors.Is(err, ErrContainerNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if err := e.client.ContainerRemove(ctx, resp.ID, container.RemoveOptions{Force: true}); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil
+		}
+
+		return e.wrapError(err, nil, "remove container %s", name)
+	}
+
+	return nil
+}
+
+// containerStatus returns the status of the container with exactly the given name.
+func (e *dockerEngine) containerStatus(ctx context.Context, name string, now time.Time) (*Status, error) {
+	resp, err := e.inspectContainer(ctx, name)
+	if err != nil {
+		if errors.Is(err, ErrContainerNotFound) {
+			return &Status{State: StateStopped, Detail: "not running"}, nil
+		}
+
+		return nil, err
+	}
+
+	return containerInspectStatus(resp, now), nil
+}
+
+// containerVersion returns the version label of the container's image.
+func (e *dockerEngine) containerVersion(ctx context.Context, name string) (string, error) {
+	resp, err := e.inspectContainer(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.Config == nil {
+		return "", nil
+	}
+
+	return imageVersion(resp.Config.Labels), nil
+}
+
+// imageVersion returns the version label of a local image.
+func (e *dockerEngine) imageVersion(ctx context.Context, ref string) (string, error) {
+	resp, err := e.client.ImageInspect(ctx, ref)
+	if err != nil {
+		return "", e.wrapError(err, ErrImageNotFound, "inspect image %s", ref)
+	}
+
+	if resp.Config == nil {
+		return "", nil
+	}
+
+	return imageVersion(resp.Config.Labels), nil
+}
+
+// pullImage pulls ref, returning any error reported part way through the pull.
+func (e *dockerEngine) pullImage(ctx context.Context, ref string) error {
+	stream, err := e.client.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		return e.wrapError(err, ErrImageNotFound, "pull image %s", ref)
+	}
+
+	defer stream.Close()
+
+	// Pull errors (eg: manifest unknown) arrive as messages on an otherwise successful stream.
+	if err := jsonmessage.DisplayJSONMessagesStream(stream, io.Discard, 0, false, nil); err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+// removeImage force removes ref, if it exists.
+func (e *dockerEngine) removeImage(ctx context.Context, ref string) error {
+	if _, err := e.client.ImageRemove(ctx, ref, image.RemoveOptions{Force: true}); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil
+		}
+
+		return e.wrapError(err, nil, "remove image %s", ref)
+	}
+
+	return nil
+}
+
+// streamLogs copies the logs of the container with exactly the given name to stdout
+// and stderr, until they end or ctx is cancelled when following. Only the tail,
+// follow and time window options are applied, by the engine.
+func (e *dockerEngine) streamLogs(
+	ctx context.Context,
+	name string,
+	opts LogOptions,
+	stdout, stderr io.Writer,
+) error {
+	resp, err := e.inspectContainer(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	logsOpts := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+		Tail:       "all",
+	}
+
+	if opts.Tail > 0 {
+		logsOpts.Tail = strconv.Itoa(opts.Tail)
+	}
+
+	if !opts.Since.IsZero() {
+		logsOpts.Since = strconv.FormatInt(opts.Since.Unix(), 10)
+	}
+
+	if !opts.Until.IsZero() {
+		logsOpts.Until = strconv.FormatInt(opts.Until.Unix(), 10)
+	}
+
+	logs, err := e.client.ContainerLogs(ctx, resp.ID, logsOpts)
+	if err != nil {
+		return e.wrapError(err, ErrContainerNotFound, "get logs for container %s", name)
+	}
+
+	defer logs.Close()
+
+	// Containers with a TTY have a single raw stream, otherwise stdout and stderr
+	// are multiplexed.
+	if resp.Config != nil && resp.Config.Tty {
+		_, err = io.Copy(stdout, logs)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, logs)
+	}
+
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read logs for container %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// serverVersion returns the version of the container engine.
+func (e *dockerEngine) serverVersion(ctx context.Context) (string, error) {
+	version, err := e.client.ServerVersion(ctx)
+	if err != nil {
+		return "", e.wrapError(err, nil, "get engine version")
+	}
+
+	return version.Version, nil
+}
+
+// networkList returns the names of the engine's networks.
+func (e *dockerEngine) networkList(ctx context.Context) ([]string, error) {
+	networks, err := e.client.NetworkList(ctx, network.ListOptions{})
+	if err != nil {
+		return nil, e.wrapError(err, nil, "list networks")
+	}
+
+	names := make([]string, 0, len(networks))
+	for _, n := range networks {
+		names = append(names, n.Name)
+	}
+
+	return names, nil
+}
+
+// containerInspectStatus converts an inspect response into a Status.
+func containerInspectStatus(resp container.InspectResponse, now time.Time) *Status {
+	status := &Status{
+		ContainerID:  resp.ID,
+		RestartCount: resp.RestartCount,
+		State:        StateUnknown,
+	}
+
+	if resp.State == nil {
+		return status
+	}
+
+	status.PID = resp.State.Pid
+	status.ExitCode = resp.State.ExitCode
+	status.Detail = string(resp.State.Status)
+
+	switch resp.State.Status {
+	case "running":
+		status.State = StateRunning
+	case "restarting":
+		status.State = StateRestarting
+	case "created", "paused", "removing", "configured", "stopping", "stopped":
+		// Podman adds configured, stopping and stopped to docker's states.
+		status.State = StateStopped
+	case "exited":
+		status.State = StateStopped
+		if resp.State.ExitCode != 0 {
+			status.State = StateFailed
+		}
+	case "dead":
+		status.State = StateFailed
+	}
+
+	// Docker reports a zero time for containers that have never started.
+	if startedAt, err := time.Parse(time.RFC3339Nano, resp.State.StartedAt); err == nil && startedAt.Year() > 1 {
+		status.StartedAt = startedAt
+	}
+
+	status.setUptime(now)
+
+	return status
+}
+
+// imageVersion returns the contributoor version from image labels.
+func imageVersion(labels map[string]string) string {
+	return strings.TrimPrefix(strings.TrimSpace(labels[imageVersionLabel]), "v")
+}
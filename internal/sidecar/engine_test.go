@@ -0,0 +1,355 @@
+package sidecar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// containerName is the name of the default instance's container.
+const containerName = "contributoor"
+
+// fakeEngine is a minimal Engine API server.
+type fakeEngine struct {
+	// containers are inspect responses keyed by the name or id they're looked up by.
+	containers map[string]string
+	// images are image labels keyed by reference.
+	images    map[string]map[string]string
+	pullError string
+	removed   []string
+	pulled    []string
+	// logsQuery is the query of the last logs request.
+	logsQuery url.Values
+}
+
+func (f *fakeEngine) notFound(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+func (f *fakeEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Strip the API version prefix, eg: /v1.47.
+	path := r.URL.Path
+	if parts := strings.SplitN(path, "/", 3); len(parts) == 3 && strings.HasPrefix(parts[1], "v1.") {
+		path = "/" + parts[2]
+	}
+
+	switch {
+	case r.Method == http.MethodGet && path == "/networks":
+		_ = json.NewEncoder(w).Encode([]map[string]string{{"Name": "bridge"}, {"Name": "eth-net"}})
+	case r.Method == http.MethodGet && path == "/version":
+		_ = json.NewEncoder(w).Encode(map[string]string{"Version": "27.0.1", "ApiVersion": "1.47"})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/containers/") && strings.HasSuffix(path, "/json"):
+		name := strings.TrimSuffix(strings.TrimPrefix(path, "/containers/"), "/json")
+
+		resp, ok := f.containers[name]
+		if !ok {
+			f.notFound(w, "No such container: "+name)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(resp))
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "/containers/"):
+		f.removed = append(f.removed, strings.TrimPrefix(path, "/containers/"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/containers/") && strings.HasSuffix(path, "/logs"):
+		f.logsQuery = r.URL.Query()
+
+		_, _ = stdcopy.NewStdWriter(w, stdcopy.Stdout).Write([]byte("to stdout\n"))
+		_, _ = stdcopy.NewStdWriter(w, stdcopy.Stderr).Write([]byte("to stderr\n"))
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/images/") && strings.HasSuffix(path, "/json"):
+		ref := strings.TrimSuffix(strings.TrimPrefix(path, "/images/"), "/json")
+
+		labels, ok := f.images[ref]
+		if !ok {
+			f.notFound(w, "No such image: "+ref)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"Id": "sha256:abc", "Config": map[string]any{"Labels": labels}})
+	case r.Method == http.MethodPost && path == "/images/create":
+		f.pulled = append(f.pulled, r.URL.Query().Get("fromImage")+":"+r.URL.Query().Get("tag"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"Pulling from ethpandaops/contributoor"}` + "\n"))
+
+		if f.pullError != "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"errorDetail": map[string]string{"message": f.pullError}, "error": f.pullError})
+		}
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "/images/"):
+		ref := strings.TrimPrefix(path, "/images/")
+		if _, ok := f.images[ref]; !ok {
+			f.notFound(w, "No such image: "+ref)
+
+			return
+		}
+
+		f.removed = append(f.removed, ref)
+		_, _ = w.Write([]byte(`[]`))
+	default:
+		http.Error(w, "unexpected request "+r.Method+" "+path, http.StatusNotImplemented)
+	}
+}
+
+func newFakeEngine(t *testing.T, fake *fakeEngine) *dockerEngine {
+	t.Helper()
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	apiClient, err := client.NewClientWithOpts(
+		client.WithHost("tcp://"+strings.TrimPrefix(server.URL, "http://")),
+		client.WithVersion("1.47"),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = apiClient.Close() })
+
+	return newDockerEngine(apiClient)
+}
+
+const runningContainer = `{"Id":"abc123","Name":"/contributoor","RestartCount":1,` +
+	`"State":{"Status":"running","Pid":42,"StartedAt":"2024-01-01T11:00:00Z"},` +
+	`"Config":{"Labels":{"org.opencontainers.image.version":"v1.2.3"}}}`
+
+func TestDockerEngine_ContainerStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("running", func(t *testing.T) {
+		engine := newFakeEngine(t, &fakeEngine{containers: map[string]string{"contributoor": runningContainer}})
+
+		status, err := engine.containerStatus(context.Background(), containerName, now)
+		require.NoError(t, err)
+		assert.Equal(t, StateRunning, status.State)
+		assert.Equal(t, "abc123", status.ContainerID)
+		assert.Equal(t, time.Hour, status.Uptime)
+	})
+
+	t.Run("missing container is stopped", func(t *testing.T) {
+		engine := newFakeEngine(t, &fakeEngine{})
+
+		status, err := engine.containerStatus(context.Background(), containerName, now)
+		require.NoError(t, err)
+		assert.Equal(t, &Status{State: StateStopped, Detail: "not running"}, status)
+	})
+
+	t.Run("only matches exact names", func(t *testing.T) {
+		// The engine resolved our name to a different container, eg: by id prefix.
+		engine := newFakeEngine(t, &fakeEngine{containers: map[string]string{
+			"contributoor": `{"Id":"def456","Name":"/contributoor-old","State":{"Status":"running"}}`,
+		}})
+
+		_, err := engine.inspectContainer(context.Background(), containerName)
+		require.ErrorIs(t, err, ErrContainerNotFound)
+
+		running, err := engine.containerStatus(context.Background(), containerName, now)
+		require.NoError(t, err)
+		assert.False(t, running.IsRunning())
+	})
+
+	t.Run("engine unavailable", func(t *testing.T) {
+		apiClient, err := client.NewClientWithOpts(client.WithHost("unix://" + filepath.Join(t.TempDir(), "missing.sock")))
+		require.NoError(t, err)
+
+		_, err = newDockerEngine(apiClient).containerStatus(context.Background(), containerName, now)
+		require.ErrorIs(t, err, ErrEngineUnavailable)
+	})
+}
+
+func TestDockerEngine_RemoveContainer(t *testing.T) {
+	fake := &fakeEngine{containers: map[string]string{"contributoor": runningContainer}}
+	engine := newFakeEngine(t, fake)
+
+	require.NoError(t, engine.removeContainer(context.Background(), containerName))
+	assert.Equal(t, []string{"abc123"}, fake.removed)
+
+	// Removing a container which doesn't exist is a no-op.
+	fake = &fakeEngine{}
+	engine = newFakeEngine(t, fake)
+
+	require.NoError(t, engine.removeContainer(context.Background(), containerName))
+	assert.Empty(t, fake.removed)
+}
+
+func TestDockerEngine_Versions(t *testing.T) {
+	engine := newFakeEngine(t, &fakeEngine{
+		containers: map[string]string{"contributoor": runningContainer},
+		images: map[string]map[string]string{
+			"ethpandaops/contributoor:1.0.0": {"org.opencontainers.image.version": "1.0.0"},
+		},
+	})
+
+	version, err := engine.containerVersion(context.Background(), containerName)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", version)
+
+	version, err = engine.imageVersion(context.Background(), "ethpandaops/contributoor:1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", version)
+
+	_, err = engine.imageVersion(context.Background(), "ethpandaops/contributoor:9.9.9")
+	require.ErrorIs(t, err, ErrImageNotFound)
+
+	version, err = engine.serverVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "27.0.1", version)
+}
+
+func TestDockerEngine_NetworkList(t *testing.T) {
+	engine := newFakeEngine(t, &fakeEngine{})
+
+	networks, err := engine.networkList(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bridge", "eth-net"}, networks)
+}
+
+func TestDockerEngine_Images(t *testing.T) {
+	fake := &fakeEngine{images: map[string]map[string]string{"ethpandaops/contributoor:1.0.0": nil}}
+	engine := newFakeEngine(t, fake)
+
+	require.NoError(t, engine.pullImage(context.Background(), "ethpandaops/contributoor:1.0.0"))
+	// The client normalises references before pulling.
+	assert.Equal(t, []string{"docker.io/ethpandaops/contributoor:1.0.0"}, fake.pulled)
+
+	// Errors are reported part way through the pull stream.
+	fake.pullError = "manifest unknown"
+	assert.ErrorContains(t, engine.pullImage(context.Background(), "ethpandaops/contributoor:9.9.9"), "manifest unknown")
+
+	require.NoError(t, engine.removeImage(context.Background(), "ethpandaops/contributoor:1.0.0"))
+	require.NoError(t, engine.removeImage(context.Background(), "ethpandaops/contributoor:9.9.9"))
+	assert.Equal(t, []string{"ethpandaops/contributoor:1.0.0"}, fake.removed)
+}
+
+func TestDockerEngine_StreamLogs(t *testing.T) {
+	fake := &fakeEngine{containers: map[string]string{"contributoor": runningContainer}}
+	engine := newFakeEngine(t, fake)
+
+	var stdout, stderr bytes.Buffer
+
+	require.NoError(t, engine.streamLogs(context.Background(), containerName, LogOptions{Tail: 10}, &stdout, &stderr))
+	assert.Equal(t, "to stdout\n", stdout.String())
+	assert.Equal(t, "to stderr\n", stderr.String())
+	assert.Equal(t, "10", fake.logsQuery.Get("tail"))
+	assert.Empty(t, fake.logsQuery.Get("since"))
+
+	opts := LogOptions{
+		Since: time.Unix(1700000000, 0),
+		Until: time.Unix(1700003600, 0),
+	}
+
+	require.NoError(t, engine.streamLogs(context.Background(), containerName, opts, &stdout, &stderr))
+	assert.Equal(t, "all", fake.logsQuery.Get("tail"))
+	assert.Equal(t, "1700000000", fake.logsQuery.Get("since"))
+	assert.Equal(t, "1700003600", fake.logsQuery.Get("until"))
+
+	err := newFakeEngine(t, &fakeEngine{}).streamLogs(context.Background(), containerName, LogOptions{Tail: 10}, &stdout, &stderr)
+	require.ErrorIs(t, err, ErrContainerNotFound)
+}
+
+func TestContainerInspectStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		output   string
+		expected *Status
+	}{
+		{
+			name: "running",
+			output: `{"Id":"abc123","RestartCount":3,"State":{"Status":"running","Restarting":false,` +
+				`"Pid":4242,"ExitCode":0,"StartedAt":"2024-01-01T11:00:00.123456789Z"}}`,
+			expected: &Status{
+				State:        StateRunning,
+				PID:          4242,
+				ContainerID:  "abc123",
+				StartedAt:    time.Date(2024, 1, 1, 11, 0, 0, 123456789, time.UTC),
+				Uptime:       59*time.Minute + 59*time.Second,
+				RestartCount: 3,
+				Detail:       "running",
+			},
+		},
+		{
+			name:   "exited cleanly",
+			output: `{"Id":"abc123","State":{"Status":"exited","ExitCode":0,"StartedAt":"2024-01-01T11:00:00Z"}}`,
+			expected: &Status{
+				State:       StateStopped,
+				ContainerID: "abc123",
+				StartedAt:   time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+				Detail:      "exited",
+			},
+		},
+		{
+			name:   "exited with error",
+			output: `{"Id":"abc123","State":{"Status":"exited","ExitCode":137,"StartedAt":"2024-01-01T11:00:00Z"}}`,
+			expected: &Status{
+				State:       StateFailed,
+				ContainerID: "abc123",
+				StartedAt:   time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+				ExitCode:    137,
+				Detail:      "exited",
+			},
+		},
+		{
+			name:   "restarting",
+			output: `{"Id":"abc123","RestartCount":5,"State":{"Status":"restarting","Restarting":true,"ExitCode":1}}`,
+			expected: &Status{
+				State:        StateRestarting,
+				ContainerID:  "abc123",
+				RestartCount: 5,
+				ExitCode:     1,
+				Detail:       "restarting",
+			},
+		},
+		{
+			name:   "created but never started",
+			output: `{"Id":"abc123","State":{"Status":"created","StartedAt":"0001-01-01T00:00:00Z"}}`,
+			expected: &Status{
+				State:       StateStopped,
+				ContainerID: "abc123",
+				Detail:      "created",
+			},
+		},
+		{
+			name:   "podman stopped",
+			output: `{"Id":"abc123","State":{"Status":"stopped","ExitCode":0,"StartedAt":"2024-01-01T11:00:00.5Z"}}`,
+			expected: &Status{
+				State:       StateStopped,
+				ContainerID: "abc123",
+				StartedAt:   time.Date(2024, 1, 1, 11, 0, 0, 500000000, time.UTC),
+				Detail:      "stopped",
+			},
+		},
+		{
+			name:     "no state",
+			output:   `{"Id":"abc123"}`,
+			expected: &Status{State: StateUnknown, ContainerID: "abc123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp container.InspectResponse
+			require.NoError(t, json.Unmarshal([]byte(tt.output), &resp))
+
+			assert.Equal(t, tt.expected, containerInspectStatus(resp, now))
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package sidecar
 
 import (
+	"bytes"
+	"context"
 	"fmt"
-	"net/http"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -13,38 +15,76 @@ import (
 	"github.com/ethpandaops/contributoor/pkg/config/v1"
 )
 
-// updateInstaller updates the installer binary to the specified version.
-func updateInstaller(cfg *config.Config, installerCfg *installer.Config) error {
-	releaseDir := filepath.Join(cfg.ContributoorDirectory, "releases", fmt.Sprintf("installer-%s", cfg.Version))
-	if err := os.MkdirAll(releaseDir, 0755); err != nil {
-		return fmt.Errorf("failed to create release directory: %w", err)
+// updateInstaller updates the installer binary to the specified version. The archive is
+// verified against the release checksums, and those against their signature, before
+// anything is extracted. Without a signing key the update is refused.
+func updateInstaller(ctx context.Context, cfg *config.Config, installerCfg *installer.Config) error {
+	var (
+		releaseURL  = fmt.Sprintf("https://github.com/%s/%s/releases/download/v%s", installerCfg.GithubOrg, installerCfg.GithubInstallerRepo, cfg.Version)
+		checksumURL = fmt.Sprintf("%s/contributoor-installer_%s_checksums.txt", releaseURL, cfg.Version)
+		archiveName = fmt.Sprintf("contributoor-installer_%s_%s_%s.tar.gz", cfg.Version, runtime.GOOS, runtime.GOARCH)
+	)
+
+	if installerCfg.SigningPublicKey == "" {
+		return fmt.Errorf("no release signing key is built into this installer, set signingPublicKey in installer.yaml to verify updates")
 	}
 
-	// Download new version.
-	downloadURL := fmt.Sprintf("https://github.com/%s/%s/releases/download/v%s/contributoor-installer_%s_%s_%s.tar.gz",
-		installerCfg.GithubOrg,
-		installerCfg.GithubInstallerRepo,
-		cfg.Version,
-		cfg.Version,
-		runtime.GOOS,
-		runtime.GOARCH,
-	)
+	// Download checksums and verify their signature.
+	checksumData, err := downloadReleaseFile(ctx, checksumURL)
+	if err != nil {
+		return fmt.Errorf("failed to download checksums: %w", err)
+	}
+
+	signature, err := downloadReleaseFile(ctx, checksumURL+".sig")
+	if err != nil {
+		return fmt.Errorf("failed to download checksums signature: %w", err)
+	}
+
+	if err := verifySignature(installerCfg.SigningPublicKey, checksumData, signature); err != nil {
+		return fmt.Errorf("failed to verify checksums: %w", err)
+	}
+
+	checksums, err := parseChecksums(bytes.NewReader(checksumData))
+	if err != nil {
+		return err
+	}
 
-	//nolint:gosec // controlled url.
-	resp, err := http.Get(downloadURL)
+	// Download new version.
+	resp, err := getReleaseAsset(ctx, fmt.Sprintf("%s/%s", releaseURL, archiveName))
 	if err != nil {
 		return fmt.Errorf("failed to download installer: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to download installer: HTTP %d", resp.StatusCode)
+	tmpFile, err := os.CreateTemp("", "contributoor-installer-*.tar.gz")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	w, digest := hashingWriter(tmpFile)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to write installer to temp file: %w", err)
+	}
+
+	if err := verifyChecksum(checksums, archiveName, digest()); err != nil {
+		return fmt.Errorf("failed to verify installer: %w", err)
+	}
+
+	// Last chance to bail out before we start touching the installation.
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("update cancelled: %w", err)
 	}
 
-	// Extract directly to release directory.
-	cmd := exec.Command("tar", "--no-same-owner", "-xzf", "-", "-C", releaseDir)
-	cmd.Stdin = resp.Body
+	releaseDir := filepath.Join(cfg.ContributoorDirectory, "releases", fmt.Sprintf("installer-%s", cfg.Version))
+	if err := os.MkdirAll(releaseDir, 0755); err != nil {
+		return fmt.Errorf("failed to create release directory: %w", err)
+	}
 
+	// Extract to release directory.
+	cmd := exec.CommandContext(ctx, "tar", "--no-same-owner", "-xzf", tmpFile.Name(), "-C", releaseDir) //nolint:gosec // controlled extraction.
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to extract installer: %w", err)
 	}
@@ -59,15 +99,32 @@ func updateInstaller(cfg *config.Config, installerCfg *installer.Config) error {
 		return fmt.Errorf("failed to set binary permissions: %w", err)
 	}
 
-	if err := os.Remove(symlink); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove old symlink: %w", err)
+	if err := swapSymlink(newBinary, symlink); err != nil {
+		return err
 	}
 
-	if err := os.Symlink(newBinary, symlink); err != nil {
+	fmt.Printf("%sInstaller updated successfully%s\n", tui.TerminalColorGreen, tui.TerminalColorReset)
+
+	return nil
+}
+
+// swapSymlink atomically points symlink at target, so the existing link is left
+// intact if anything fails (or we're interrupted) part way through.
+func swapSymlink(target, symlink string) error {
+	tmpSymlink := symlink + ".new"
+	if err := os.Remove(tmpSymlink); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale symlink: %w", err)
+	}
+
+	if err := os.Symlink(target, tmpSymlink); err != nil {
 		return fmt.Errorf("failed to create symlink: %w", err)
 	}
 
-	fmt.Printf("%sInstaller updated successfully%s\n", tui.TerminalColorGreen, tui.TerminalColorReset)
+	if err := os.Rename(tmpSymlink, symlink); err != nil {
+		os.Remove(tmpSymlink)
+
+		return fmt.Errorf("failed to update symlink: %w", err)
+	}
 
 	return nil
 }
@@ -76,7 +133,8 @@ func updateInstaller(cfg *config.Config, installerCfg *installer.Config) error {
 func wrapNotInstalledError(err error, mode string) error {
 	return fmt.Errorf("error:\n\n"+
 		"Contributoor is not installed under %s mode. Please run 'contributoor install' first, selecting the %s mode as your preferred run mode.\n\n"+
-		"This can occur if you switch between run modes and haven't completed installation for the new mode.\n\n"+
+		"This can occur if the run mode was changed without installing the new mode, "+
+		"use 'contributoor switch-run-method' to switch between run modes.\n\n"+
 		"Debug details: %w",
 		mode,
 		mode,
@@ -4,12 +4,16 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/ethpandaops/contributoor-installer/internal/installer"
@@ -19,14 +23,36 @@ import (
 )
 
 func TestUpdateInstaller(t *testing.T) {
-	// Setup a mock tar.gz file.
-	mockTarGz := createMockTarGz(t, "contributoor", []byte("mock-installer-binary"))
+	const version = "0.0.1"
+
+	var (
+		mockTarGz   = createMockTarGz(t, "contributoor", []byte("mock-installer-binary"))
+		tampered    = createMockTarGz(t, "contributoor", []byte("tampered-installer-binary"))
+		archiveName = fmt.Sprintf("contributoor-installer_%s_%s_%s.tar.gz", version, runtime.GOOS, runtime.GOARCH)
+		basePath    = fmt.Sprintf("/ethpandaops/contributoor-installer/releases/download/v%s/", version)
+		checksums   = fmt.Sprintf("%s  %s\n", sha256Hex(mockTarGz), archiveName)
+		// otherChecksums are signed, but don't list the archive.
+		otherChecksums = fmt.Sprintf("%s  other.tar.gz\n", sha256Hex(mockTarGz))
+	)
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	_, otherPriv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	var (
+		publicKey      = base64.StdEncoding.EncodeToString(pub)
+		signature      = ed25519.Sign(priv, []byte(checksums))
+		otherSignature = ed25519.Sign(otherPriv, []byte(checksums))
+	)
 
 	tests := []struct {
 		name         string
 		cfg          *config.Config
 		installerCfg *installer.Config
-		serverFunc   func() *httptest.Server
+		files        map[string][]byte
+		cancelled    bool
 		wantErr      bool
 		errContains  string
 	}{
@@ -34,18 +60,17 @@ func TestUpdateInstaller(t *testing.T) {
 			name: "successful update",
 			cfg: &config.Config{
 				ContributoorDirectory: "",
-				Version:               "0.0.1",
+				Version:               version,
 			},
 			installerCfg: &installer.Config{
 				GithubOrg:           "ethpandaops",
 				GithubInstallerRepo: "contributoor-installer",
+				SigningPublicKey:    publicKey,
 			},
-			serverFunc: func() *httptest.Server {
-				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-					if _, err := w.Write(mockTarGz); err != nil {
-						t.Fatalf("failed to write mock tar.gz: %v", err)
-					}
-				}))
+			files: map[string][]byte{
+				"contributoor-installer_0.0.1_checksums.txt":     []byte(checksums),
+				"contributoor-installer_0.0.1_checksums.txt.sig": signature,
+				archiveName: mockTarGz,
 			},
 			wantErr: false,
 		},
@@ -53,17 +78,14 @@ func TestUpdateInstaller(t *testing.T) {
 			name: "404 error",
 			cfg: &config.Config{
 				ContributoorDirectory: "",
-				Version:               "0.0.1",
+				Version:               version,
 			},
 			installerCfg: &installer.Config{
 				GithubOrg:           "ethpandaops",
 				GithubInstallerRepo: "contributoor-installer",
+				SigningPublicKey:    publicKey,
 			},
-			serverFunc: func() *httptest.Server {
-				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-					w.WriteHeader(http.StatusNotFound)
-				}))
-			},
+			files:       map[string][]byte{},
 			wantErr:     true,
 			errContains: "HTTP 404",
 		},
@@ -71,26 +93,158 @@ func TestUpdateInstaller(t *testing.T) {
 			name: "invalid directory",
 			cfg: &config.Config{
 				ContributoorDirectory: "/nonexistent/directory",
-				Version:               "0.0.1",
+				Version:               version,
 			},
 			installerCfg: &installer.Config{
 				GithubOrg:           "ethpandaops",
 				GithubInstallerRepo: "contributoor-installer",
+				SigningPublicKey:    publicKey,
 			},
-			serverFunc: func() *httptest.Server {
-				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-					if _, err := w.Write(mockTarGz); err != nil {
-						t.Fatalf("failed to write mock tar.gz: %v", err)
-					}
-				}))
+			files: map[string][]byte{
+				"contributoor-installer_0.0.1_checksums.txt":     []byte(checksums),
+				"contributoor-installer_0.0.1_checksums.txt.sig": signature,
+				archiveName: mockTarGz,
 			},
 			wantErr:     true,
 			errContains: "failed to create release directory",
 		},
+		{
+			name: "tampered archive",
+			cfg: &config.Config{
+				ContributoorDirectory: "",
+				Version:               version,
+			},
+			installerCfg: &installer.Config{
+				GithubOrg:           "ethpandaops",
+				GithubInstallerRepo: "contributoor-installer",
+				SigningPublicKey:    publicKey,
+			},
+			files: map[string][]byte{
+				"contributoor-installer_0.0.1_checksums.txt":     []byte(checksums),
+				"contributoor-installer_0.0.1_checksums.txt.sig": signature,
+				archiveName: tampered,
+			},
+			wantErr:     true,
+			errContains: "checksum mismatch",
+		},
+		{
+			name: "missing checksum entry",
+			cfg: &config.Config{
+				ContributoorDirectory: "",
+				Version:               version,
+			},
+			installerCfg: &installer.Config{
+				GithubOrg:           "ethpandaops",
+				GithubInstallerRepo: "contributoor-installer",
+				SigningPublicKey:    publicKey,
+			},
+			files: map[string][]byte{
+				"contributoor-installer_0.0.1_checksums.txt":     []byte(otherChecksums),
+				"contributoor-installer_0.0.1_checksums.txt.sig": ed25519.Sign(priv, []byte(otherChecksums)),
+				archiveName: mockTarGz,
+			},
+			wantErr:     true,
+			errContains: "checksum not found",
+		},
+		{
+			name: "valid signature",
+			cfg: &config.Config{
+				ContributoorDirectory: "",
+				Version:               version,
+			},
+			installerCfg: &installer.Config{
+				GithubOrg:           "ethpandaops",
+				GithubInstallerRepo: "contributoor-installer",
+				SigningPublicKey:    publicKey,
+			},
+			files: map[string][]byte{
+				"contributoor-installer_0.0.1_checksums.txt":     []byte(checksums),
+				"contributoor-installer_0.0.1_checksums.txt.sig": []byte(base64.StdEncoding.EncodeToString(signature)),
+				archiveName: mockTarGz,
+			},
+			wantErr: false,
+		},
+		{
+			name: "signature from another key",
+			cfg: &config.Config{
+				ContributoorDirectory: "",
+				Version:               version,
+			},
+			installerCfg: &installer.Config{
+				GithubOrg:           "ethpandaops",
+				GithubInstallerRepo: "contributoor-installer",
+				SigningPublicKey:    publicKey,
+			},
+			files: map[string][]byte{
+				"contributoor-installer_0.0.1_checksums.txt":     []byte(checksums),
+				"contributoor-installer_0.0.1_checksums.txt.sig": otherSignature,
+				archiveName: mockTarGz,
+			},
+			wantErr:     true,
+			errContains: "signature verification failed",
+		},
+		{
+			name: "missing signature",
+			cfg: &config.Config{
+				ContributoorDirectory: "",
+				Version:               version,
+			},
+			installerCfg: &installer.Config{
+				GithubOrg:           "ethpandaops",
+				GithubInstallerRepo: "contributoor-installer",
+				SigningPublicKey:    publicKey,
+			},
+			files: map[string][]byte{
+				"contributoor-installer_0.0.1_checksums.txt": []byte(checksums),
+				archiveName: mockTarGz,
+			},
+			wantErr:     true,
+			errContains: "failed to download checksums signature",
+		},
+		{
+			name: "no signing key",
+			cfg: &config.Config{
+				ContributoorDirectory: "",
+				Version:               version,
+			},
+			installerCfg: &installer.Config{
+				GithubOrg:           "ethpandaops",
+				GithubInstallerRepo: "contributoor-installer",
+			},
+			files: map[string][]byte{
+				"contributoor-installer_0.0.1_checksums.txt":     []byte(checksums),
+				"contributoor-installer_0.0.1_checksums.txt.sig": signature,
+				archiveName: mockTarGz,
+			},
+			wantErr:     true,
+			errContains: "no release signing key",
+		},
+		{
+			name: "cancelled",
+			cfg: &config.Config{
+				ContributoorDirectory: "",
+				Version:               version,
+			},
+			installerCfg: &installer.Config{
+				GithubOrg:           "ethpandaops",
+				GithubInstallerRepo: "contributoor-installer",
+				SigningPublicKey:    publicKey,
+			},
+			files: map[string][]byte{
+				"contributoor-installer_0.0.1_checksums.txt":     []byte(checksums),
+				"contributoor-installer_0.0.1_checksums.txt.sig": signature,
+				archiveName: mockTarGz,
+			},
+			cancelled:   true,
+			wantErr:     true,
+			errContains: "context canceled",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var existingTarget string
+
 			// Create a new temporary directory for each test.
 			if tt.cfg.ContributoorDirectory != "/nonexistent/directory" {
 				tempDir, err := os.MkdirTemp("", "installer-test-*")
@@ -98,41 +252,42 @@ func TestUpdateInstaller(t *testing.T) {
 
 				defer os.RemoveAll(tempDir)
 
-				// Create bin directory.
+				// Create bin directory, with a symlink to the currently installed version.
 				require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "bin"), 0755))
 
+				existingTarget = filepath.Join(tempDir, "releases", "installer-0.0.0", "contributoor")
+				require.NoError(t, os.Symlink(existingTarget, filepath.Join(tempDir, "bin", "contributoor")))
+
 				// Use the temp directory as the Contributoor directory for the test.
 				tt.cfg.ContributoorDirectory = tempDir
 			}
 
-			// Start test server.
-			server := tt.serverFunc()
+			// Start test release server.
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, ok := tt.files[strings.TrimPrefix(r.URL.Path, basePath)]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+
+					return
+				}
+
+				if _, err := w.Write(data); err != nil {
+					t.Fatalf("failed to write release file: %v", err)
+				}
+			}))
 			defer server.Close()
 
-			// Override the download URL to use our test server.
-			origURL := fmt.Sprintf(
-				"https://github.com/%s/%s/releases/download/v%s/contributoor-installer_%s_%s_%s.tar.gz",
-				tt.installerCfg.GithubOrg,
-				tt.installerCfg.GithubInstallerRepo,
-				tt.cfg.Version,
-				tt.cfg.Version,
-				runtime.GOOS,
-				runtime.GOARCH,
-			)
-
-			// Patch http.Get to redirect GitHub URLs to our test server.
-			oldClient := http.DefaultClient
-			http.DefaultClient = &http.Client{
-				Transport: &mockTransport{
-					origURL:    origURL,
-					mockURL:    server.URL,
-					origClient: oldClient.Transport,
-				},
-			}
+			useReleaseServer(t, server.URL)
 
-			defer func() { http.DefaultClient = oldClient }()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 
-			err := updateInstaller(tt.cfg, tt.installerCfg)
+			if tt.cancelled {
+				cancel()
+			}
+
+			err := updateInstaller(ctx, tt.cfg, tt.installerCfg)
+			binPath := filepath.Join(tt.cfg.ContributoorDirectory, "bin", "contributoor")
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -140,11 +295,17 @@ func TestUpdateInstaller(t *testing.T) {
 				if tt.errContains != "" {
 					assert.Contains(t, err.Error(), tt.errContains)
 				}
+
+				// The existing symlink should be left untouched.
+				if existingTarget != "" {
+					target, err := os.Readlink(binPath)
+					require.NoError(t, err)
+					assert.Equal(t, existingTarget, target)
+				}
 			} else {
 				require.NoError(t, err)
 
 				// Verify the binary was created and is executable.
-				binPath := filepath.Join(tt.cfg.ContributoorDirectory, "bin", "contributoor")
 				_, err := os.Stat(binPath)
 				assert.NoError(t, err)
 
@@ -162,38 +323,6 @@ func TestUpdateInstaller(t *testing.T) {
 	}
 }
 
-// mockTransport redirects GitHub URLs to our test server.
-type mockTransport struct {
-	origURL    string
-	mockURL    string
-	origClient http.RoundTripper
-}
-
-func (t *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	if req.URL.String() == t.origURL {
-		newReq := *req
-
-		var err error
-
-		newReq.URL, err = req.URL.Parse(t.mockURL)
-		if err != nil {
-			return nil, err
-		}
-
-		if t.origClient == nil {
-			t.origClient = http.DefaultTransport
-		}
-
-		return t.origClient.RoundTrip(&newReq)
-	}
-
-	if t.origClient == nil {
-		t.origClient = http.DefaultTransport
-	}
-
-	return t.origClient.RoundTrip(req)
-}
-
 // createMockTarGz creates a mock tar.gz file for testing.
 func createMockTarGz(t *testing.T, filename string, data []byte) []byte {
 	t.Helper()
@@ -0,0 +1,115 @@
+package sidecar
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	// InstancesDir is the directory, under the config path, holding named instances.
+	InstancesDir = "instances"
+	// DefaultInstanceName is the name the unnamed instance is shown as.
+	DefaultInstanceName = "default"
+)
+
+// Instance is a contributoor installation found under the config path.
+type Instance struct {
+	// Name is the name of the instance, empty for the default instance.
+	Name string
+	// ConfigPath is the config directory of the instance.
+	ConfigPath string
+}
+
+// DisplayName returns the name of the instance for display.
+func (i Instance) DisplayName() string {
+	if i.Name == "" {
+		return DefaultInstanceName
+	}
+
+	return i.Name
+}
+
+// InstanceConfigPath returns the config directory of instance under configPath. The
+// default instance lives in configPath itself.
+func InstanceConfigPath(configPath, instance string) string {
+	if instance == "" {
+		return configPath
+	}
+
+	return filepath.Join(configPath, InstancesDir, instance)
+}
+
+// ServiceName returns the name of the container, compose project, systemd unit and
+// launchd label of instance, eg: contributoor or contributoor-holesky.
+func ServiceName(instance string) string {
+	if instance == "" {
+		return "contributoor"
+	}
+
+	return "contributoor-" + instance
+}
+
+// ListInstances returns the default instance, if installed, followed by the named
+// instances under configPath in name order.
+func ListInstances(configPath string) ([]Instance, error) {
+	path, err := homedir.Expand(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error expanding config path [%s]: %w", configPath, err)
+	}
+
+	var instances []Instance
+
+	if hasConfig(path) {
+		instances = append(instances, Instance{ConfigPath: path})
+	}
+
+	entries, err := os.ReadDir(filepath.Join(path, InstancesDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read instances: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, entry := range entries {
+		dir := filepath.Join(path, InstancesDir, entry.Name())
+		if entry.IsDir() && hasConfig(dir) {
+			instances = append(instances, Instance{Name: entry.Name(), ConfigPath: dir})
+		}
+	}
+
+	return instances, nil
+}
+
+// InitInstanceConfig writes a default config to the config directory of a new
+// instance, so it can be set up with the install command. Existing configs are
+// left alone.
+func InitInstanceConfig(configDir string) error {
+	path, err := homedir.Expand(configDir)
+	if err != nil {
+		return fmt.Errorf("error expanding config path [%s]: %w", configDir, err)
+	}
+
+	if hasConfig(path) {
+		return nil
+	}
+
+	// The binary run method writes its logs here.
+	if err := os.MkdirAll(filepath.Join(path, "logs"), 0755); err != nil { //nolint:gosec // Logs are read by the user.
+		return fmt.Errorf("failed to create instance directory: %w", err)
+	}
+
+	cfg := newDefaultConfig()
+	cfg.ContributoorDirectory = path
+
+	return writeConfig(filepath.Join(path, "config.yaml"), cfg)
+}
+
+func hasConfig(dir string) bool {
+	fi, err := os.Stat(filepath.Join(dir, "config.yaml"))
+
+	return err == nil && !fi.IsDir()
+}
@@ -0,0 +1,268 @@
+package sidecar
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// journalGroup is the group whose members can read the system journal.
+	journalGroup = "systemd-journal"
+	// maxJournalEntrySize caps the size of a single exported journal entry.
+	maxJournalEntrySize = 4 * 1024 * 1024
+)
+
+// JournalEntry is an entry of the systemd journal, as exported by journalctl -o json.
+type JournalEntry struct {
+	// Cursor identifies the entry's position in the journal.
+	Cursor string
+	// Time is when the entry was logged.
+	Time time.Time
+	// Hostname is the host the entry was logged on.
+	Hostname string
+	// Identifier is the name of the process which logged the entry, eg: "sentry".
+	Identifier string
+	// PID is the pid of the process which logged the entry, if known.
+	PID string
+	// Priority is the syslog priority of the entry, from 0 (emerg) to 7 (debug), or
+	// -1 if it doesn't have one.
+	Priority int
+	// Message is the message logged, which may span several lines.
+	Message string
+	// Fields are all of the entry's fields, eg: _SYSTEMD_UNIT. Fields with several
+	// values only have the first.
+	Fields map[string]string
+}
+
+// parseJournalEntry parses an entry exported by journalctl -o json.
+func parseJournalEntry(data []byte) (*JournalEntry, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse journal entry: %w", err)
+	}
+
+	fields := make(map[string]string, len(raw))
+
+	for key, value := range raw {
+		fields[key] = journalValue(value)
+	}
+
+	entry := &JournalEntry{
+		Cursor:     fields["__CURSOR"],
+		Hostname:   fields["_HOSTNAME"],
+		Identifier: fields["SYSLOG_IDENTIFIER"],
+		PID:        fields["_PID"],
+		Priority:   -1,
+		Message:    fields["MESSAGE"],
+		Fields:     fields,
+	}
+
+	if entry.Identifier == "" {
+		entry.Identifier = fields["_COMM"]
+	}
+
+	if usec, err := strconv.ParseInt(fields["__REALTIME_TIMESTAMP"], 10, 64); err == nil {
+		entry.Time = time.UnixMicro(usec)
+	}
+
+	if priority, err := strconv.Atoi(fields["PRIORITY"]); err == nil {
+		entry.Priority = priority
+	}
+
+	return entry, nil
+}
+
+// journalValue decodes a field value exported by journalctl. Values which aren't
+// printable text are exported as an array of bytes, and fields with several values
+// as an array of values.
+func journalValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any:
+		if len(v) == 0 {
+			return ""
+		}
+
+		if _, ok := v[0].(float64); !ok {
+			return journalValue(v[0])
+		}
+
+		data := make([]byte, 0, len(v))
+
+		for _, b := range v {
+			n, _ := b.(float64)
+			data = append(data, byte(n))
+		}
+
+		return string(data)
+	default:
+		// Values too large to export are null.
+		return ""
+	}
+}
+
+// lines renders the entry as lines like journalctl's default output, ie: prefixed by
+// the time, host and process which logged it. Continuation lines are indented.
+func (e *JournalEntry) lines(location *time.Location) []string {
+	var prefix strings.Builder
+
+	prefix.WriteString(e.Time.In(location).Format(time.Stamp))
+
+	if e.Hostname != "" {
+		prefix.WriteString(" " + e.Hostname)
+	}
+
+	prefix.WriteString(" " + e.Identifier)
+
+	if e.PID != "" {
+		prefix.WriteString("[" + e.PID + "]")
+	}
+
+	prefix.WriteString(": ")
+
+	lines := strings.Split(strings.TrimRight(e.Message, "\n"), "\n")
+	indent := strings.Repeat(" ", prefix.Len())
+
+	for i := range lines {
+		if i == 0 {
+			lines[i] = prefix.String() + lines[i]
+		} else {
+			lines[i] = indent + lines[i]
+		}
+	}
+
+	return lines
+}
+
+// canReadJournal returns true if the current user can read the system journal
+// without sudo, ie: they're root or in the systemd-journal group.
+var canReadJournal = func() bool {
+	if os.Geteuid() == 0 {
+		return true
+	}
+
+	group, err := user.LookupGroup(journalGroup)
+	if err != nil {
+		return false
+	}
+
+	gid, err := strconv.Atoi(group.Gid)
+	if err != nil {
+		return false
+	}
+
+	// The process's groups, rather than the user's, as being added to a group only
+	// takes effect on the next login.
+	groups, err := os.Getgroups()
+	if err != nil {
+		return false
+	}
+
+	return os.Getegid() == gid || slices.Contains(groups, gid)
+}
+
+// journalctlCommand returns the command running journalctl with args, under sudo if
+// the user can't read the journal themselves. If nonInteractive is set, sudo fails
+// rather than prompting for a password.
+func journalctlCommand(ctx context.Context, args []string, nonInteractive bool) *exec.Cmd {
+	name := "journalctl"
+
+	// Users can always read their own journal.
+	if !slices.Contains(args, "--user") && !canReadJournal() {
+		name, args = "sudo", append([]string{"journalctl"}, args...)
+
+		if nonInteractive {
+			args = append([]string{"-n"}, args...)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	// sudo passes SIGTERM on to journalctl, whereas a SIGKILL would leave it behind.
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+
+	return cmd
+}
+
+// readJournal runs journalctl with args, which must include -o json, calling fn with
+// each entry it exports.
+func readJournal(ctx context.Context, args []string, nonInteractive bool, fn func(entry *JournalEntry) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var stderr bytes.Buffer
+
+	cmd := journalctlCommand(ctx, args, nonInteractive)
+	cmd.Stderr = os.Stderr
+
+	if nonInteractive {
+		cmd.Stderr = &stderr
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run journalctl: %w", err)
+	}
+
+	var (
+		scanner = bufio.NewScanner(stdout)
+		readErr error
+	)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJournalEntrySize)
+
+	for scanner.Scan() {
+		entry, err := parseJournalEntry(scanner.Bytes())
+		if err == nil {
+			err = fn(entry)
+		}
+
+		if err != nil {
+			readErr = err
+
+			break
+		}
+	}
+
+	if readErr == nil {
+		readErr = scanner.Err()
+	}
+
+	// Stop journalctl if we gave up part way, so it isn't left blocked writing.
+	if readErr != nil {
+		cancel()
+	}
+
+	waitErr := cmd.Wait()
+
+	switch {
+	case readErr != nil:
+		return readErr
+	case waitErr != nil && ctx.Err() == nil:
+		if stderr.Len() > 0 {
+			return fmt.Errorf("failed to read journal: %w: %s", waitErr, strings.TrimSpace(stderr.String()))
+		}
+
+		return fmt.Errorf("failed to read journal: %w", waitErr)
+	}
+
+	return nil
+}
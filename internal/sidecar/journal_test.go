@@ -0,0 +1,176 @@
+package sidecar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJournalEntry(t *testing.T) {
+	entry, err := parseJournalEntry([]byte(`{
+		"__CURSOR": "s=abc;i=1",
+		"__REALTIME_TIMESTAMP": "1704164645123456",
+		"_HOSTNAME": "node",
+		"SYSLOG_IDENTIFIER": "sentry",
+		"_PID": "123",
+		"PRIORITY": "6",
+		"MESSAGE": "level=info msg=started",
+		"_SYSTEMD_UNIT": "contributoor.service"
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "s=abc;i=1", entry.Cursor)
+	assert.True(t, time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC).Equal(entry.Time))
+	assert.Equal(t, "node", entry.Hostname)
+	assert.Equal(t, "sentry", entry.Identifier)
+	assert.Equal(t, "123", entry.PID)
+	assert.Equal(t, 6, entry.Priority)
+	assert.Equal(t, "level=info msg=started", entry.Message)
+	assert.Equal(t, "contributoor.service", entry.Fields["_SYSTEMD_UNIT"])
+
+	// Messages which aren't printable text are exported as bytes, fields with several
+	// values as arrays, and values which are too large as null.
+	entry, err = parseJournalEntry([]byte(`{
+		"_COMM": "sentry",
+		"MESSAGE": [104, 105, 10, 27],
+		"TAG": ["first", "second"],
+		"LARGE": null
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "sentry", entry.Identifier)
+	assert.Equal(t, -1, entry.Priority)
+	assert.Equal(t, "hi\n\x1b", entry.Message)
+	assert.Equal(t, "first", entry.Fields["TAG"])
+	assert.Empty(t, entry.Fields["LARGE"])
+
+	_, err = parseJournalEntry([]byte("not json"))
+	assert.ErrorContains(t, err, "failed to parse journal entry")
+}
+
+func TestJournalEntryLines(t *testing.T) {
+	entry := &JournalEntry{
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Hostname:   "node",
+		Identifier: "sentry",
+		PID:        "123",
+		Message:    "panic: boom\ngoroutine 1 [running]:\n",
+	}
+
+	assert.Equal(t, []string{
+		"Jan  2 03:04:05 node sentry[123]: panic: boom",
+		"                                  goroutine 1 [running]:",
+	}, entry.lines(time.UTC))
+
+	entry = &JournalEntry{
+		Time:       time.Date(2024, 1, 12, 3, 4, 5, 0, time.UTC),
+		Identifier: "systemd",
+		Message:    "Started contributoor.service.",
+	}
+
+	assert.Equal(t, []string{"Jan 12 03:04:05 systemd: Started contributoor.service."}, entry.lines(time.UTC))
+}
+
+func TestJournalctlCommand(t *testing.T) {
+	original := canReadJournal
+
+	t.Cleanup(func() {
+		canReadJournal = original
+	})
+
+	canReadJournal = func() bool { return true }
+
+	cmd := journalctlCommand(context.Background(), []string{"-u", "contributoor.service"}, true)
+	assert.Equal(t, []string{"journalctl", "-u", "contributoor.service"}, cmd.Args)
+
+	canReadJournal = func() bool { return false }
+
+	cmd = journalctlCommand(context.Background(), []string{"-u", "contributoor.service"}, false)
+	assert.Equal(t, []string{"sudo", "journalctl", "-u", "contributoor.service"}, cmd.Args)
+
+	cmd = journalctlCommand(context.Background(), []string{"-u", "contributoor.service"}, true)
+	assert.Equal(t, []string{"sudo", "-n", "journalctl", "-u", "contributoor.service"}, cmd.Args)
+
+	// A user unit's logs are in the user's own journal.
+	cmd = journalctlCommand(context.Background(), []string{"--user", "-u", "contributoor.service"}, false)
+	assert.Equal(t, []string{"journalctl", "--user", "-u", "contributoor.service"}, cmd.Args)
+}
+
+func TestSystemdSidecar_Logs(t *testing.T) {
+	if runtime.GOOS == ArchDarwin {
+		t.Skip("macOS reads the binary sidecar's logs")
+	}
+
+	original := canReadJournal
+
+	t.Cleanup(func() {
+		canReadJournal = original
+	})
+
+	canReadJournal = func() bool { return true }
+
+	// A fake journalctl records its arguments, and exports an entry for the tail and
+	// another once following.
+	var (
+		dir     = t.TempDir()
+		argsLog = filepath.Join(dir, "args")
+		script  = `#!/bin/sh
+echo "$@" >> ` + argsLog + `
+case " $* " in
+*" -f "*) echo '{"__CURSOR":"c2","__REALTIME_TIMESTAMP":"1704164646000000","SYSLOG_IDENTIFIER":"sentry","MESSAGE":"level=error msg=second"}' ;;
+*) echo '{"__CURSOR":"c1","__REALTIME_TIMESTAMP":"1704164645000000","SYSLOG_IDENTIFIER":"sentry","MESSAGE":"level=info msg=first"}' ;;
+esac
+`
+	)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "journalctl"), []byte(script), 0755)) //nolint:gosec // test script.
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var (
+		out     strings.Builder
+		sidecar = &systemdSidecar{name: "contributoor"}
+	)
+
+	err := sidecar.Logs(context.Background(), LogOptions{
+		Tail:   10,
+		Follow: true,
+		Since:  time.Unix(1704160000, 0),
+		Output: &out,
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "sentry: level=info msg=first\n")
+	assert.Contains(t, out.String(), "sentry: level=error msg=second\n")
+
+	args, err := os.ReadFile(argsLog)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"-u contributoor.service -o json --no-pager --since @1704160000 -n 10",
+		"-u contributoor.service -o json --no-pager --since @1704160000 -f --after-cursor c1 -n all",
+	}, strings.Split(strings.TrimSpace(string(args)), "\n"))
+
+	// Entries are filtered by the printer, as journalctl only knows their priority.
+	out.Reset()
+
+	require.NoError(t, sidecar.Logs(context.Background(), LogOptions{Level: "error", Output: &out}))
+	assert.Empty(t, out.String())
+
+	// A user unit's logs are read from the user's journal.
+	require.NoError(t, os.Remove(argsLog))
+
+	sidecar.userScope = true
+
+	require.NoError(t, sidecar.Logs(context.Background(), LogOptions{Tail: 5, Output: &out}))
+
+	args, err = os.ReadFile(argsLog)
+	require.NoError(t, err)
+	assert.Equal(t, "--user -u contributoor.service -o json --no-pager -n 5\n", string(args))
+}
@@ -0,0 +1,142 @@
+package sidecar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/contributoor-installer/internal/tui"
+)
+
+const (
+	// prettyTimeFormat is the layout of timestamps in pretty output.
+	prettyTimeFormat = "2006-01-02 15:04:05.000"
+	// prettyMessageWidth is the width messages are padded to in pretty output, so
+	// the fields of consecutive entries line up.
+	prettyMessageWidth = 44
+)
+
+// formatLine renders line in the printer's format.
+func (p *logPrinter) formatLine(line string) string {
+	switch p.format {
+	case LogFormatPretty:
+		return p.prettyLine(line)
+	case LogFormatJSON:
+		return jsonLogLine(line)
+	default:
+		return line
+	}
+}
+
+// prettyLine renders an entry as its time, level and message in aligned columns,
+// followed by its fields. Lines which aren't entries are left as they are.
+func (p *logPrinter) prettyLine(line string) string {
+	entry, ok := ParseLogLine(line)
+	if !ok {
+		return line
+	}
+
+	var (
+		b           strings.Builder
+		color       = p.levelColor(entry.Level)
+		reset       = ""
+		timestamp   = strings.Repeat(" ", len(prettyTimeFormat))
+		levelPrefix = strings.ToUpper(entry.Level)
+	)
+
+	if color != "" {
+		reset = tui.TerminalColorReset
+	}
+
+	if !entry.Time.IsZero() {
+		timestamp = entry.Time.In(p.location).Format(prettyTimeFormat)
+	}
+
+	// Levels are shortened to four characters, as logrus does, eg: "WARN".
+	if len(levelPrefix) > 4 {
+		levelPrefix = levelPrefix[:4]
+	}
+
+	fmt.Fprintf(&b, "%s %s%-4s%s %-*s", timestamp, color, levelPrefix, reset, prettyMessageWidth, entry.Message)
+
+	for _, field := range entry.Fields {
+		fmt.Fprintf(&b, " %s%s%s=%s", color, field.Key, reset, quoteLogValue(field.Value))
+	}
+
+	return strings.TrimRight(b.String(), " ")
+}
+
+// levelColor returns the colour entries at level are shown in, if colour is on.
+func (p *logPrinter) levelColor(level string) string {
+	if !p.color {
+		return ""
+	}
+
+	switch level {
+	case "panic", "fatal", "error":
+		return tui.TerminalColorRed
+	case "warn", "warning":
+		return tui.TerminalColorYellow
+	case "info":
+		return tui.TerminalColorLightBlue
+	default:
+		return tui.TerminalColorGray
+	}
+}
+
+// quoteLogValue quotes a field value if it wouldn't otherwise read back as one.
+func quoteLogValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\"=") || strconv.Quote(value) != `"`+value+`"` {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+// jsonLogLine renders line as a JSON object with the entry's time, level, msg and
+// fields, in that order. Lines which aren't entries only have a msg.
+func jsonLogLine(line string) string {
+	entry, ok := ParseLogLine(line)
+	if !ok {
+		entry = &LogEntry{Message: line}
+	}
+
+	var b bytes.Buffer
+
+	b.WriteByte('{')
+
+	add := func(key, value string) {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+
+		// Marshalling a string can't fail.
+		encodedKey, _ := json.Marshal(key)
+		encodedValue, _ := json.Marshal(value)
+
+		b.Write(encodedKey)
+		b.WriteByte(':')
+		b.Write(encodedValue)
+	}
+
+	if !entry.Time.IsZero() {
+		add("time", entry.Time.Format(time.RFC3339Nano))
+	}
+
+	if entry.Level != "" {
+		add("level", entry.Level)
+	}
+
+	add("msg", entry.Message)
+
+	for _, field := range entry.Fields {
+		add(field.Key, field.Value)
+	}
+
+	b.WriteByte('}')
+
+	return b.String()
+}
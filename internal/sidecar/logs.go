@@ -0,0 +1,376 @@
+package sidecar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogFormat is how log lines are shown.
+type LogFormat string
+
+const (
+	// LogFormatRaw shows lines as sentry wrote them.
+	LogFormatRaw LogFormat = "raw"
+	// LogFormatPretty shows entries aligned, with their level in colour.
+	LogFormatPretty LogFormat = "pretty"
+	// LogFormatJSON shows each line as a JSON object, eg: to pipe into jq.
+	LogFormatJSON LogFormat = "json"
+)
+
+// LogOptions selects the logs shown by a runner, and how they're shown.
+type LogOptions struct {
+	// Tail is the number of lines to show from the end of the logs, 0 for all of them.
+	// It counts lines before they're filtered by Level and Grep.
+	Tail int
+	// Follow streams new lines as they're written.
+	Follow bool
+	// Since only shows lines written at or after this time, if set.
+	Since time.Time
+	// Until only shows lines written before this time, if set.
+	Until time.Time
+	// Level only shows entries at this level or more severe, eg: "warn". Empty shows
+	// every level.
+	Level string
+	// Grep only shows entries matching this pattern, if set.
+	Grep *regexp.Regexp
+	// Format is how lines are shown, raw if empty.
+	Format LogFormat
+	// Color colours pretty output, eg: when it's written to a terminal.
+	Color bool
+	// Output receives both streams of the logs instead of stdout and stderr, eg: to
+	// scan them. Runners mustn't prompt for a password when it's set.
+	Output io.Writer
+}
+
+// outputs returns where the logs selected by o are written.
+func (o LogOptions) outputs() (stdout, stderr io.Writer) {
+	if o.Output != nil {
+		return o.Output, o.Output
+	}
+
+	return os.Stdout, os.Stderr
+}
+
+// LogEntry is a line of sentry's logs, as written by logrus in text or JSON format.
+type LogEntry struct {
+	// Time is when the entry was logged, zero if it doesn't say.
+	Time time.Time
+	// Level is the level the entry was logged at, eg: "info".
+	Level string
+	// Message is the log message.
+	Message string
+	// Fields are the remaining fields of the entry, in the order they were written.
+	Fields []LogField
+}
+
+// LogField is a field of a log entry.
+type LogField struct {
+	Key   string
+	Value string
+}
+
+// logfmtStart finds the start of logrus text output in a line, which may have a
+// prefix, eg: the timestamp and unit added by journalctl.
+var logfmtStart = regexp.MustCompile(`(^|\s)(time|level)=`)
+
+// ParseLogLine parses a line of logrus output. It returns false for lines which
+// aren't log entries, eg: the lines of a stack trace.
+func ParseLogLine(line string) (*LogEntry, bool) {
+	if i := strings.IndexByte(line, '{'); i >= 0 {
+		if entry, ok := parseJSONLogLine(line[i:]); ok {
+			return entry, true
+		}
+	}
+
+	loc := logfmtStart.FindStringIndex(line)
+	if loc == nil {
+		return nil, false
+	}
+
+	return parseLogfmtLine(strings.TrimLeft(line[loc[0]:], " \t"))
+}
+
+func parseJSONLogLine(line string) (*LogEntry, bool) {
+	var raw map[string]any
+
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, false
+	}
+
+	level, ok := raw["level"].(string)
+	if !ok {
+		return nil, false
+	}
+
+	entry := &LogEntry{Level: level}
+	entry.Message, _ = raw["msg"].(string)
+
+	if t, ok := raw["time"].(string); ok {
+		entry.Time, _ = time.Parse(time.RFC3339Nano, t)
+	}
+
+	// JSON objects are unordered, so the remaining fields are sorted by key.
+	keys := make([]string, 0, len(raw))
+
+	for key := range raw {
+		if key != "level" && key != "msg" && key != "time" {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		value, ok := raw[key].(string)
+		if !ok {
+			encoded, _ := json.Marshal(raw[key])
+			value = string(encoded)
+		}
+
+		entry.Fields = append(entry.Fields, LogField{Key: key, Value: value})
+	}
+
+	return entry, true
+}
+
+func parseLogfmtLine(line string) (*LogEntry, bool) {
+	var (
+		entry = &LogEntry{}
+		found bool
+	)
+
+	for line != "" {
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 || strings.ContainsAny(line[:eq], " \t") {
+			return nil, false
+		}
+
+		key := line[:eq]
+		line = line[eq+1:]
+
+		var value string
+
+		if strings.HasPrefix(line, `"`) {
+			quoted, err := strconv.QuotedPrefix(line)
+			if err != nil {
+				return nil, false
+			}
+
+			value, _ = strconv.Unquote(quoted)
+			line = line[len(quoted):]
+		} else {
+			end := strings.IndexAny(line, " \t")
+			if end < 0 {
+				end = len(line)
+			}
+
+			value = line[:end]
+			line = line[end:]
+		}
+
+		line = strings.TrimLeft(line, " \t")
+
+		switch key {
+		case "time":
+			entry.Time, _ = time.Parse(time.RFC3339Nano, value)
+		case "level":
+			entry.Level = value
+			found = true
+		case "msg":
+			entry.Message = value
+		default:
+			entry.Fields = append(entry.Fields, LogField{Key: key, Value: value})
+		}
+	}
+
+	return entry, found
+}
+
+// logFilter decides which log lines to show. Lines which aren't log entries, eg: a
+// stack trace, belong to the entry before them, so they're shown along with it.
+type logFilter struct {
+	since, until time.Time
+	level        logrus.Level
+	hasLevel     bool
+	grep         *regexp.Regexp
+
+	// inWindow and matched are the decisions for the last entry.
+	inWindow bool
+	matched  bool
+}
+
+// newLogFilter returns a filter for the lines selected by opts.
+func newLogFilter(opts LogOptions) (*logFilter, error) {
+	f := &logFilter{
+		since:    opts.Since,
+		until:    opts.Until,
+		grep:     opts.Grep,
+		inWindow: opts.Since.IsZero(),
+		matched:  opts.Level == "" && opts.Grep == nil,
+	}
+
+	if opts.Level != "" {
+		level, err := logrus.ParseLevel(opts.Level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level %q", opts.Level)
+		}
+
+		f.level, f.hasLevel = level, true
+	}
+
+	return f, nil
+}
+
+// filtersEntries returns true if the filter selects lines by level or pattern.
+func (f *logFilter) filtersEntries() bool {
+	return f.hasLevel || f.grep != nil
+}
+
+// withinWindow returns true if line was written between since and until. Lines which
+// don't say when they were written are taken to be from the time of the last entry.
+func (f *logFilter) withinWindow(line string) bool {
+	if f.since.IsZero() && f.until.IsZero() {
+		return true
+	}
+
+	entry, ok := ParseLogLine(line)
+	if !ok || entry.Time.IsZero() {
+		return f.inWindow
+	}
+
+	f.inWindow = !entry.Time.Before(f.since) && (f.until.IsZero() || entry.Time.Before(f.until))
+
+	return f.inWindow
+}
+
+// match returns true if line is an entry at the selected level matching the
+// pattern, or belongs to one.
+func (f *logFilter) match(line string) bool {
+	if !f.filtersEntries() {
+		return true
+	}
+
+	entry, ok := ParseLogLine(line)
+	if !ok {
+		return f.matched
+	}
+
+	f.matched = true
+
+	if f.hasLevel {
+		level, err := logrus.ParseLevel(entry.Level)
+		f.matched = err == nil && level <= f.level
+	}
+
+	if f.matched && f.grep != nil {
+		f.matched = f.grep.MatchString(line)
+	}
+
+	return f.matched
+}
+
+// logPrinter filters and formats the lines of a log stream. Each stream needs its
+// own printer, as lines which aren't entries belong to the entry before them in
+// the same stream.
+type logPrinter struct {
+	filter   *logFilter
+	format   LogFormat
+	color    bool
+	location *time.Location
+}
+
+// newLogPrinter returns a printer for the lines selected by opts.
+func newLogPrinter(opts LogOptions) (*logPrinter, error) {
+	switch opts.Format {
+	case "", LogFormatRaw, LogFormatPretty, LogFormatJSON:
+	default:
+		return nil, fmt.Errorf("invalid log format %q", opts.Format)
+	}
+
+	filter, err := newLogFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logPrinter{
+		filter:   filter,
+		format:   opts.Format,
+		color:    opts.Color,
+		location: time.Local,
+	}, nil
+}
+
+// rewrites returns true if lines are filtered by level or pattern, or reformatted,
+// so they can't be copied straight to the output.
+func (p *logPrinter) rewrites() bool {
+	return p.filter.filtersEntries() || (p.format != "" && p.format != LogFormatRaw)
+}
+
+// print writes line to w in the printer's format, if it's selected.
+func (p *logPrinter) print(w io.Writer, line string) error {
+	if !p.filter.match(line) {
+		return nil
+	}
+
+	_, err := io.WriteString(w, p.formatLine(line)+"\n")
+
+	return err
+}
+
+// logWriter is an io.Writer passing the lines written to it through a printer, for
+// runners which read their logs from a stream.
+type logWriter struct {
+	out     io.Writer
+	printer *logPrinter
+	partial []byte
+}
+
+func newLogWriter(out io.Writer, printer *logPrinter) *logWriter {
+	return &logWriter{out: out, printer: printer}
+}
+
+// Write implements io.Writer. Incomplete lines are held until they're finished.
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+
+		line := strings.TrimSuffix(string(w.partial[:i]), "\r")
+		w.partial = w.partial[i+1:]
+
+		if err := w.printer.print(w.out, line); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes out the last line, if it wasn't terminated.
+func (w *logWriter) Flush() error {
+	if len(w.partial) == 0 {
+		return nil
+	}
+
+	line := string(w.partial)
+	w.partial = nil
+
+	return w.printer.print(w.out, line)
+}
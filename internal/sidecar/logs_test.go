@@ -0,0 +1,252 @@
+package sidecar
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogLine(t *testing.T) {
+	logged := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		line     string
+		expected *LogEntry
+	}{
+		{
+			name: "logfmt",
+			line: `time="2024-01-02T15:04:05Z" level=warning msg="peer dropped" peer=abc reason="too many peers"`,
+			expected: &LogEntry{
+				Time:    logged,
+				Level:   "warning",
+				Message: "peer dropped",
+				Fields:  []LogField{{Key: "peer", Value: "abc"}, {Key: "reason", Value: "too many peers"}},
+			},
+		},
+		{
+			name: "logfmt with a journal prefix",
+			line: `Jan 02 15:04:05 host sentry[123]: time="2024-01-02T15:04:05Z" level=info msg=started`,
+			expected: &LogEntry{
+				Time:    logged,
+				Level:   "info",
+				Message: "started",
+			},
+		},
+		{
+			name: "json",
+			line: `{"time":"2024-01-02T15:04:05Z","level":"error","msg":"failed","slot":123,"module":"sentry"}`,
+			expected: &LogEntry{
+				Time:    logged,
+				Level:   "error",
+				Message: "failed",
+				Fields:  []LogField{{Key: "module", Value: "sentry"}, {Key: "slot", Value: "123"}},
+			},
+		},
+		{
+			name: "json with a journal prefix",
+			line: `Jan 02 15:04:05 host sentry[123]: {"level":"debug","msg":"tick"}`,
+			expected: &LogEntry{
+				Level:   "debug",
+				Message: "tick",
+			},
+		},
+		{
+			name: "logfmt message with braces",
+			line: `level=info msg="config {a b}"`,
+			expected: &LogEntry{
+				Level:   "info",
+				Message: "config {a b}",
+			},
+		},
+		{
+			name: "stack trace",
+			line: "\tgithub.com/ethpandaops/contributoor/pkg/sentry.(*Sentry).Start(0xc000123)",
+		},
+		{
+			name: "plain text",
+			line: "panic: runtime error: invalid memory address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := ParseLogLine(tt.line)
+			if tt.expected == nil {
+				assert.False(t, ok)
+
+				return
+			}
+
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, entry)
+		})
+	}
+}
+
+func TestLogFilter(t *testing.T) {
+	lines := []string{
+		`time="2024-01-02T10:00:00Z" level=info msg="started"`,
+		`time="2024-01-02T11:00:00Z" level=error msg="peer dropped" peer=abc`,
+		"goroutine 1 [running]:",
+		`time="2024-01-02T12:00:00Z" level=warning msg="slow peer" peer=def`,
+		`time="2024-01-02T13:00:00Z" level=debug msg="peer dropped" peer=ghi`,
+	}
+
+	tests := []struct {
+		name     string
+		opts     LogOptions
+		expected []int
+	}{
+		{
+			name:     "no filter",
+			expected: []int{0, 1, 2, 3, 4},
+		},
+		{
+			name:     "since",
+			opts:     LogOptions{Since: time.Date(2024, 1, 2, 11, 0, 0, 0, time.UTC)},
+			expected: []int{1, 2, 3, 4},
+		},
+		{
+			name: "since and until",
+			opts: LogOptions{
+				Since: time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC),
+				Until: time.Date(2024, 1, 2, 13, 0, 0, 0, time.UTC),
+			},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "level",
+			opts:     LogOptions{Level: "warn"},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "grep",
+			opts:     LogOptions{Grep: regexp.MustCompile(`peer dropped`)},
+			expected: []int{1, 2, 4},
+		},
+		{
+			name:     "level and grep",
+			opts:     LogOptions{Level: "error", Grep: regexp.MustCompile(`peer=`)},
+			expected: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newLogFilter(tt.opts)
+			require.NoError(t, err)
+
+			var shown []int
+
+			for i, line := range lines {
+				if filter.withinWindow(line) && filter.match(line) {
+					shown = append(shown, i)
+				}
+			}
+
+			assert.Equal(t, tt.expected, shown)
+		})
+	}
+
+	_, err := newLogFilter(LogOptions{Level: "loud"})
+	assert.ErrorContains(t, err, `invalid log level "loud"`)
+}
+
+func TestLogWriter(t *testing.T) {
+	printer, err := newLogPrinter(LogOptions{Level: "warn"})
+	require.NoError(t, err)
+
+	var (
+		out    bytes.Buffer
+		writer = newLogWriter(&out, printer)
+	)
+
+	// Lines may be split across writes.
+	for _, chunk := range []string{
+		"level=info msg=started\nlevel=err",
+		"or msg=failed\n\tstack frame\n",
+		"level=debug msg=tick\nlevel=warning msg=unterminated",
+	} {
+		n, err := writer.Write([]byte(chunk))
+		require.NoError(t, err)
+		assert.Equal(t, len(chunk), n)
+	}
+
+	assert.Equal(t, "level=error msg=failed\n\tstack frame\n", out.String())
+
+	require.NoError(t, writer.Flush())
+	assert.Equal(t, "level=error msg=failed\n\tstack frame\nlevel=warning msg=unterminated\n", out.String())
+}
+
+func TestLogPrinter(t *testing.T) {
+	lines := []string{
+		`time="2024-01-02T15:04:05.123Z" level=warning msg="slow peer" peer=abc reason="too many peers"`,
+		`Jan 02 15:04:05 host sentry[123]: {"time":"2024-01-02T15:04:06Z","level":"info","msg":"event sent","slot":123}`,
+		"goroutine 1 [running]:",
+	}
+
+	tests := []struct {
+		name     string
+		opts     LogOptions
+		expected []string
+	}{
+		{
+			name:     "raw",
+			opts:     LogOptions{Format: LogFormatRaw},
+			expected: lines,
+		},
+		{
+			name: "pretty",
+			opts: LogOptions{Format: LogFormatPretty},
+			expected: []string{
+				`2024-01-02 15:04:05.123 WARN slow peer                                    peer=abc reason="too many peers"`,
+				`2024-01-02 15:04:06.000 INFO event sent                                   slot=123`,
+				"goroutine 1 [running]:",
+			},
+		},
+		{
+			// The stack trace belongs to the info entry, so it's filtered out with it.
+			name: "pretty with colour",
+			opts: LogOptions{Format: LogFormatPretty, Color: true, Level: "warn"},
+			expected: []string{
+				"2024-01-02 15:04:05.123 \033[33mWARN\033[0m slow peer                                    " +
+					"\033[33mpeer\033[0m=abc \033[33mreason\033[0m=\"too many peers\"",
+			},
+		},
+		{
+			name: "json",
+			opts: LogOptions{Format: LogFormatJSON},
+			expected: []string{
+				`{"time":"2024-01-02T15:04:05.123Z","level":"warning","msg":"slow peer","peer":"abc","reason":"too many peers"}`,
+				`{"time":"2024-01-02T15:04:06Z","level":"info","msg":"event sent","slot":"123"}`,
+				`{"msg":"goroutine 1 [running]:"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printer, err := newLogPrinter(tt.opts)
+			require.NoError(t, err)
+
+			printer.location = time.UTC
+
+			var out bytes.Buffer
+
+			for _, line := range lines {
+				require.NoError(t, printer.print(&out, line))
+			}
+
+			assert.Equal(t, strings.Join(tt.expected, "\n")+"\n", out.String())
+		})
+	}
+
+	_, err := newLogPrinter(LogOptions{Format: "yaml"})
+	assert.ErrorContains(t, err, `invalid log format "yaml"`)
+}
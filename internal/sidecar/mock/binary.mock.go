@@ -10,8 +10,10 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
+	sidecar "github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,102 +42,130 @@ func (m *MockBinarySidecar) EXPECT() *MockBinarySidecarMockRecorder {
 }
 
 // IsRunning mocks base method.
-func (m *MockBinarySidecar) IsRunning() (bool, error) {
+func (m *MockBinarySidecar) IsRunning(ctx context.Context) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsRunning")
+	ret := m.ctrl.Call(m, "IsRunning", ctx)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // IsRunning indicates an expected call of IsRunning.
-func (mr *MockBinarySidecarMockRecorder) IsRunning() *gomock.Call {
+func (mr *MockBinarySidecarMockRecorder) IsRunning(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRunning", reflect.TypeOf((*MockBinarySidecar)(nil).IsRunning))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRunning", reflect.TypeOf((*MockBinarySidecar)(nil).IsRunning), ctx)
 }
 
 // Logs mocks base method.
-func (m *MockBinarySidecar) Logs(tailLines int, follow bool) error {
+func (m *MockBinarySidecar) Logs(ctx context.Context, opts sidecar.LogOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Logs", tailLines, follow)
+	ret := m.ctrl.Call(m, "Logs", ctx, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Logs indicates an expected call of Logs.
-func (mr *MockBinarySidecarMockRecorder) Logs(tailLines, follow any) *gomock.Call {
+func (mr *MockBinarySidecarMockRecorder) Logs(ctx, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockBinarySidecar)(nil).Logs), tailLines, follow)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockBinarySidecar)(nil).Logs), ctx, opts)
+}
+
+// Provision mocks base method.
+func (m *MockBinarySidecar) Provision(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Provision", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Provision indicates an expected call of Provision.
+func (mr *MockBinarySidecarMockRecorder) Provision(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Provision", reflect.TypeOf((*MockBinarySidecar)(nil).Provision), ctx)
 }
 
 // Start mocks base method.
-func (m *MockBinarySidecar) Start() error {
+func (m *MockBinarySidecar) Start(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Start")
+	ret := m.ctrl.Call(m, "Start", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Start indicates an expected call of Start.
-func (mr *MockBinarySidecarMockRecorder) Start() *gomock.Call {
+func (mr *MockBinarySidecarMockRecorder) Start(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockBinarySidecar)(nil).Start))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockBinarySidecar)(nil).Start), ctx)
 }
 
 // Status mocks base method.
-func (m *MockBinarySidecar) Status() (string, error) {
+func (m *MockBinarySidecar) Status(ctx context.Context) (*sidecar.Status, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Status")
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Status", ctx)
+	ret0, _ := ret[0].(*sidecar.Status)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Status indicates an expected call of Status.
-func (mr *MockBinarySidecarMockRecorder) Status() *gomock.Call {
+func (mr *MockBinarySidecarMockRecorder) Status(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockBinarySidecar)(nil).Status))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockBinarySidecar)(nil).Status), ctx)
 }
 
 // Stop mocks base method.
-func (m *MockBinarySidecar) Stop() error {
+func (m *MockBinarySidecar) Stop(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Stop")
+	ret := m.ctrl.Call(m, "Stop", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Stop indicates an expected call of Stop.
-func (mr *MockBinarySidecarMockRecorder) Stop() *gomock.Call {
+func (mr *MockBinarySidecarMockRecorder) Stop(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockBinarySidecar)(nil).Stop), ctx)
+}
+
+// UninstallSteps mocks base method.
+func (m *MockBinarySidecar) UninstallSteps() []sidecar.UninstallStep {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallSteps")
+	ret0, _ := ret[0].([]sidecar.UninstallStep)
+	return ret0
+}
+
+// UninstallSteps indicates an expected call of UninstallSteps.
+func (mr *MockBinarySidecarMockRecorder) UninstallSteps() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockBinarySidecar)(nil).Stop))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallSteps", reflect.TypeOf((*MockBinarySidecar)(nil).UninstallSteps))
 }
 
 // Update mocks base method.
-func (m *MockBinarySidecar) Update() error {
+func (m *MockBinarySidecar) Update(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update")
+	ret := m.ctrl.Call(m, "Update", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockBinarySidecarMockRecorder) Update() *gomock.Call {
+func (mr *MockBinarySidecarMockRecorder) Update(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBinarySidecar)(nil).Update))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBinarySidecar)(nil).Update), ctx)
 }
 
 // Version mocks base method.
-func (m *MockBinarySidecar) Version() (string, error) {
+func (m *MockBinarySidecar) Version(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Version")
+	ret := m.ctrl.Call(m, "Version", ctx)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Version indicates an expected call of Version.
-func (mr *MockBinarySidecarMockRecorder) Version() *gomock.Call {
+func (mr *MockBinarySidecarMockRecorder) Version(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockBinarySidecar)(nil).Version))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockBinarySidecar)(nil).Version), ctx)
 }
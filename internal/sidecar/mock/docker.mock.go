@@ -10,8 +10,10 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
+	sidecar "github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,117 +41,146 @@ func (m *MockDockerSidecar) EXPECT() *MockDockerSidecarMockRecorder {
 	return m.recorder
 }
 
-// GetComposeEnv mocks base method.
-func (m *MockDockerSidecar) GetComposeEnv() []string {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetComposeEnv")
-	ret0, _ := ret[0].([]string)
-	return ret0
-}
-
-// GetComposeEnv indicates an expected call of GetComposeEnv.
-func (mr *MockDockerSidecarMockRecorder) GetComposeEnv() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComposeEnv", reflect.TypeOf((*MockDockerSidecar)(nil).GetComposeEnv))
-}
-
 // IsRunning mocks base method.
-func (m *MockDockerSidecar) IsRunning() (bool, error) {
+func (m *MockDockerSidecar) IsRunning(ctx context.Context) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsRunning")
+	ret := m.ctrl.Call(m, "IsRunning", ctx)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // IsRunning indicates an expected call of IsRunning.
-func (mr *MockDockerSidecarMockRecorder) IsRunning() *gomock.Call {
+func (mr *MockDockerSidecarMockRecorder) IsRunning(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRunning", reflect.TypeOf((*MockDockerSidecar)(nil).IsRunning))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRunning", reflect.TypeOf((*MockDockerSidecar)(nil).IsRunning), ctx)
 }
 
 // Logs mocks base method.
-func (m *MockDockerSidecar) Logs(tailLines int, follow bool) error {
+func (m *MockDockerSidecar) Logs(ctx context.Context, opts sidecar.LogOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Logs", tailLines, follow)
+	ret := m.ctrl.Call(m, "Logs", ctx, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Logs indicates an expected call of Logs.
-func (mr *MockDockerSidecarMockRecorder) Logs(tailLines, follow any) *gomock.Call {
+func (mr *MockDockerSidecarMockRecorder) Logs(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockDockerSidecar)(nil).Logs), ctx, opts)
+}
+
+// Provision mocks base method.
+func (m *MockDockerSidecar) Provision(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Provision", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Provision indicates an expected call of Provision.
+func (mr *MockDockerSidecarMockRecorder) Provision(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockDockerSidecar)(nil).Logs), tailLines, follow)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Provision", reflect.TypeOf((*MockDockerSidecar)(nil).Provision), ctx)
+}
+
+// RenderCompose mocks base method.
+func (m *MockDockerSidecar) RenderCompose() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenderCompose")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenderCompose indicates an expected call of RenderCompose.
+func (mr *MockDockerSidecarMockRecorder) RenderCompose() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderCompose", reflect.TypeOf((*MockDockerSidecar)(nil).RenderCompose))
 }
 
 // Start mocks base method.
-func (m *MockDockerSidecar) Start() error {
+func (m *MockDockerSidecar) Start(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Start")
+	ret := m.ctrl.Call(m, "Start", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Start indicates an expected call of Start.
-func (mr *MockDockerSidecarMockRecorder) Start() *gomock.Call {
+func (mr *MockDockerSidecarMockRecorder) Start(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockDockerSidecar)(nil).Start))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockDockerSidecar)(nil).Start), ctx)
 }
 
 // Status mocks base method.
-func (m *MockDockerSidecar) Status() (string, error) {
+func (m *MockDockerSidecar) Status(ctx context.Context) (*sidecar.Status, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Status")
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Status", ctx)
+	ret0, _ := ret[0].(*sidecar.Status)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Status indicates an expected call of Status.
-func (mr *MockDockerSidecarMockRecorder) Status() *gomock.Call {
+func (mr *MockDockerSidecarMockRecorder) Status(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockDockerSidecar)(nil).Status))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockDockerSidecar)(nil).Status), ctx)
 }
 
 // Stop mocks base method.
-func (m *MockDockerSidecar) Stop() error {
+func (m *MockDockerSidecar) Stop(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Stop")
+	ret := m.ctrl.Call(m, "Stop", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Stop indicates an expected call of Stop.
-func (mr *MockDockerSidecarMockRecorder) Stop() *gomock.Call {
+func (mr *MockDockerSidecarMockRecorder) Stop(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockDockerSidecar)(nil).Stop), ctx)
+}
+
+// UninstallSteps mocks base method.
+func (m *MockDockerSidecar) UninstallSteps() []sidecar.UninstallStep {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallSteps")
+	ret0, _ := ret[0].([]sidecar.UninstallStep)
+	return ret0
+}
+
+// UninstallSteps indicates an expected call of UninstallSteps.
+func (mr *MockDockerSidecarMockRecorder) UninstallSteps() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockDockerSidecar)(nil).Stop))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallSteps", reflect.TypeOf((*MockDockerSidecar)(nil).UninstallSteps))
 }
 
 // Update mocks base method.
-func (m *MockDockerSidecar) Update() error {
+func (m *MockDockerSidecar) Update(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update")
+	ret := m.ctrl.Call(m, "Update", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockDockerSidecarMockRecorder) Update() *gomock.Call {
+func (mr *MockDockerSidecarMockRecorder) Update(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDockerSidecar)(nil).Update))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDockerSidecar)(nil).Update), ctx)
 }
 
 // Version mocks base method.
-func (m *MockDockerSidecar) Version() (string, error) {
+func (m *MockDockerSidecar) Version(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Version")
+	ret := m.ctrl.Call(m, "Version", ctx)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Version indicates an expected call of Version.
-func (mr *MockDockerSidecarMockRecorder) Version() *gomock.Call {
+func (mr *MockDockerSidecarMockRecorder) Version(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockDockerSidecar)(nil).Version))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockDockerSidecar)(nil).Version), ctx)
 }
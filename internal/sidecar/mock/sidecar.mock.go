@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ethpandaops/contributoor-installer/internal/sidecar (interfaces: SidecarRunner)
+//
+// Generated by this command:
+//
+//	mockgen -package mock -destination mock/sidecar.mock.go github.com/ethpandaops/contributoor-installer/internal/sidecar SidecarRunner
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sidecar "github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSidecarRunner is a mock of SidecarRunner interface.
+type MockSidecarRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockSidecarRunnerMockRecorder
+	isgomock struct{}
+}
+
+// MockSidecarRunnerMockRecorder is the mock recorder for MockSidecarRunner.
+type MockSidecarRunnerMockRecorder struct {
+	mock *MockSidecarRunner
+}
+
+// NewMockSidecarRunner creates a new mock instance.
+func NewMockSidecarRunner(ctrl *gomock.Controller) *MockSidecarRunner {
+	mock := &MockSidecarRunner{ctrl: ctrl}
+	mock.recorder = &MockSidecarRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSidecarRunner) EXPECT() *MockSidecarRunnerMockRecorder {
+	return m.recorder
+}
+
+// IsRunning mocks base method.
+func (m *MockSidecarRunner) IsRunning(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRunning", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRunning indicates an expected call of IsRunning.
+func (mr *MockSidecarRunnerMockRecorder) IsRunning(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRunning", reflect.TypeOf((*MockSidecarRunner)(nil).IsRunning), ctx)
+}
+
+// Logs mocks base method.
+func (m *MockSidecarRunner) Logs(ctx context.Context, opts sidecar.LogOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logs", ctx, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logs indicates an expected call of Logs.
+func (mr *MockSidecarRunnerMockRecorder) Logs(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockSidecarRunner)(nil).Logs), ctx, opts)
+}
+
+// Provision mocks base method.
+func (m *MockSidecarRunner) Provision(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Provision", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Provision indicates an expected call of Provision.
+func (mr *MockSidecarRunnerMockRecorder) Provision(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Provision", reflect.TypeOf((*MockSidecarRunner)(nil).Provision), ctx)
+}
+
+// Start mocks base method.
+func (m *MockSidecarRunner) Start(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockSidecarRunnerMockRecorder) Start(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockSidecarRunner)(nil).Start), ctx)
+}
+
+// Status mocks base method.
+func (m *MockSidecarRunner) Status(ctx context.Context) (*sidecar.Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx)
+	ret0, _ := ret[0].(*sidecar.Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockSidecarRunnerMockRecorder) Status(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockSidecarRunner)(nil).Status), ctx)
+}
+
+// Stop mocks base method.
+func (m *MockSidecarRunner) Stop(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockSidecarRunnerMockRecorder) Stop(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockSidecarRunner)(nil).Stop), ctx)
+}
+
+// UninstallSteps mocks base method.
+func (m *MockSidecarRunner) UninstallSteps() []sidecar.UninstallStep {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallSteps")
+	ret0, _ := ret[0].([]sidecar.UninstallStep)
+	return ret0
+}
+
+// UninstallSteps indicates an expected call of UninstallSteps.
+func (mr *MockSidecarRunnerMockRecorder) UninstallSteps() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallSteps", reflect.TypeOf((*MockSidecarRunner)(nil).UninstallSteps))
+}
+
+// Update mocks base method.
+func (m *MockSidecarRunner) Update(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSidecarRunnerMockRecorder) Update(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSidecarRunner)(nil).Update), ctx)
+}
+
+// Version mocks base method.
+func (m *MockSidecarRunner) Version(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockSidecarRunnerMockRecorder) Version(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockSidecarRunner)(nil).Version), ctx)
+}
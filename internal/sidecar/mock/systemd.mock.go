@@ -10,8 +10,10 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
 
+	sidecar "github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,103 +41,189 @@ func (m *MockSystemdSidecar) EXPECT() *MockSystemdSidecarMockRecorder {
 	return m.recorder
 }
 
+// CheckInstalled mocks base method.
+func (m *MockSystemdSidecar) CheckInstalled(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckInstalled", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckInstalled indicates an expected call of CheckInstalled.
+func (mr *MockSystemdSidecarMockRecorder) CheckInstalled(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInstalled", reflect.TypeOf((*MockSystemdSidecar)(nil).CheckInstalled), ctx)
+}
+
+// InstallUnit mocks base method.
+func (m *MockSystemdSidecar) InstallUnit(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallUnit", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallUnit indicates an expected call of InstallUnit.
+func (mr *MockSystemdSidecarMockRecorder) InstallUnit(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallUnit", reflect.TypeOf((*MockSystemdSidecar)(nil).InstallUnit), ctx)
+}
+
 // IsRunning mocks base method.
-func (m *MockSystemdSidecar) IsRunning() (bool, error) {
+func (m *MockSystemdSidecar) IsRunning(ctx context.Context) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsRunning")
+	ret := m.ctrl.Call(m, "IsRunning", ctx)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // IsRunning indicates an expected call of IsRunning.
-func (mr *MockSystemdSidecarMockRecorder) IsRunning() *gomock.Call {
+func (mr *MockSystemdSidecarMockRecorder) IsRunning(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRunning", reflect.TypeOf((*MockSystemdSidecar)(nil).IsRunning))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRunning", reflect.TypeOf((*MockSystemdSidecar)(nil).IsRunning), ctx)
 }
 
 // Logs mocks base method.
-func (m *MockSystemdSidecar) Logs(tailLines int, follow bool) error {
+func (m *MockSystemdSidecar) Logs(ctx context.Context, opts sidecar.LogOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Logs", tailLines, follow)
+	ret := m.ctrl.Call(m, "Logs", ctx, opts)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Logs indicates an expected call of Logs.
-func (mr *MockSystemdSidecarMockRecorder) Logs(tailLines, follow any) *gomock.Call {
+func (mr *MockSystemdSidecarMockRecorder) Logs(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockSystemdSidecar)(nil).Logs), ctx, opts)
+}
+
+// Provision mocks base method.
+func (m *MockSystemdSidecar) Provision(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Provision", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Provision indicates an expected call of Provision.
+func (mr *MockSystemdSidecarMockRecorder) Provision(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockSystemdSidecar)(nil).Logs), tailLines, follow)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Provision", reflect.TypeOf((*MockSystemdSidecar)(nil).Provision), ctx)
+}
+
+// RenderUnit mocks base method.
+func (m *MockSystemdSidecar) RenderUnit() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenderUnit")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenderUnit indicates an expected call of RenderUnit.
+func (mr *MockSystemdSidecarMockRecorder) RenderUnit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderUnit", reflect.TypeOf((*MockSystemdSidecar)(nil).RenderUnit))
 }
 
 // Start mocks base method.
-func (m *MockSystemdSidecar) Start() error {
+func (m *MockSystemdSidecar) Start(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Start")
+	ret := m.ctrl.Call(m, "Start", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Start indicates an expected call of Start.
-func (mr *MockSystemdSidecarMockRecorder) Start() *gomock.Call {
+func (mr *MockSystemdSidecarMockRecorder) Start(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockSystemdSidecar)(nil).Start))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockSystemdSidecar)(nil).Start), ctx)
 }
 
 // Status mocks base method.
-func (m *MockSystemdSidecar) Status() (string, error) {
+func (m *MockSystemdSidecar) Status(ctx context.Context) (*sidecar.Status, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Status")
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Status", ctx)
+	ret0, _ := ret[0].(*sidecar.Status)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Status indicates an expected call of Status.
-func (mr *MockSystemdSidecarMockRecorder) Status() *gomock.Call {
+func (mr *MockSystemdSidecarMockRecorder) Status(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockSystemdSidecar)(nil).Status))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockSystemdSidecar)(nil).Status), ctx)
 }
 
 // Stop mocks base method.
-func (m *MockSystemdSidecar) Stop() error {
+func (m *MockSystemdSidecar) Stop(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Stop")
+	ret := m.ctrl.Call(m, "Stop", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Stop indicates an expected call of Stop.
-func (mr *MockSystemdSidecarMockRecorder) Stop() *gomock.Call {
+func (mr *MockSystemdSidecarMockRecorder) Stop(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockSystemdSidecar)(nil).Stop), ctx)
+}
+
+// UninstallSteps mocks base method.
+func (m *MockSystemdSidecar) UninstallSteps() []sidecar.UninstallStep {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallSteps")
+	ret0, _ := ret[0].([]sidecar.UninstallStep)
+	return ret0
+}
+
+// UninstallSteps indicates an expected call of UninstallSteps.
+func (mr *MockSystemdSidecarMockRecorder) UninstallSteps() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallSteps", reflect.TypeOf((*MockSystemdSidecar)(nil).UninstallSteps))
+}
+
+// UnitDrift mocks base method.
+func (m *MockSystemdSidecar) UnitDrift() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnitDrift")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnitDrift indicates an expected call of UnitDrift.
+func (mr *MockSystemdSidecarMockRecorder) UnitDrift() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockSystemdSidecar)(nil).Stop))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnitDrift", reflect.TypeOf((*MockSystemdSidecar)(nil).UnitDrift))
 }
 
 // Update mocks base method.
-func (m *MockSystemdSidecar) Update() error {
+func (m *MockSystemdSidecar) Update(ctx context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update")
+	ret := m.ctrl.Call(m, "Update", ctx)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockSystemdSidecarMockRecorder) Update() *gomock.Call {
+func (mr *MockSystemdSidecarMockRecorder) Update(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSystemdSidecar)(nil).Update))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSystemdSidecar)(nil).Update), ctx)
 }
 
 // Version mocks base method.
-func (m *MockSystemdSidecar) Version() (string, error) {
+func (m *MockSystemdSidecar) Version(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Version")
+	ret := m.ctrl.Call(m, "Version", ctx)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Version indicates an expected call of Version.
-func (mr *MockSystemdSidecarMockRecorder) Version() *gomock.Call {
+func (mr *MockSystemdSidecarMockRecorder) Version(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockSystemdSidecar)(nil).Version))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockSystemdSidecar)(nil).Version), ctx)
 }
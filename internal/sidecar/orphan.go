@@ -0,0 +1,111 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// Orphan is a sidecar running under a run method other than the configured one,
+// usually left behind by the run method being changed by hand.
+type Orphan struct {
+	// RunMode is the run method, and systemd scope, the sidecar is running under.
+	RunMode
+	// Runner is the runner for that run mode.
+	Runner SidecarRunner
+}
+
+// FindOrphans probes every registered run method other than the configured one for
+// a running sidecar, including systemd with the other scope. Run methods which can't
+// be probed, eg: because docker isn't installed, can't be running anything and are
+// skipped.
+func FindOrphans(
+	ctx context.Context,
+	logger *logrus.Logger,
+	sidecarCfg ConfigManager,
+	installerCfg *installer.Config,
+) []Orphan {
+	runnersMu.RLock()
+
+	methods := make([]config.RunMethod, 0, len(runners))
+	for method := range runners {
+		methods = append(methods, method)
+	}
+
+	runnersMu.RUnlock()
+
+	slices.Sort(methods)
+
+	var orphans []Orphan
+
+	current := RunMode{Method: sidecarCfg.Get().RunMethod, Scope: installerCfg.Systemd.Scope}
+
+	for _, candidate := range orphanCandidates(methods, current) {
+		name := candidate.Name()
+
+		cfg := *installerCfg
+		if candidate.Scope != "" {
+			cfg.Systemd.Scope = candidate.Scope
+		}
+
+		runner, err := NewRunner(candidate.Method, logger, sidecarCfg, &cfg)
+		if err != nil {
+			logger.Debugf("skipping orphan check for %s: %v", name, err)
+
+			continue
+		}
+
+		running, err := runner.IsRunning(ctx)
+		if err != nil {
+			logger.Debugf("skipping orphan check for %s: %v", name, err)
+
+			continue
+		}
+
+		if running {
+			candidate.Runner = runner
+			orphans = append(orphans, candidate)
+		}
+	}
+
+	return orphans
+}
+
+// orphanCandidates returns the run methods, along with both systemd scopes, which
+// aren't the configured run mode.
+func orphanCandidates(methods []config.RunMethod, current RunMode) []Orphan {
+	var candidates []Orphan
+
+	for _, method := range methods {
+		scopes := []string{""}
+		if method == config.RunMethod_RUN_METHOD_SYSTEMD {
+			scopes = []string{installer.SystemdScopeSystem, installer.SystemdScopeUser}
+		}
+
+		for _, scope := range scopes {
+			mode := RunMode{Method: method, Scope: scope}
+			if mode.Name() == current.Name() {
+				continue
+			}
+
+			candidates = append(candidates, Orphan{RunMode: mode})
+		}
+	}
+
+	return candidates
+}
+
+// OrphanCleanupSteps returns the steps to stop the orphan and remove its run method
+// specific artifacts, so it doesn't come back on the next boot.
+func OrphanCleanupSteps(orphan Orphan) []UninstallStep {
+	steps := []UninstallStep{{
+		Description: fmt.Sprintf("Stop the %s sidecar", orphan.Name()),
+		Run:         orphan.Runner.Stop,
+	}}
+
+	return append(steps, orphan.Runner.UninstallSteps()...)
+}
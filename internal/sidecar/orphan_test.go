@@ -0,0 +1,66 @@
+package sidecar
+
+import (
+	"testing"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrphanCandidates(t *testing.T) {
+	methods := []config.RunMethod{
+		config.RunMethod_RUN_METHOD_DOCKER,
+		config.RunMethod_RUN_METHOD_SYSTEMD,
+		config.RunMethod_RUN_METHOD_BINARY,
+	}
+
+	tests := []struct {
+		name     string
+		current  RunMode
+		expected []Orphan
+	}{
+		{
+			name:    "docker probes both systemd scopes",
+			current: RunMode{Method: config.RunMethod_RUN_METHOD_DOCKER, Scope: installer.SystemdScopeSystem},
+			expected: []Orphan{
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: installer.SystemdScopeSystem}},
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: installer.SystemdScopeUser}},
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_BINARY}},
+			},
+		},
+		{
+			name:    "systemd probes the user scope",
+			current: RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: installer.SystemdScopeSystem},
+			expected: []Orphan{
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_DOCKER}},
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: installer.SystemdScopeUser}},
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_BINARY}},
+			},
+		},
+		{
+			name:    "systemd-user probes the system scope",
+			current: RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: installer.SystemdScopeUser},
+			expected: []Orphan{
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_DOCKER}},
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: installer.SystemdScopeSystem}},
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_BINARY}},
+			},
+		},
+		{
+			name:    "an unset scope is the system scope",
+			current: RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD},
+			expected: []Orphan{
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_DOCKER}},
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: installer.SystemdScopeUser}},
+				{RunMode: RunMode{Method: config.RunMethod_RUN_METHOD_BINARY}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, orphanCandidates(methods, tt.current))
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/ethpandaops/contributoor-installer/internal/pidfile"
+)
+
+// processPollInterval is how often a stopping process is checked for having exited.
+const processPollInterval = 100 * time.Millisecond
+
+// isSupervisorCmdline returns true if cmdline is the supervisor of the binary sidecar
+// in dir. Named instances are supervised from the root config path with --instance,
+// or with their own config path by supervisors started before that.
+func isSupervisorCmdline(cmdline []string, dir string) bool {
+	configPath := cmdlineFlag(cmdline, "--config-path")
+
+	return slices.Contains(cmdline, "supervise") && configPath != "" &&
+		InstanceConfigPath(configPath, cmdlineFlag(cmdline, "--instance")) == dir
+}
+
+// cmdlineFlag returns the value following flag in cmdline, or "" if it isn't set.
+func cmdlineFlag(cmdline []string, flag string) string {
+	i := slices.Index(cmdline, flag)
+	if i < 0 || i+1 >= len(cmdline) {
+		return ""
+	}
+
+	return cmdline[i+1]
+}
+
+// isSentryCmdline returns true if cmdline is a sentry process, as written to the pid
+// file before sidecars were supervised.
+func isSentryCmdline(cmdline []string) bool {
+	return len(cmdline) > 0 && filepath.Base(cmdline[0]) == "sentry"
+}
+
+// IsBinarySidecarProcess returns true if cmdline is the binary sidecar in dir, ie:
+// its supervisor, or a sentry process started before sidecars were supervised.
+func IsBinarySidecarProcess(cmdline []string, dir string) bool {
+	return isSupervisorCmdline(cmdline, dir) || isSentryCmdline(cmdline)
+}
+
+// terminateProcess asks the process with the given pid to exit with SIGTERM, then
+// waits up to grace for it to exit before killing it with SIGKILL. If group is set,
+// the SIGKILL is sent to the process group led by pid, so nothing is left behind.
+func terminateProcess(ctx context.Context, pid int, grace time.Duration, group bool) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("failed to find process %d: %w", pid, err)
+	}
+
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		if errors.Is(err, os.ErrProcessDone) || errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to stop process %d: %w", pid, err)
+	}
+
+	if waitForExit(ctx, pid, grace) {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("stop cancelled while waiting for process %d to exit: %w", pid, err)
+	}
+
+	killPid := pid
+	if group {
+		killPid = -pid
+	}
+
+	if err := syscall.Kill(killPid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("failed to kill process %d: %w", pid, err)
+	}
+
+	// SIGKILL can't be ignored, but it can take a moment to be delivered.
+	if !waitForExit(context.WithoutCancel(ctx), pid, 5*time.Second) {
+		return fmt.Errorf("process %d is still running after being killed", pid)
+	}
+
+	return nil
+}
+
+// waitForExit polls until the process with the given pid has exited, returning
+// false if it's still running after timeout or ctx is cancelled.
+func waitForExit(ctx context.Context, pid int, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(processPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if !pidfile.Alive(pid) {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return !pidfile.Alive(pid)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package sidecar
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/contributoor-installer/internal/pidfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSidecarCmdline(t *testing.T) {
+	const dir = "/home/eth/.contributoor"
+
+	tests := []struct {
+		name               string
+		cmdline            []string
+		expectedSupervisor bool
+		expectedSentry     bool
+	}{
+		{
+			name:               "supervisor",
+			cmdline:            []string{"/usr/local/bin/contributoor", "--config-path", dir, "supervise"},
+			expectedSupervisor: true,
+		},
+		{
+			name:    "supervisor of another instance",
+			cmdline: []string{"/usr/local/bin/contributoor", "--config-path", dir + "/instances/holesky", "supervise"},
+		},
+		{
+			name:    "supervisor of another instance from the root config path",
+			cmdline: []string{"/usr/local/bin/contributoor", "--config-path", dir, "--instance", "holesky", "supervise"},
+		},
+		{
+			name:           "sentry",
+			cmdline:        []string{dir + "/bin/sentry", "--config", dir + "/config.yaml"},
+			expectedSentry: true,
+		},
+		{
+			name:    "recycled pid",
+			cmdline: []string{"/usr/bin/python3", "server.py"},
+		},
+		{
+			name:    "contributoor but not supervising",
+			cmdline: []string{"/usr/local/bin/contributoor", "--config-path", dir, "status"},
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedSupervisor, isSupervisorCmdline(tt.cmdline, dir))
+			assert.Equal(t, tt.expectedSentry, isSentryCmdline(tt.cmdline))
+		})
+	}
+}
+
+func TestSupervisorArgs(t *testing.T) {
+	const dir = "/home/eth/.contributoor"
+
+	args := supervisorArgs(dir, "")
+	assert.Equal(t, []string{"--config-path", dir, "supervise"}, args)
+	assert.True(t, isSupervisorCmdline(append([]string{"contributoor"}, args...), dir))
+
+	instanceDir := InstanceConfigPath(dir, "holesky")
+
+	args = supervisorArgs(instanceDir, "holesky")
+	assert.Equal(t, []string{"--config-path", dir, "--instance", "holesky", "supervise"}, args)
+	assert.True(t, isSupervisorCmdline(append([]string{"contributoor"}, args...), instanceDir))
+	assert.False(t, isSupervisorCmdline(append([]string{"contributoor"}, args...), dir))
+
+	// Supervisors started with the instance's own config path are still recognised.
+	assert.True(t, isSupervisorCmdline([]string{"contributoor", "--config-path", instanceDir, "supervise"}, instanceDir))
+}
+
+func TestTerminateProcess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	tests := []struct {
+		name        string
+		script      string
+		maxDuration time.Duration
+	}{
+		{
+			name:        "exits on SIGTERM",
+			script:      "trap 'exit 0' TERM; while true; do sleep 0.01; done",
+			maxDuration: time.Second,
+		},
+		{
+			name:        "killed after the grace period",
+			script:      "trap '' TERM; while true; do sleep 0.01; done",
+			maxDuration: 3 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command("sh", "-c", tt.script)
+			require.NoError(t, cmd.Start())
+
+			// Reap the process, as its parent would.
+			go func() {
+				_ = cmd.Wait()
+			}()
+
+			// Give the shell time to install its trap.
+			time.Sleep(100 * time.Millisecond)
+
+			start := time.Now()
+			require.NoError(t, terminateProcess(context.Background(), cmd.Process.Pid, 500*time.Millisecond, false))
+
+			assert.False(t, pidfile.Alive(cmd.Process.Pid))
+			assert.Less(t, time.Since(start), tt.maxDuration)
+		})
+	}
+}
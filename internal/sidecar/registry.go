@@ -0,0 +1,58 @@
+package sidecar
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// RunnerFactory constructs the SidecarRunner for a run method.
+type RunnerFactory func(logger *logrus.Logger, sidecarCfg ConfigManager, installerCfg *installer.Config) (SidecarRunner, error)
+
+var (
+	runnersMu sync.RWMutex
+	runners   = make(map[config.RunMethod]RunnerFactory)
+)
+
+// RegisterRunner registers the factory used to construct the runner for a run method,
+// replacing any existing registration. Backends register themselves in init().
+func RegisterRunner(method config.RunMethod, factory RunnerFactory) {
+	runnersMu.Lock()
+	defer runnersMu.Unlock()
+
+	runners[method] = factory
+}
+
+// ResolveRunner constructs the runner for the configured run method. Only the
+// configured backend is constructed, so problems with other backends (eg: missing
+// compose files for a binary user) don't get in the way.
+func ResolveRunner(logger *logrus.Logger, sidecarCfg ConfigManager, installerCfg *installer.Config) (SidecarRunner, error) {
+	return NewRunner(sidecarCfg.Get().RunMethod, logger, sidecarCfg, installerCfg)
+}
+
+// NewRunner constructs the runner for method, regardless of the configured run method.
+func NewRunner(
+	method config.RunMethod,
+	logger *logrus.Logger,
+	sidecarCfg ConfigManager,
+	installerCfg *installer.Config,
+) (SidecarRunner, error) {
+	runnersMu.RLock()
+	factory, ok := runners[method]
+	runnersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("invalid sidecar run method: %s", method)
+	}
+
+	runner, err := factory(logger, sidecarCfg, installerCfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s sidecar service: %w", strings.ToLower(method.DisplayName()), err)
+	}
+
+	return runner, nil
+}
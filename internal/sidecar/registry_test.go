@@ -0,0 +1,105 @@
+package sidecar_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResolveRunner(t *testing.T) {
+	// Test-only run methods, so we don't clobber the real registrations.
+	const (
+		runMethodFake   = config.RunMethod(100)
+		runMethodBroken = config.RunMethod(101)
+	)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeRunner := mock.NewMockSidecarRunner(ctrl)
+
+	sidecar.RegisterRunner(runMethodFake, func(*logrus.Logger, sidecar.ConfigManager, *installer.Config) (sidecar.SidecarRunner, error) {
+		return fakeRunner, nil
+	})
+	sidecar.RegisterRunner(runMethodBroken, func(*logrus.Logger, sidecar.ConfigManager, *installer.Config) (sidecar.SidecarRunner, error) {
+		return nil, errors.New("boom")
+	})
+
+	tests := []struct {
+		name          string
+		cfg           func(dir string) *config.Config
+		check         func(t *testing.T, runner sidecar.SidecarRunner)
+		expectedError string
+	}{
+		{
+			name: "resolves registered runner",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{RunMethod: runMethodFake}
+			},
+			check: func(t *testing.T, runner sidecar.SidecarRunner) {
+				t.Helper()
+
+				assert.Same(t, fakeRunner, runner)
+			},
+		},
+		{
+			name: "binary resolves without compose files",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{
+					RunMethod:             config.RunMethod_RUN_METHOD_BINARY,
+					ContributoorDirectory: dir,
+				}
+			},
+			check: func(t *testing.T, runner sidecar.SidecarRunner) {
+				t.Helper()
+
+				assert.Implements(t, (*sidecar.BinarySidecar)(nil), runner)
+			},
+		},
+		{
+			name: "unregistered run method",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{RunMethod: config.RunMethod_RUN_METHOD_UNSPECIFIED}
+			},
+			expectedError: "invalid sidecar run method",
+		},
+		{
+			name: "factory error is wrapped",
+			cfg: func(dir string) *config.Config {
+				return &config.Config{RunMethod: runMethodBroken}
+			},
+			expectedError: "sidecar service: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "logs"), 0755))
+
+			mockConfig := mock.NewMockConfigManager(ctrl)
+			mockConfig.EXPECT().Get().Return(tt.cfg(dir)).AnyTimes()
+
+			runner, err := sidecar.ResolveRunner(logrus.New(), mockConfig, installer.NewConfig())
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				assert.Nil(t, runner)
+
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, runner)
+		})
+	}
+}
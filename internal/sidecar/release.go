@@ -0,0 +1,149 @@
+package sidecar
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxReleaseFileSize caps the size of small release files (checksums, signatures)
+// we're willing to read into memory.
+const maxReleaseFileSize = 1 << 20
+
+// getReleaseAsset downloads a release asset, treating any non-200 response as an error.
+func getReleaseAsset(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:gosec // controlled url.
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+// parseChecksums parses a release checksums file, in the `sha256sum` format of
+// "<hex digest>  <filename>", into a map of filename to digest.
+func parseChecksums(r io.Reader) (map[string]string, error) {
+	var (
+		checksums = make(map[string]string)
+		scanner   = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed checksum line: %q", line)
+		}
+
+		digest := strings.ToLower(fields[0])
+		if _, err := hex.DecodeString(digest); err != nil || len(digest) != sha256.Size*2 {
+			return nil, fmt.Errorf("malformed sha256 digest for %s", fields[1])
+		}
+
+		// sha256sum prefixes the filename with '*' when run in binary mode.
+		checksums[strings.TrimPrefix(fields[1], "*")] = digest
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checksums: %w", err)
+	}
+
+	return checksums, nil
+}
+
+// verifyChecksum checks the actual digest of a file against its entry in the checksums.
+func verifyChecksum(checksums map[string]string, filename, actual string) error {
+	expected, ok := checksums[filename]
+	if !ok {
+		return fmt.Errorf("checksum not found for %s", filename)
+	}
+
+	if !strings.EqualFold(expected, actual) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", filename, expected, actual)
+	}
+
+	return nil
+}
+
+// downloadReleaseFile downloads a small release file into memory.
+func downloadReleaseFile(ctx context.Context, url string) ([]byte, error) {
+	resp, err := getReleaseAsset(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxReleaseFileSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// downloadChecksums fetches and parses a release checksums file.
+func downloadChecksums(ctx context.Context, url string) (map[string]string, error) {
+	data, err := downloadReleaseFile(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download checksums: %w", err)
+	}
+
+	return parseChecksums(bytes.NewReader(data))
+}
+
+// verifySignature verifies a detached ed25519 signature of data. The public key is
+// base64 encoded, and the signature may be either raw or base64 encoded.
+func verifySignature(publicKey string, data, signature []byte) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid signing public key")
+	}
+
+	if len(signature) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil || len(decoded) != ed25519.SignatureSize {
+			return fmt.Errorf("malformed signature")
+		}
+
+		signature = decoded
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(key), data, signature) {
+		return fmt.Errorf("signature verification failed")
+	}
+
+	return nil
+}
+
+// hashingWriter wraps a writer, computing the sha256 digest of everything written to it.
+func hashingWriter(w io.Writer) (io.Writer, func() string) {
+	h := sha256.New()
+
+	return io.MultiWriter(w, h), func() string {
+		return hex.EncodeToString(h.Sum(nil))
+	}
+}
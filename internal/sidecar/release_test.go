@@ -0,0 +1,192 @@
+package sidecar
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChecksums(t *testing.T) {
+	var (
+		digestA = strings.Repeat("a", 64)
+		digestB = strings.Repeat("B", 64)
+	)
+
+	tests := []struct {
+		name          string
+		input         string
+		expected      map[string]string
+		expectedError string
+	}{
+		{
+			name:  "text and binary mode entries",
+			input: digestA + "  contributoor_1.0.0_linux_amd64.tar.gz\n\n" + digestB + " *contributoor_1.0.0_darwin_arm64.tar.gz\n",
+			expected: map[string]string{
+				"contributoor_1.0.0_linux_amd64.tar.gz":  digestA,
+				"contributoor_1.0.0_darwin_arm64.tar.gz": strings.ToLower(digestB),
+			},
+		},
+		{
+			name:     "empty file",
+			input:    "",
+			expected: map[string]string{},
+		},
+		{
+			name:          "missing filename",
+			input:         digestA + "\n",
+			expectedError: "malformed checksum line",
+		},
+		{
+			name:          "invalid digest",
+			input:         "not-a-digest  contributoor.tar.gz\n",
+			expectedError: "malformed sha256 digest",
+		},
+		{
+			name:          "short digest",
+			input:         "abcd  contributoor.tar.gz\n",
+			expectedError: "malformed sha256 digest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checksums, err := parseChecksums(strings.NewReader(tt.input))
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, checksums)
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	digest := strings.Repeat("a", 64)
+	checksums := map[string]string{"contributoor.tar.gz": digest}
+
+	assert.NoError(t, verifyChecksum(checksums, "contributoor.tar.gz", digest))
+	assert.NoError(t, verifyChecksum(checksums, "contributoor.tar.gz", strings.ToUpper(digest)))
+	assert.ErrorContains(t, verifyChecksum(checksums, "contributoor.tar.gz", strings.Repeat("b", 64)), "checksum mismatch")
+	assert.ErrorContains(t, verifyChecksum(checksums, "other.tar.gz", digest), "checksum not found")
+}
+
+func TestVerifySignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	var (
+		data      = []byte("checksums")
+		publicKey = base64.StdEncoding.EncodeToString(pub)
+		signature = ed25519.Sign(priv, data)
+	)
+
+	tests := []struct {
+		name          string
+		publicKey     string
+		data          []byte
+		signature     []byte
+		expectedError string
+	}{
+		{
+			name:      "raw signature",
+			publicKey: publicKey,
+			data:      data,
+			signature: signature,
+		},
+		{
+			name:      "base64 signature",
+			publicKey: publicKey,
+			data:      data,
+			signature: []byte(base64.StdEncoding.EncodeToString(signature) + "\n"),
+		},
+		{
+			name:          "modified data",
+			publicKey:     publicKey,
+			data:          []byte("tampered"),
+			signature:     signature,
+			expectedError: "signature verification failed",
+		},
+		{
+			name:          "malformed signature",
+			publicKey:     publicKey,
+			data:          data,
+			signature:     []byte("nope"),
+			expectedError: "malformed signature",
+		},
+		{
+			name:          "invalid public key",
+			publicKey:     "bm90LWEta2V5",
+			data:          data,
+			signature:     signature,
+			expectedError: "invalid signing public key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifySignature(tt.publicKey, tt.data, tt.signature)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// sha256Hex returns the hex encoded sha256 digest of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// releaseTransport redirects all GitHub release downloads to a test server,
+// preserving the request path.
+type releaseTransport struct {
+	serverURL string
+}
+
+func (t *releaseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == "github.com" {
+		target, err := url.Parse(t.serverURL)
+		if err != nil {
+			return nil, err
+		}
+
+		newReq := req.Clone(req.Context())
+		newReq.URL.Scheme = target.Scheme
+		newReq.URL.Host = target.Host
+		newReq.Host = target.Host
+
+		return http.DefaultTransport.RoundTrip(newReq)
+	}
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// useReleaseServer routes GitHub release downloads to the given test server for
+// the duration of the test.
+func useReleaseServer(t *testing.T, serverURL string) {
+	t.Helper()
+
+	oldClient := http.DefaultClient
+	http.DefaultClient = &http.Client{Transport: &releaseTransport{serverURL: serverURL}}
+
+	t.Cleanup(func() { http.DefaultClient = oldClient })
+}
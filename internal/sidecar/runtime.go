@@ -0,0 +1,206 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// Container runtimes supported by the docker run method.
+const (
+	ContainerRuntimeAuto   = "auto"
+	ContainerRuntimeDocker = "docker"
+	ContainerRuntimePodman = "podman"
+)
+
+// imageVersionLabel is the image label holding the contributoor version.
+const imageVersionLabel = "org.opencontainers.image.version"
+
+// networkListTimeout bounds listing the networks, so an unresponsive container
+// engine can't hang the pages offering them.
+const networkListTimeout = 5 * time.Second
+
+// ContainerRuntime drives a container engine CLI, along with the compose
+// implementation that goes with it.
+type ContainerRuntime struct {
+	name    string
+	compose []string
+}
+
+// NewContainerRuntime returns the runtime for preference, which is one of "auto",
+// "docker" or "podman". Auto prefers docker, unless docker is podman's docker shim,
+// and falls back to docker if neither is installed so the error surfaces on use.
+func NewContainerRuntime(preference string) (*ContainerRuntime, error) {
+	return resolveContainerRuntime(preference, exec.LookPath, isPodmanShim)
+}
+
+func resolveContainerRuntime(
+	preference string,
+	lookPath func(file string) (string, error),
+	isPodman func(path string) bool,
+) (*ContainerRuntime, error) {
+	switch preference {
+	case ContainerRuntimeDocker:
+		return newDockerRuntime(), nil
+	case ContainerRuntimePodman:
+		return newPodmanRuntime(lookPath), nil
+	case "", ContainerRuntimeAuto:
+		dockerPath, dockerErr := lookPath("docker")
+		_, podmanErr := lookPath("podman")
+
+		if podmanErr == nil && (dockerErr != nil || isPodman(dockerPath)) {
+			return newPodmanRuntime(lookPath), nil
+		}
+
+		return newDockerRuntime(), nil
+	default:
+		return nil, fmt.Errorf(
+			"invalid container runtime %q, must be one of: %s, %s, %s",
+			preference,
+			ContainerRuntimeAuto,
+			ContainerRuntimeDocker,
+			ContainerRuntimePodman,
+		)
+	}
+}
+
+func newDockerRuntime() *ContainerRuntime {
+	return &ContainerRuntime{name: ContainerRuntimeDocker, compose: []string{"docker", "compose"}}
+}
+
+// newPodmanRuntime prefers podman-compose when it's installed, as `podman compose`
+// is only a wrapper which delegates to an external compose provider.
+func newPodmanRuntime(lookPath func(file string) (string, error)) *ContainerRuntime {
+	if _, err := lookPath("podman-compose"); err == nil {
+		return &ContainerRuntime{name: ContainerRuntimePodman, compose: []string{"podman-compose"}}
+	}
+
+	return &ContainerRuntime{name: ContainerRuntimePodman, compose: []string{"podman", "compose"}}
+}
+
+// isPodmanShim checks whether the docker binary at path is podman-docker.
+func isPodmanShim(path string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(string(output)), "podman")
+}
+
+// Name returns the name of the runtime, "docker" or "podman".
+func (r *ContainerRuntime) Name() string {
+	return r.name
+}
+
+// ComposeName returns the name of the compose implementation, eg: "docker compose".
+func (r *ContainerRuntime) ComposeName() string {
+	return strings.Join(r.compose, " ")
+}
+
+// ComposeCommand returns a command which runs the compose implementation with args.
+func (r *ContainerRuntime) ComposeCommand(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, r.compose[0], append(r.compose[1:], args...)...)
+}
+
+// ServerVersion returns the version of the container engine, erroring if its API
+// socket isn't reachable.
+func (r *ContainerRuntime) ServerVersion(ctx context.Context) (string, error) {
+	apiClient, err := r.newEngineClient()
+	if err != nil {
+		return "", err
+	}
+
+	defer apiClient.Close()
+
+	version, err := newDockerEngine(apiClient).serverVersion(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s is not reachable at %s: %w", r.name, apiClient.DaemonHost(), err)
+	}
+
+	return version, nil
+}
+
+// NetworkList returns the names of the container engine's networks, giving up after
+// a few seconds.
+func (r *ContainerRuntime) NetworkList(ctx context.Context) ([]string, error) {
+	apiClient, err := r.newEngineClient()
+	if err != nil {
+		return nil, err
+	}
+
+	defer apiClient.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, networkListTimeout)
+	defer cancel()
+
+	return newDockerEngine(apiClient).networkList(ctx)
+}
+
+// engineHost returns the Engine API socket of the runtime, or "" to use the client
+// default. DOCKER_HOST always takes precedence.
+func (r *ContainerRuntime) engineHost() string {
+	if r.name != ContainerRuntimePodman || os.Getenv(client.EnvOverrideHost) != "" {
+		return ""
+	}
+
+	if host := os.Getenv("CONTAINER_HOST"); strings.HasPrefix(host, "unix://") {
+		return host
+	}
+
+	if os.Geteuid() == 0 {
+		return "unix:///run/podman/podman.sock"
+	}
+
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		runtimeDir = fmt.Sprintf("/run/user/%d", os.Getuid())
+	}
+
+	return "unix://" + filepath.Join(runtimeDir, "podman", "podman.sock")
+}
+
+// newEngineClient returns an Engine API client for the runtime.
+func (r *ContainerRuntime) newEngineClient() (*client.Client, error) {
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+	if host := r.engineHost(); host != "" {
+		opts = append(opts, client.WithHost(host))
+	}
+
+	apiClient, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s client: %w", r.name, err)
+	}
+
+	return apiClient, nil
+}
+
+// QualifyImage returns the image reference to use with the runtime. Podman refuses
+// to guess the registry for short names when not attached to a terminal, so they
+// are qualified with docker.io.
+func (r *ContainerRuntime) QualifyImage(image string) string {
+	if r.name != ContainerRuntimePodman {
+		return image
+	}
+
+	if domain, _, ok := strings.Cut(image, "/"); ok && (strings.ContainsAny(domain, ".:") || domain == "localhost") {
+		return image
+	}
+
+	return "docker.io/" + image
+}
+
+// composeDownSupportsRmi returns true if `compose down` accepts --rmi, which
+// podman-compose doesn't.
+func (r *ContainerRuntime) composeDownSupportsRmi() bool {
+	return r.compose[0] != "podman-compose"
+}
@@ -0,0 +1,108 @@
+package sidecar
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveContainerRuntime(t *testing.T) {
+	tests := []struct {
+		name            string
+		preference      string
+		installed       []string
+		dockerIsPodman  bool
+		expectedName    string
+		expectedCompose string
+		expectedError   string
+	}{
+		{
+			name:            "auto prefers docker",
+			preference:      ContainerRuntimeAuto,
+			installed:       []string{"docker", "podman"},
+			expectedName:    ContainerRuntimeDocker,
+			expectedCompose: "docker compose",
+		},
+		{
+			name:            "auto falls back to podman",
+			preference:      "",
+			installed:       []string{"podman"},
+			expectedName:    ContainerRuntimePodman,
+			expectedCompose: "podman compose",
+		},
+		{
+			name:            "auto sees through the podman docker shim",
+			preference:      ContainerRuntimeAuto,
+			installed:       []string{"docker", "podman", "podman-compose"},
+			dockerIsPodman:  true,
+			expectedName:    ContainerRuntimePodman,
+			expectedCompose: "podman-compose",
+		},
+		{
+			name:            "auto with nothing installed",
+			preference:      ContainerRuntimeAuto,
+			expectedName:    ContainerRuntimeDocker,
+			expectedCompose: "docker compose",
+		},
+		{
+			name:            "explicit podman prefers podman-compose",
+			preference:      ContainerRuntimePodman,
+			installed:       []string{"docker", "podman", "podman-compose"},
+			expectedName:    ContainerRuntimePodman,
+			expectedCompose: "podman-compose",
+		},
+		{
+			name:            "explicit docker",
+			preference:      ContainerRuntimeDocker,
+			installed:       []string{"podman"},
+			expectedName:    ContainerRuntimeDocker,
+			expectedCompose: "docker compose",
+		},
+		{
+			name:          "invalid preference",
+			preference:    "containerd",
+			expectedError: "invalid container runtime",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookPath := func(file string) (string, error) {
+				for _, installed := range tt.installed {
+					if installed == file {
+						return "/usr/bin/" + file, nil
+					}
+				}
+
+				return "", exec.ErrNotFound
+			}
+
+			runtime, err := resolveContainerRuntime(tt.preference, lookPath, func(string) bool { return tt.dockerIsPodman })
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedName, runtime.Name())
+			assert.Equal(t, tt.expectedCompose, runtime.ComposeName())
+		})
+	}
+}
+
+func TestContainerRuntime_QualifyImage(t *testing.T) {
+	var (
+		docker = newDockerRuntime()
+		podman = &ContainerRuntime{name: ContainerRuntimePodman, compose: []string{"podman", "compose"}}
+	)
+
+	assert.Equal(t, "ethpandaops/contributoor", docker.QualifyImage("ethpandaops/contributoor"))
+	assert.Equal(t, "docker.io/ethpandaops/contributoor", podman.QualifyImage("ethpandaops/contributoor"))
+	assert.Equal(t, "docker.io/busybox", podman.QualifyImage("busybox"))
+	assert.Equal(t, "ghcr.io/ethpandaops/contributoor", podman.QualifyImage("ghcr.io/ethpandaops/contributoor"))
+	assert.Equal(t, "localhost:5000/contributoor", podman.QualifyImage("localhost:5000/contributoor"))
+	assert.Equal(t, "localhost/contributoor", podman.QualifyImage("localhost/contributoor"))
+}
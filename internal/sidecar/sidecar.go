@@ -1,10 +1,23 @@
 package sidecar
 
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+)
+
 // RunMethods defines the possible ways to run the contributoor service.
 const (
 	RunMethodDocker  = "docker"
 	RunMethodSystemd = "systemd"
 	RunMethodBinary  = "binary"
+	// RunMethodSystemdUser is the systemd run method with a user unit, which isn't a
+	// run method of its own in the config, but is recorded in installer.yaml.
+	RunMethodSystemdUser = "systemd-user"
 )
 
 const (
@@ -12,26 +25,103 @@ const (
 	ArchLinux  = "linux"
 )
 
-// SidecarRunner handles operations for the various run methods.
+// queryTimeout bounds commands which only inspect the service, so a wedged docker
+// daemon or service manager can't hang the CLI indefinitely.
+const queryTimeout = 30 * time.Second
+
+//go:generate mockgen -package mock -destination mock/sidecar.mock.go github.com/ethpandaops/contributoor-installer/internal/sidecar SidecarRunner
+
+// SidecarRunner handles operations for the various run methods. Cancelling the
+// context passed to a method aborts any commands or downloads it has in flight.
 type SidecarRunner interface {
 	// Start starts the service.
-	Start() error
+	Start(ctx context.Context) error
 
 	// Stop stops the service.
-	Stop() error
+	Stop(ctx context.Context) error
 
 	// Update updates the service.
-	Update() error
+	Update(ctx context.Context) error
 
 	// Status returns the status of the service.
-	Status() (string, error)
+	Status(ctx context.Context) (*Status, error)
 
 	// IsRunning checks if the service is running.
-	IsRunning() (bool, error)
+	IsRunning(ctx context.Context) (bool, error)
 
-	// Logs returns the logs from the service.
-	Logs(tailLines int, follow bool) error
+	// Logs shows the logs from the service selected by opts.
+	Logs(ctx context.Context, opts LogOptions) error
 
 	// Version returns the current version the underlying sidecar is running.
-	Version() (string, error)
+	Version(ctx context.Context) (string, error)
+
+	// Provision installs the run method specific artifacts of the service, ie: the
+	// image, binary or service unit, without starting it.
+	Provision(ctx context.Context) error
+
+	// UninstallSteps returns the steps required to remove the run method specific
+	// artifacts of the service. The service is expected to be stopped first.
+	UninstallSteps() []UninstallStep
+}
+
+// UninstallStep is a single action taken when uninstalling contributoor.
+type UninstallStep struct {
+	// Description describes what the step removes.
+	Description string
+	// Run performs the step.
+	Run func(ctx context.Context) error
+}
+
+// ParseRunMethod parses a run method in either its short form (eg: "docker") or
+// its config form (eg: "RUN_METHOD_DOCKER").
+func ParseRunMethod(method string) (config.RunMethod, error) {
+	switch strings.ToLower(strings.TrimSpace(method)) {
+	case RunMethodDocker, "run_method_docker":
+		return config.RunMethod_RUN_METHOD_DOCKER, nil
+	case RunMethodSystemd, "run_method_systemd", RunMethodSystemdUser:
+		return config.RunMethod_RUN_METHOD_SYSTEMD, nil
+	case RunMethodBinary, "run_method_binary":
+		return config.RunMethod_RUN_METHOD_BINARY, nil
+	default:
+		return config.RunMethod_RUN_METHOD_UNSPECIFIED, fmt.Errorf(
+			"invalid run method %q, must be one of: %s, %s, %s, %s",
+			method,
+			RunMethodDocker,
+			RunMethodSystemd,
+			RunMethodSystemdUser,
+			RunMethodBinary,
+		)
+	}
+}
+
+// ParseSystemdScope returns the systemd scope selected by a run method accepted by
+// ParseRunMethod, ie: "user" for systemd-user, "system" for systemd, or "" if it
+// isn't the systemd run method.
+func ParseSystemdScope(method string) string {
+	switch strings.ToLower(strings.TrimSpace(method)) {
+	case RunMethodSystemdUser:
+		return installer.SystemdScopeUser
+	case RunMethodSystemd, "run_method_systemd":
+		return installer.SystemdScopeSystem
+	default:
+		return ""
+	}
+}
+
+// RunMode is a run method along with the systemd scope of its unit, which is empty
+// for the other run methods.
+type RunMode struct {
+	Method config.RunMethod
+	Scope  string
+}
+
+// Name returns the run mode for display and as passed to switch-run-method, eg:
+// docker, or systemd-user for the systemd run method with a user unit. An empty
+// scope is the system scope.
+func (m RunMode) Name() string {
+	if m.Method == config.RunMethod_RUN_METHOD_SYSTEMD && m.Scope == installer.SystemdScopeUser {
+		return RunMethodSystemdUser
+	}
+
+	return strings.ToLower(m.Method.DisplayName())
 }
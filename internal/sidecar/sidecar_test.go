@@ -0,0 +1,51 @@
+package sidecar
+
+import (
+	"testing"
+
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRunMethod(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		expected      config.RunMethod
+		expectedScope string
+		expectedError string
+	}{
+		{name: "short docker", method: "docker", expected: config.RunMethod_RUN_METHOD_DOCKER},
+		{name: "short systemd", method: "systemd", expected: config.RunMethod_RUN_METHOD_SYSTEMD, expectedScope: "system"},
+		{name: "systemd user", method: "systemd-user", expected: config.RunMethod_RUN_METHOD_SYSTEMD, expectedScope: "user"},
+		{name: "short binary", method: "binary", expected: config.RunMethod_RUN_METHOD_BINARY},
+		{name: "config form", method: "RUN_METHOD_SYSTEMD", expected: config.RunMethod_RUN_METHOD_SYSTEMD, expectedScope: "system"},
+		{name: "mixed case with spaces", method: " Docker ", expected: config.RunMethod_RUN_METHOD_DOCKER},
+		{name: "unknown", method: "kubernetes", expectedError: "invalid run method"},
+		{name: "empty", method: "", expectedError: "invalid run method"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, err := ParseRunMethod(tt.method)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, method)
+			assert.Equal(t, tt.expectedScope, ParseSystemdScope(tt.method))
+		})
+	}
+}
+
+func TestRunMode_Name(t *testing.T) {
+	assert.Equal(t, "docker", RunMode{Method: config.RunMethod_RUN_METHOD_DOCKER, Scope: "user"}.Name())
+	assert.Equal(t, "systemd", RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: "system"}.Name())
+	assert.Equal(t, "systemd", RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD}.Name())
+	assert.Equal(t, "systemd-user", RunMode{Method: config.RunMethod_RUN_METHOD_SYSTEMD, Scope: "user"}.Name())
+	assert.Equal(t, "binary", RunMode{Method: config.RunMethod_RUN_METHOD_BINARY}.Name())
+}
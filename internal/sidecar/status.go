@@ -0,0 +1,189 @@
+package sidecar
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// State is the normalised state of the service, regardless of how it is run.
+type State int
+
+const (
+	// StateUnknown means the state couldn't be determined.
+	StateUnknown State = iota
+	// StateStopped means the service isn't running.
+	StateStopped
+	// StateStarting means the service is starting up.
+	StateStarting
+	// StateRunning means the service is running.
+	StateRunning
+	// StateRestarting means the service exited and is being restarted.
+	StateRestarting
+	// StateFailed means the service exited with an error.
+	StateFailed
+)
+
+// String returns the lowercase name of the state.
+func (s State) String() string {
+	switch s {
+	case StateStopped:
+		return "stopped"
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateRestarting:
+		return "restarting"
+	case StateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *State) UnmarshalText(text []byte) error {
+	for _, state := range []State{StateStopped, StateStarting, StateRunning, StateRestarting, StateFailed, StateUnknown} {
+		if state.String() == string(text) {
+			*s = state
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid state: %s", text)
+}
+
+// Status describes the service as reported by its run method.
+type Status struct {
+	// State is the normalised state of the service.
+	State State
+	// PID is the process id of the service, if known.
+	PID int
+	// ContainerID is the docker container id, docker only.
+	ContainerID string
+	// StartedAt is when the service was last started, if known.
+	StartedAt time.Time
+	// Uptime is how long the service has been running, zero unless running.
+	Uptime time.Duration
+	// RestartCount is how many times the service has been restarted, if known.
+	RestartCount int
+	// ExitCode is the exit code of the last run, if known.
+	ExitCode int
+	// Detail is the run method's native description of the state (eg: "exited", "active (running)").
+	Detail string
+}
+
+// IsRunning returns true if the service is running.
+func (s *Status) IsRunning() bool {
+	return s.State == StateRunning
+}
+
+// setUptime calculates the uptime of a running service from its start time.
+func (s *Status) setUptime(now time.Time) {
+	if s.State == StateRunning && !s.StartedAt.IsZero() && now.After(s.StartedAt) {
+		s.Uptime = now.Sub(s.StartedAt).Truncate(time.Second)
+	}
+}
+
+// systemdShowProperties are the unit properties requested from `systemctl show`.
+var systemdShowProperties = []string{
+	"ActiveState",
+	"SubState",
+	"MainPID",
+	"ExecMainStartTimestamp",
+	"ExecMainStatus",
+	"NRestarts",
+}
+
+// parseSystemdShow parses the KEY=VALUE output of `systemctl show`.
+func parseSystemdShow(output []byte, now time.Time) (*Status, error) {
+	props := make(map[string]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "="); ok {
+			props[key] = strings.TrimSpace(value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse systemctl output: %w", err)
+	}
+
+	activeState, subState := props["ActiveState"], props["SubState"]
+	if activeState == "" {
+		return nil, fmt.Errorf("systemctl output is missing ActiveState")
+	}
+
+	status := &Status{Detail: activeState}
+	if subState != "" && subState != activeState {
+		status.Detail = fmt.Sprintf("%s (%s)", activeState, subState)
+	}
+
+	switch activeState {
+	case "active", "reloading":
+		status.State = StateRunning
+	case "activating":
+		status.State = StateStarting
+		if subState == "auto-restart" {
+			status.State = StateRestarting
+		}
+	case "inactive", "deactivating":
+		status.State = StateStopped
+	case "failed":
+		status.State = StateFailed
+	default:
+		status.State = StateUnknown
+	}
+
+	// These are all best effort, older versions of systemd don't report NRestarts.
+	status.PID, _ = strconv.Atoi(props["MainPID"])
+	status.ExitCode, _ = strconv.Atoi(props["ExecMainStatus"])
+	status.RestartCount, _ = strconv.Atoi(props["NRestarts"])
+
+	if startedAt, err := time.Parse("Mon 2006-01-02 15:04:05 MST", props["ExecMainStartTimestamp"]); err == nil {
+		status.StartedAt = startedAt
+	}
+
+	status.setUptime(now)
+
+	return status, nil
+}
+
+var (
+	launchdPIDPattern        = regexp.MustCompile(`"PID"\s*=\s*(\d+);`)
+	launchdExitStatusPattern = regexp.MustCompile(`"LastExitStatus"\s*=\s*(-?\d+);`)
+)
+
+// parseLaunchdList parses the output of `launchctl list <label>`.
+func parseLaunchdList(output []byte) *Status {
+	status := &Status{State: StateStopped, Detail: "inactive"}
+
+	if match := launchdExitStatusPattern.FindSubmatch(output); match != nil {
+		status.ExitCode, _ = strconv.Atoi(string(match[1]))
+	}
+
+	if match := launchdPIDPattern.FindSubmatch(output); match != nil {
+		status.PID, _ = strconv.Atoi(string(match[1]))
+		status.State = StateRunning
+		status.Detail = "active"
+
+		return status
+	}
+
+	if status.ExitCode != 0 {
+		status.State = StateFailed
+	}
+
+	return status
+}
@@ -0,0 +1,116 @@
+package sidecar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSystemdShow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		output        string
+		expected      *Status
+		expectedError string
+	}{
+		{
+			name: "active",
+			output: "ActiveState=active\nSubState=running\nMainPID=1234\n" +
+				"ExecMainStartTimestamp=Mon 2024-01-01 11:30:00 UTC\nExecMainStatus=0\nNRestarts=1\n",
+			expected: &Status{
+				State:        StateRunning,
+				PID:          1234,
+				StartedAt:    time.Date(2024, 1, 1, 11, 30, 0, 0, time.UTC),
+				Uptime:       30 * time.Minute,
+				RestartCount: 1,
+				Detail:       "active (running)",
+			},
+		},
+		{
+			name:   "inactive",
+			output: "ActiveState=inactive\nSubState=dead\nMainPID=0\nExecMainStartTimestamp=\nExecMainStatus=0\n",
+			expected: &Status{
+				State:  StateStopped,
+				Detail: "inactive (dead)",
+			},
+		},
+		{
+			name: "failed",
+			output: "ActiveState=failed\nSubState=failed\nMainPID=0\n" +
+				"ExecMainStartTimestamp=Mon 2024-01-01 11:30:00 UTC\nExecMainStatus=1\nNRestarts=5\n",
+			expected: &Status{
+				State:        StateFailed,
+				StartedAt:    time.Date(2024, 1, 1, 11, 30, 0, 0, time.UTC),
+				RestartCount: 5,
+				ExitCode:     1,
+				Detail:       "failed",
+			},
+		},
+		{
+			name:   "waiting to restart",
+			output: "ActiveState=activating\nSubState=auto-restart\nExecMainStatus=1\nNRestarts=2\n",
+			expected: &Status{
+				State:        StateRestarting,
+				RestartCount: 2,
+				ExitCode:     1,
+				Detail:       "activating (auto-restart)",
+			},
+		},
+		{
+			name:   "starting",
+			output: "ActiveState=activating\nSubState=start\n",
+			expected: &Status{
+				State:  StateStarting,
+				Detail: "activating (start)",
+			},
+		},
+		{
+			name:          "missing active state",
+			output:        "SubState=running\n",
+			expectedError: "missing ActiveState",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := parseSystemdShow([]byte(tt.output), now)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, status)
+		})
+	}
+}
+
+func TestParseLaunchdList(t *testing.T) {
+	running := parseLaunchdList([]byte("{\n\t\"PID\" = 812;\n\t\"LastExitStatus\" = 0;\n\t\"Label\" = \"io.ethpandaops.contributoor\";\n};\n"))
+	assert.Equal(t, &Status{State: StateRunning, PID: 812, Detail: "active"}, running)
+
+	stopped := parseLaunchdList([]byte("{\n\t\"LastExitStatus\" = 0;\n};\n"))
+	assert.Equal(t, &Status{State: StateStopped, Detail: "inactive"}, stopped)
+
+	failed := parseLaunchdList([]byte("{\n\t\"LastExitStatus\" = 256;\n};\n"))
+	assert.Equal(t, &Status{State: StateFailed, ExitCode: 256, Detail: "inactive"}, failed)
+}
+
+func TestStateText(t *testing.T) {
+	for _, state := range []State{StateUnknown, StateStopped, StateStarting, StateRunning, StateRestarting, StateFailed} {
+		text, err := state.MarshalText()
+		require.NoError(t, err)
+
+		var parsed State
+		require.NoError(t, parsed.UnmarshalText(text))
+		assert.Equal(t, state, parsed)
+	}
+
+	var invalid State
+	assert.Error(t, invalid.UnmarshalText([]byte("bogus")))
+}
@@ -0,0 +1,247 @@
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/ethpandaops/contributoor-installer/internal/pidfile"
+	"github.com/mitchellh/go-homedir"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// supervisorStateFile is the file, in the contributoor directory, the supervisor
+	// records the state of the sidecar in.
+	supervisorStateFile = "supervisor.json"
+	// minRestartBackoff is the delay before the first restart after a crash.
+	minRestartBackoff = time.Second
+	// maxRestartBackoff caps the delay between restarts.
+	maxRestartBackoff = time.Minute
+	// stableRunTime is how long the sidecar must stay up for the backoff to reset.
+	stableRunTime = 5 * time.Minute
+)
+
+// SupervisorState is the state of the sidecar as recorded by the supervisor.
+type SupervisorState struct {
+	// PID is the process id of the sidecar, zero while waiting to restart it.
+	PID int `json:"pid"`
+	// StartedAt is when the sidecar was last started.
+	StartedAt time.Time `json:"startedAt"`
+	// Restarts is how many times the sidecar has been restarted after exiting.
+	Restarts int `json:"restarts"`
+	// LastExitCode is the exit code of the last run, -1 if it couldn't be started.
+	LastExitCode int `json:"lastExitCode"`
+	// LastExitAt is when the sidecar last exited.
+	LastExitAt time.Time `json:"lastExitAt,omitzero"`
+}
+
+// Supervisor runs the sentry binary for the binary run method, restarting it with
+// exponential backoff whenever it exits. It runs detached from the CLI, via the
+// hidden supervise command, so the sidecar outlives the command which started it.
+type Supervisor struct {
+	logger     *logrus.Logger
+	binary     string
+	args       []string
+	dir        string
+	stdout     io.Writer
+	stderr     io.Writer
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	stableRun  time.Duration
+	state      SupervisorState
+}
+
+// NewSupervisor creates a supervisor for the sidecar configured in sidecarCfg,
+// writing the sidecar's output to stdout and stderr.
+func NewSupervisor(logger *logrus.Logger, sidecarCfg ConfigManager, stdout, stderr io.Writer) (*Supervisor, error) {
+	dir, err := homedir.Expand(sidecarCfg.Get().ContributoorDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand config path: %w", err)
+	}
+
+	return &Supervisor{
+		logger:     logger,
+		binary:     filepath.Join(dir, "bin", "sentry"),
+		args:       []string{"--config", filepath.Join(dir, "config.yaml")},
+		dir:        dir,
+		stdout:     stdout,
+		stderr:     stderr,
+		minBackoff: minRestartBackoff,
+		maxBackoff: maxRestartBackoff,
+		stableRun:  stableRunTime,
+	}, nil
+}
+
+// Run runs the sidecar until ctx is cancelled, then stops it and removes the pid
+// file so the sidecar is reported as stopped.
+func (s *Supervisor) Run(ctx context.Context) error {
+	defer s.removePidFile()
+
+	backoff := s.minBackoff
+
+	for {
+		startedAt := time.Now()
+
+		exitCode, err := s.runOnce(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			s.logger.Errorf("Failed to run sentry: %v", err)
+		}
+
+		// Only back off further if the sidecar is crashing repeatedly.
+		if time.Since(startedAt) >= s.stableRun {
+			backoff = s.minBackoff
+		}
+
+		s.state.PID = 0
+		s.state.LastExitCode = exitCode
+		s.state.LastExitAt = time.Now()
+		s.writeState()
+
+		s.logger.Warnf("Sentry exited with code %d, restarting in %s", exitCode, backoff)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		s.state.Restarts++
+		backoff = min(backoff*2, s.maxBackoff)
+	}
+}
+
+// runOnce starts the sidecar and waits for it to exit, returning its exit code. If
+// ctx is cancelled first, the sidecar is asked to shut down.
+func (s *Supervisor) runOnce(ctx context.Context) (int, error) {
+	// The sidecar isn't started with ctx, as cancelling it would SIGKILL the process
+	// rather than letting it shut down cleanly.
+	cmd := exec.Command(s.binary, s.args...) //nolint:gosec // binary and args come from the config.
+	cmd.Stdout = s.stdout
+	cmd.Stderr = s.stderr
+
+	if err := cmd.Start(); err != nil {
+		return -1, fmt.Errorf("failed to start sentry: %w", err)
+	}
+
+	s.state.PID = cmd.Process.Pid
+	s.state.StartedAt = time.Now()
+	s.writeState()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return cmd.ProcessState.ExitCode(), exitError(err)
+	case <-ctx.Done():
+		s.logger.Info("Stopping sentry")
+
+		if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+			s.logger.Errorf("Failed to signal sentry: %v", err)
+		}
+
+		<-done
+
+		s.state.PID = 0
+		s.state.LastExitCode = cmd.ProcessState.ExitCode()
+		s.state.LastExitAt = time.Now()
+		s.writeState()
+
+		return s.state.LastExitCode, nil
+	}
+}
+
+// writeState records the state of the sidecar for status to report. Failing to
+// record it mustn't take the sidecar down, so errors are only logged.
+func (s *Supervisor) writeState() {
+	data, err := json.Marshal(s.state)
+	if err != nil {
+		s.logger.Errorf("Failed to encode supervisor state: %v", err)
+
+		return
+	}
+
+	path := filepath.Join(s.dir, supervisorStateFile)
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		s.logger.Errorf("Failed to write supervisor state: %v", err)
+
+		return
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		s.logger.Errorf("Failed to write supervisor state: %v", err)
+	}
+}
+
+// removePidFile removes the pid file, unless it has since been taken over by
+// another supervisor.
+func (s *Supervisor) removePidFile() {
+	pidFile := pidfile.New(filepath.Join(s.dir, "contributoor.pid"))
+
+	if pid, err := pidFile.Read(); err != nil || pid != os.Getpid() {
+		return
+	}
+
+	if err := pidFile.Remove(); err != nil {
+		s.logger.Errorf("Failed to remove pid file: %v", err)
+	}
+}
+
+// readSupervisorState reads the state recorded by the supervisor in dir.
+func readSupervisorState(dir string) (*SupervisorState, error) {
+	data, err := os.ReadFile(filepath.Join(dir, supervisorStateFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var state SupervisorState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse supervisor state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// applySupervisorState fills in the details of a running supervisor's sidecar.
+func applySupervisorState(status *Status, state *SupervisorState) {
+	status.RestartCount = state.Restarts
+	status.ExitCode = state.LastExitCode
+
+	if state.PID == 0 {
+		status.State = StateRestarting
+		status.Detail = "restarting"
+
+		return
+	}
+
+	status.PID = state.PID
+	status.StartedAt = state.StartedAt
+}
+
+// exitError drops the error cmd.Wait returns for a non-zero exit, which is
+// already reported by the exit code.
+func exitError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil
+	}
+
+	return err
+}
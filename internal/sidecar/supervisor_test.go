@@ -0,0 +1,131 @@
+package sidecar
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupervisor_Run(t *testing.T) {
+	tests := []struct {
+		name string
+		// script is the body of the fake sentry binary. It touches a ready file once
+		// it will exit cleanly on SIGTERM, as stopping it sooner would race its startup.
+		script string
+		// until is the state to wait for, once the sidecar is ready, before stopping the
+		// supervisor.
+		until        func(state *SupervisorState) bool
+		expectedExit int
+	}{
+		{
+			name: "restarts a crashing sidecar",
+			script: "runs=$(dirname \"$0\")/runs\necho >> \"$runs\"\n" +
+				"[ \"$(wc -l < \"$runs\")\" -le 2 ] && exit 3\n" +
+				"trap 'exit 0' TERM\ntouch \"$(dirname \"$0\")/ready\"\nwhile true; do sleep 0.01; done",
+			until: func(state *SupervisorState) bool {
+				return state.Restarts == 2 && state.LastExitCode == 3
+			},
+			expectedExit: 0,
+		},
+		{
+			name:   "stops a running sidecar",
+			script: "trap 'exit 0' TERM\ntouch \"$(dirname \"$0\")/ready\"\nwhile true; do sleep 0.01; done",
+			until: func(state *SupervisorState) bool {
+				return state.PID != 0
+			},
+			expectedExit: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			binary := filepath.Join(dir, "sentry")
+			require.NoError(t, os.WriteFile(binary, []byte("#!/bin/sh\n"+tt.script+"\n"), 0700)) //nolint:gosec // test script.
+
+			pidFile := filepath.Join(dir, "contributoor.pid")
+			require.NoError(t, os.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())), 0600))
+
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+
+			s := &Supervisor{
+				logger:     logger,
+				binary:     binary,
+				dir:        dir,
+				stdout:     io.Discard,
+				stderr:     io.Discard,
+				minBackoff: time.Millisecond,
+				maxBackoff: 10 * time.Millisecond,
+				stableRun:  time.Minute,
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+
+			go func() {
+				done <- s.Run(ctx)
+			}()
+
+			require.Eventually(t, func() bool {
+				state, err := readSupervisorState(dir)
+
+				if err != nil || !tt.until(state) {
+					return false
+				}
+
+				_, err = os.Stat(filepath.Join(dir, "ready"))
+
+				return err == nil
+			}, 5*time.Second, 5*time.Millisecond)
+
+			cancel()
+
+			select {
+			case err := <-done:
+				require.NoError(t, err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("supervisor didn't stop")
+			}
+
+			state, err := readSupervisorState(dir)
+			require.NoError(t, err)
+			assert.Equal(t, 0, state.PID)
+			assert.Equal(t, tt.expectedExit, state.LastExitCode)
+			assert.NoFileExists(t, pidFile)
+		})
+	}
+}
+
+func TestApplySupervisorState(t *testing.T) {
+	startedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("running", func(t *testing.T) {
+		status := &Status{State: StateRunning, Detail: "running", PID: 100}
+		applySupervisorState(status, &SupervisorState{PID: 200, StartedAt: startedAt, Restarts: 2, LastExitCode: 1})
+
+		assert.Equal(t, StateRunning, status.State)
+		assert.Equal(t, 200, status.PID)
+		assert.Equal(t, startedAt, status.StartedAt)
+		assert.Equal(t, 2, status.RestartCount)
+		assert.Equal(t, 1, status.ExitCode)
+	})
+
+	t.Run("waiting to restart", func(t *testing.T) {
+		status := &Status{State: StateRunning, Detail: "running", PID: 100}
+		applySupervisorState(status, &SupervisorState{Restarts: 3, LastExitCode: 2})
+
+		assert.Equal(t, StateRestarting, status.State)
+		assert.Equal(t, 100, status.PID)
+		assert.Equal(t, 3, status.RestartCount)
+		assert.Equal(t, 2, status.ExitCode)
+	})
+}
@@ -1,14 +1,22 @@
 package sidecar
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/user"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ethpandaops/contributoor-installer/internal/installer"
 	"github.com/ethpandaops/contributoor-installer/internal/tui"
+	"github.com/ethpandaops/contributoor/pkg/config/v1"
 	"github.com/sirupsen/logrus"
 )
 
@@ -16,64 +24,112 @@ import (
 
 type SystemdSidecar interface {
 	SidecarRunner
+
+	// CheckInstalled returns an error if the service unit (or launchd plist) isn't installed.
+	CheckInstalled(ctx context.Context) error
+
+	// RenderUnit returns the service unit (or launchd plist) rendered from the config
+	// and installer settings.
+	RenderUnit() ([]byte, error)
+
+	// UnitDrift returns how the installed service unit (or launchd plist) differs from
+	// the rendered one, as lines prefixed by "-" if they're only in the installed unit
+	// and "+" if they're only in the rendered one. It's empty if they match.
+	UnitDrift() ([]string, error)
+
+	// InstallUnit renders the service unit (or launchd plist) and installs it in place
+	// of any installed one. The service isn't started or restarted.
+	InstallUnit(ctx context.Context) error
 }
 
 // systemdSidecar is a service for managing the contributoor service (systemd on Linux, launchd on macOS).
 type systemdSidecar struct {
 	logger       *logrus.Logger
+	name         string
+	userScope    bool
 	sidecarCfg   ConfigManager
 	installerCfg *installer.Config
 }
 
+func init() {
+	RegisterRunner(config.RunMethod_RUN_METHOD_SYSTEMD, func(logger *logrus.Logger, sidecarCfg ConfigManager, installerCfg *installer.Config) (SidecarRunner, error) {
+		return NewSystemdSidecar(logger, sidecarCfg, installerCfg)
+	})
+}
+
 // NewSystemdSidecar creates a new SystemdSidecar.
 func NewSystemdSidecar(logger *logrus.Logger, sidecarCfg ConfigManager, installerCfg *installer.Config) (SystemdSidecar, error) {
+	var userScope bool
+
+	switch installerCfg.Systemd.Scope {
+	case "", installer.SystemdScopeSystem:
+	case installer.SystemdScopeUser:
+		if runtime.GOOS == ArchDarwin {
+			return nil, fmt.Errorf("the user systemd scope isn't supported on macOS, use the system scope instead")
+		}
+
+		userScope = true
+	default:
+		return nil, fmt.Errorf(
+			"invalid systemd scope %q, must be one of: %s, %s",
+			installerCfg.Systemd.Scope,
+			installer.SystemdScopeSystem,
+			installer.SystemdScopeUser,
+		)
+	}
+
 	return &systemdSidecar{
 		logger:       logger,
+		name:         ServiceName(installerCfg.Instance),
+		userScope:    userScope,
 		sidecarCfg:   sidecarCfg,
 		installerCfg: installerCfg,
 	}, nil
 }
 
 // Start starts the service.
-func (s *systemdSidecar) Start() error {
-	if err := s.checkBinaryExists(); err != nil {
+func (s *systemdSidecar) Start(ctx context.Context) error {
+	if err := s.checkBinaryExists(ctx); err != nil {
 		return wrapNotInstalledError(err, "systemd")
 	}
 
 	if runtime.GOOS == ArchDarwin {
-		return s.startLaunchd()
+		return s.startLaunchd(ctx)
 	}
 
-	return s.startSystemd()
+	return s.startSystemd(ctx)
 }
 
 // Stop stops the service.
-func (s *systemdSidecar) Stop() error {
-	if err := s.checkDaemonExists(); err != nil {
+func (s *systemdSidecar) Stop(ctx context.Context) error {
+	if err := s.checkDaemonExists(ctx); err != nil {
 		return err
 	}
 
 	if runtime.GOOS == ArchDarwin {
-		return s.stopLaunchd()
+		return s.stopLaunchd(ctx)
 	}
 
-	return s.stopSystemd()
+	return s.stopSystemd(ctx)
 }
 
 // IsRunning checks if the service is running.
-func (s *systemdSidecar) IsRunning() (bool, error) {
+func (s *systemdSidecar) IsRunning(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
 	if runtime.GOOS == ArchDarwin {
-		return s.isRunningLaunchd()
+		return s.isRunningLaunchd(ctx)
 	}
 
-	return s.isRunningSystemd()
+	return s.isRunningSystemd(ctx)
 }
 
 // Update updates the service.
-func (s *systemdSidecar) Update() error {
+func (s *systemdSidecar) Update(ctx context.Context) error {
 	// Stop service if running
-	if running, _ := s.IsRunning(); running {
-		if err := s.Stop(); err != nil {
+	if running, _ := s.IsRunning(ctx); running {
+		if err := s.Stop(ctx); err != nil {
 			return fmt.Errorf("failed to stop service for update: %w", err)
 		}
 	}
@@ -84,20 +140,20 @@ func (s *systemdSidecar) Update() error {
 		return fmt.Errorf("failed to create binary sidecar: %w", err)
 	}
 
-	if err := binarySidecar.Update(); err != nil {
+	if err := binarySidecar.Update(ctx); err != nil {
 		return fmt.Errorf("failed to update binary: %w", err)
 	}
 
 	// Reload service manager
 	if runtime.GOOS == ArchDarwin {
-		return s.reloadLaunchd()
+		return s.reloadLaunchd(ctx)
 	}
 
-	return s.reloadSystemd()
+	return s.reloadSystemd(ctx)
 }
 
 // Logs shows the logs from the service.
-func (s *systemdSidecar) Logs(tailLines int, follow bool) error {
+func (s *systemdSidecar) Logs(ctx context.Context, opts LogOptions) error {
 	// For macOS, use binary logs.
 	if runtime.GOOS == ArchDarwin {
 		binarySidecar, err := NewBinarySidecar(s.logger, s.sidecarCfg, s.installerCfg)
@@ -105,35 +161,392 @@ func (s *systemdSidecar) Logs(tailLines int, follow bool) error {
 			return fmt.Errorf("failed to create binary sidecar for logs: %w", err)
 		}
 
-		return binarySidecar.Logs(tailLines, follow)
+		return binarySidecar.Logs(ctx, opts)
 	}
 
-	// For Linux/systemd, use journalctl.
-	args := []string{"-u", "contributoor.service", "-e"}
+	printer, err := newLogPrinter(opts)
+	if err != nil {
+		return err
+	}
+
+	var (
+		out, _ = opts.outputs()
+		cursor string
+		args   = []string{"-u", s.unit(), "-o", "json", "--no-pager"}
+	)
+
+	// A user unit logs to the user's own journal.
+	if s.userScope {
+		args = append([]string{"--user"}, args...)
+	}
 
-	if follow {
-		args = append(args, "-f")
+	if !opts.Since.IsZero() {
+		args = append(args, "--since", fmt.Sprintf("@%d", opts.Since.Unix()))
 	}
 
-	if tailLines > 0 {
-		args = append(args, "-n", fmt.Sprintf("%d", tailLines))
+	if !opts.Until.IsZero() {
+		args = append(args, "--until", fmt.Sprintf("@%d", opts.Until.Unix()))
 	}
 
-	//nolint:gosec // controlled input.
-	cmd := exec.Command("sudo", append([]string{"journalctl"}, args...)...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	show := func(entry *JournalEntry) error {
+		cursor = entry.Cursor
+
+		for _, line := range entry.lines(time.Local) {
+			if err := printer.print(out, line); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
 
-	return cmd.Run()
+	tailArgs := args
+	if opts.Tail > 0 {
+		tailArgs = append(slices.Clone(args), "-n", strconv.Itoa(opts.Tail))
+	}
+
+	// Captured logs, eg: being scanned, have no terminal to prompt for a password on.
+	if err := readJournal(ctx, tailArgs, opts.Output != nil, show); err != nil {
+		return err
+	}
+
+	if !opts.Follow {
+		return nil
+	}
+
+	// Carry on from the last entry shown, so nothing is missed or shown twice.
+	followArgs := append(args, "-f")
+
+	switch {
+	case cursor != "":
+		followArgs = append(followArgs, "--after-cursor", cursor, "-n", "all")
+	case !opts.Since.IsZero():
+		followArgs = append(followArgs, "-n", "all")
+	default:
+		followArgs = append(followArgs, "-n", "0")
+	}
+
+	return readJournal(ctx, followArgs, opts.Output != nil, show)
 }
 
-func (s *systemdSidecar) startSystemd() error {
-	if err := s.checkDaemonExists(); err != nil {
-		return wrapNotInstalledError(err, "systemd")
+// Provision installs the sentry binary and the systemd unit (or launchd plist on macOS).
+func (s *systemdSidecar) Provision(ctx context.Context) error {
+	// systemd + launchd are underpinned by the binary sidecar.
+	binarySidecar, err := NewBinarySidecar(s.logger, s.sidecarCfg, s.installerCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create binary sidecar: %w", err)
+	}
+
+	if err := binarySidecar.Provision(ctx); err != nil {
+		return fmt.Errorf("failed to install binary: %w", err)
+	}
+
+	return s.InstallUnit(ctx)
+}
+
+// RenderUnit returns the systemd unit (or launchd plist on macOS) rendered from the
+// config and installer settings.
+func (s *systemdSidecar) RenderUnit() ([]byte, error) {
+	params, err := s.serviceParams()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	if runtime.GOOS == ArchDarwin {
+		if err := plistTemplate.Execute(&buf, params); err != nil {
+			return nil, fmt.Errorf("failed to render launchd plist: %w", err)
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	if err := unitTemplate.Execute(&buf, params); err != nil {
+		return nil, fmt.Errorf("failed to render systemd unit: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnitDrift returns how the installed systemd unit (or launchd plist on macOS)
+// differs from the rendered one, ignoring comments and formatting.
+func (s *systemdSidecar) UnitDrift() ([]string, error) {
+	rendered, err := s.RenderUnit()
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := s.servicePath()
+	if err != nil {
+		return nil, err
+	}
+
+	// Units are world readable, so this never needs sudo.
+	installed, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrUnitNotInstalled, path)
+		}
+
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return diffUnit(installed, rendered), nil
+}
+
+// InstallUnit renders the systemd unit (or launchd plist on macOS) and installs it.
+// A systemd unit is reloaded and enabled, but not started, same as install.sh.
+func (s *systemdSidecar) InstallUnit(ctx context.Context) error {
+	data, err := s.RenderUnit()
+	if err != nil {
+		return err
+	}
+
+	if runtime.GOOS == ArchDarwin {
+		if err := sudoWriteFile(ctx, s.plistPath(), data); err != nil {
+			return err
+		}
+
+		cmd := exec.CommandContext(ctx, "sudo", "chown", "root:wheel", s.plistPath())
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to set plist owner: %s: %w", string(output), err)
+		}
+
+		return nil
+	}
+
+	unitPath, err := s.unitPath()
+	if err != nil {
+		return err
+	}
+
+	if s.userScope {
+		err = writeUserFile(unitPath, data)
+	} else {
+		err = sudoWriteFile(ctx, unitPath, data)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.reloadSystemd(ctx); err != nil {
+		return err
+	}
+
+	if output, err := s.systemctl(ctx, "enable", s.unit()).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to enable service: %s: %w", string(output), err)
+	}
+
+	if s.userScope {
+		if current, err := user.Current(); err == nil && !lingerEnabled(current.Username) {
+			printLingerGuidance(current.Username)
+		}
+	}
+
+	return nil
+}
+
+// UninstallSteps returns the steps to remove the systemd unit (or launchd plist on macOS).
+func (s *systemdSidecar) UninstallSteps() []UninstallStep {
+	if runtime.GOOS == ArchDarwin {
+		plist := s.plistPath()
+
+		return []UninstallStep{
+			{
+				Description: fmt.Sprintf("Remove launchd service %s", plist),
+				Run: func(ctx context.Context) error {
+					cmd := exec.CommandContext(ctx, "sudo", "rm", "-f", plist)
+					if output, err := cmd.CombinedOutput(); err != nil {
+						return fmt.Errorf("failed to remove plist: %s: %w", string(output), err)
+					}
+
+					return nil
+				},
+			},
+		}
+	}
+
+	unit, err := s.unitPath()
+	if err != nil {
+		return []UninstallStep{
+			{
+				Description: fmt.Sprintf("Disable and remove systemd unit %s", s.unit()),
+				Run: func(context.Context) error {
+					return err
+				},
+			},
+		}
 	}
 
-	cmd := exec.Command("sudo", "systemctl", "start", "contributoor.service")
+	return []UninstallStep{
+		{
+			Description: fmt.Sprintf("Disable and remove systemd unit %s", unit),
+			Run: func(ctx context.Context) error {
+				// Disabling fails if the unit is already gone, which is fine.
+				_ = s.systemctl(ctx, "disable", s.unit()).Run()
+
+				paths := []string{
+					unit,
+					unit + ".d",
+					filepath.Join(filepath.Dir(unit), s.wantedBy()+".wants", s.unit()),
+				}
+
+				if s.userScope {
+					for _, path := range paths {
+						if err := os.RemoveAll(path); err != nil {
+							return fmt.Errorf("failed to remove unit: %w", err)
+						}
+					}
+				} else {
+					cmd := exec.CommandContext(ctx, "sudo", append([]string{"rm", "-rf"}, paths...)...)
+					if output, err := cmd.CombinedOutput(); err != nil {
+						return fmt.Errorf("failed to remove unit: %s: %w", string(output), err)
+					}
+				}
+
+				return s.reloadSystemd(ctx)
+			},
+		},
+	}
+}
+
+// writeUserFile writes data to the user owned file at path, with mode 0644.
+func writeUserFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // units must be readable by systemd.
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// sudoWriteFile writes data to the root owned file at path, with mode 0644.
+func sudoWriteFile(ctx context.Context, path string, data []byte) error {
+	cmd := exec.CommandContext(ctx, "sudo", "mkdir", "-p", filepath.Dir(path))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create %s: %s: %w", filepath.Dir(path), string(output), err)
+	}
+
+	cmd = exec.CommandContext(ctx, "sudo", "tee", path)
+	cmd.Stdin = bytes.NewReader(data)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write %s: %s: %w", path, string(output), err)
+	}
+
+	cmd = exec.CommandContext(ctx, "sudo", "chmod", "644", path)
 	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %s: %w", path, string(output), err)
+	}
+
+	return nil
+}
+
+// unit returns the name of the systemd unit, eg: contributoor.service.
+func (s *systemdSidecar) unit() string {
+	return s.name + ".service"
+}
+
+// servicePath returns the path of the systemd unit, or the launchd plist on macOS.
+func (s *systemdSidecar) servicePath() (string, error) {
+	if runtime.GOOS == ArchDarwin {
+		return s.plistPath(), nil
+	}
+
+	return s.unitPath()
+}
+
+// unitPath returns the path of the systemd unit, in /etc/systemd/system for a system
+// unit or ~/.config/systemd/user for a user unit.
+func (s *systemdSidecar) unitPath() (string, error) {
+	if !s.userScope {
+		return filepath.Join("/etc/systemd/system", s.unit()), nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user config directory: %w", err)
+	}
+
+	return filepath.Join(dir, "systemd", "user", s.unit()), nil
+}
+
+// wantedBy returns the target the unit is enabled for. A user's service manager has
+// no multi-user.target.
+func (s *systemdSidecar) wantedBy() string {
+	if s.userScope {
+		return "default.target"
+	}
+
+	return "multi-user.target"
+}
+
+// systemctl returns the command running systemctl with args, against the user's
+// service manager for a user unit, otherwise with sudo.
+func (s *systemdSidecar) systemctl(ctx context.Context, args ...string) *exec.Cmd {
+	if s.userScope {
+		return exec.CommandContext(ctx, "systemctl", append([]string{"--user"}, args...)...)
+	}
+
+	return exec.CommandContext(ctx, "sudo", append([]string{"systemctl"}, args...)...)
+}
+
+// systemctlQuery returns the command running systemctl with args which only inspect
+// units, so never need sudo.
+func (s *systemdSidecar) systemctlQuery(ctx context.Context, args ...string) *exec.Cmd {
+	if s.userScope {
+		args = append([]string{"--user"}, args...)
+	}
+
+	return exec.CommandContext(ctx, "systemctl", args...)
+}
+
+// LingerEnabled returns true if systemd keeps the user's service manager running
+// while they're logged out, which user units need to run at boot.
+func LingerEnabled(username string) bool {
+	_, err := os.Stat(filepath.Join("/var/lib/systemd/linger", username))
+
+	return err == nil
+}
+
+// lingerEnabled checks whether lingering is enabled for a user, swappable so tests
+// don't depend on the host.
+var lingerEnabled = LingerEnabled
+
+// printLingerGuidance explains how to keep a user unit running once the user logs out.
+var printLingerGuidance = func(username string) {
+	fmt.Printf(
+		"%sContributoor runs as a user unit, which systemd stops when you log out and doesn't start at boot.\n"+
+			"To keep it running, enable lingering for %s (an administrator can run this for you):\n"+
+			"    loginctl enable-linger %s%s\n",
+		tui.TerminalColorYellow,
+		username,
+		username,
+		tui.TerminalColorReset,
+	)
+}
+
+// launchdLabel returns the launchd label, eg: io.ethpandaops.contributoor.
+func (s *systemdSidecar) launchdLabel() string {
+	return "io.ethpandaops." + s.name
+}
+
+// plistPath returns the path of the launchd plist.
+func (s *systemdSidecar) plistPath() string {
+	return filepath.Join("/Library/LaunchDaemons", s.launchdLabel()+".plist")
+}
+
+func (s *systemdSidecar) startSystemd(ctx context.Context) error {
+	if err := s.checkDaemonExists(ctx); err != nil {
+		return wrapNotInstalledError(err, "systemd")
+	}
+
+	if output, err := s.systemctl(ctx, "start", s.unit()).CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to start service: %s: %w", string(output), err)
 	}
 
@@ -142,13 +555,12 @@ func (s *systemdSidecar) startSystemd() error {
 	return nil
 }
 
-func (s *systemdSidecar) stopSystemd() error {
-	if err := s.checkDaemonExists(); err != nil {
+func (s *systemdSidecar) stopSystemd(ctx context.Context) error {
+	if err := s.checkDaemonExists(ctx); err != nil {
 		return wrapNotInstalledError(err, "systemd")
 	}
 
-	cmd := exec.Command("sudo", "systemctl", "stop", "contributoor.service")
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := s.systemctl(ctx, "stop", s.unit()).CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to stop service: %s: %w", string(output), err)
 	}
 
@@ -157,15 +569,14 @@ func (s *systemdSidecar) stopSystemd() error {
 	return nil
 }
 
-func (s *systemdSidecar) isRunningSystemd() (bool, error) {
-	if err := s.checkDaemonExists(); err != nil {
+func (s *systemdSidecar) isRunningSystemd(ctx context.Context) (bool, error) {
+	if err := s.checkDaemonExists(ctx); err != nil {
 		//nolint:nilerr // We want to return false if the service doesn't exist.
 		return false, nil
 	}
 
-	cmd := exec.Command("sudo", "systemctl", "is-active", "contributoor.service")
-
-	output, err := cmd.CombinedOutput()
+	// Querying the unit doesn't need root, so this never prompts for a password.
+	output, err := s.systemctlQuery(ctx, "is-active", s.unit()).CombinedOutput()
 	if err != nil {
 		//nolint:nilerr // We want to return false if the service doesn't exist.
 		return false, nil
@@ -174,28 +585,27 @@ func (s *systemdSidecar) isRunningSystemd() (bool, error) {
 	return strings.TrimSpace(string(output)) == "active", nil
 }
 
-func (s *systemdSidecar) reloadSystemd() error {
-	cmd := exec.Command("sudo", "systemctl", "daemon-reload")
-	if output, err := cmd.CombinedOutput(); err != nil {
+func (s *systemdSidecar) reloadSystemd(ctx context.Context) error {
+	if output, err := s.systemctl(ctx, "daemon-reload").CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to reload systemd: %s: %w", string(output), err)
 	}
 
 	return nil
 }
 
-func (s *systemdSidecar) startLaunchd() error {
-	if err := s.checkDaemonExists(); err != nil {
+func (s *systemdSidecar) startLaunchd(ctx context.Context) error {
+	if err := s.checkDaemonExists(ctx); err != nil {
 		return wrapNotInstalledError(err, "launchd")
 	}
 
 	// Mac's launchd is a bit different from systemd. We need to load the service first.
-	cmd := exec.Command("sudo", "launchctl", "load", "-w", "/Library/LaunchDaemons/io.ethpandaops.contributoor.plist")
+	cmd := exec.CommandContext(ctx, "sudo", "launchctl", "load", "-w", s.plistPath())
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to load service: %s: %w", string(output), err)
 	}
 
 	// Then we can start it.
-	cmd = exec.Command("sudo", "launchctl", "start", "io.ethpandaops.contributoor")
+	cmd = exec.CommandContext(ctx, "sudo", "launchctl", "start", s.launchdLabel())
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to start service: %s: %w", string(output), err)
 	}
@@ -205,17 +615,17 @@ func (s *systemdSidecar) startLaunchd() error {
 	return nil
 }
 
-func (s *systemdSidecar) stopLaunchd() error {
-	if err := s.checkDaemonExists(); err != nil {
+func (s *systemdSidecar) stopLaunchd(ctx context.Context) error {
+	if err := s.checkDaemonExists(ctx); err != nil {
 		return wrapNotInstalledError(err, "launchd")
 	}
 
 	// First stop the service.
-	cmd := exec.Command("sudo", "launchctl", "stop", "io.ethpandaops.contributoor")
+	cmd := exec.CommandContext(ctx, "sudo", "launchctl", "stop", s.launchdLabel())
 	_ = cmd.Run()
 
 	// Then (similar to what we do with Start()), mac requires us to unload the service, otherwise it never stops.
-	cmd = exec.Command("sudo", "launchctl", "unload", "/Library/LaunchDaemons/io.ethpandaops.contributoor.plist")
+	cmd = exec.CommandContext(ctx, "sudo", "launchctl", "unload", s.plistPath())
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to unload service: %s: %w", string(output), err)
 	}
@@ -225,13 +635,13 @@ func (s *systemdSidecar) stopLaunchd() error {
 	return nil
 }
 
-func (s *systemdSidecar) isRunningLaunchd() (bool, error) {
-	if err := s.checkDaemonExists(); err != nil {
+func (s *systemdSidecar) isRunningLaunchd(ctx context.Context) (bool, error) {
+	if err := s.checkDaemonExists(ctx); err != nil {
 		//nolint:nilerr // We want to return false if the service doesn't exist.
 		return false, nil
 	}
 
-	cmd := exec.Command("sudo", "launchctl", "list", "io.ethpandaops.contributoor")
+	cmd := exec.CommandContext(ctx, "sudo", "launchctl", "list", s.launchdLabel())
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -250,13 +660,13 @@ func (s *systemdSidecar) isRunningLaunchd() (bool, error) {
 	return false, nil
 }
 
-func (s *systemdSidecar) reloadLaunchd() error {
-	cmd := exec.Command("sudo", "launchctl", "unload", "/Library/LaunchDaemons/io.ethpandaops.contributoor.plist")
+func (s *systemdSidecar) reloadLaunchd(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "sudo", "launchctl", "unload", s.plistPath())
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to unload service: %s: %w", string(output), err)
 	}
 
-	cmd = exec.Command("sudo", "launchctl", "load", "-w", "/Library/LaunchDaemons/io.ethpandaops.contributoor.plist")
+	cmd = exec.CommandContext(ctx, "sudo", "launchctl", "load", "-w", s.plistPath())
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to reload service: %s: %w", string(output), err)
 	}
@@ -264,26 +674,31 @@ func (s *systemdSidecar) reloadLaunchd() error {
 	return nil
 }
 
+// CheckInstalled returns an error if the service unit (or launchd plist) isn't installed.
+func (s *systemdSidecar) CheckInstalled(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	return s.checkDaemonExists(ctx)
+}
+
 // checkDaemonExists checks if the daemon exists.
-func (s *systemdSidecar) checkDaemonExists() error {
+func (s *systemdSidecar) checkDaemonExists(ctx context.Context) error {
+	// Neither check needs root, so probing for the service never prompts for a password.
 	if runtime.GOOS == ArchDarwin {
-		// Check if plist file exists
-		cmd := exec.Command("sudo", "test", "-f", "/Library/LaunchDaemons/io.ethpandaops.contributoor.plist")
-		if err := cmd.Run(); err != nil {
+		if _, err := os.Stat(s.plistPath()); err != nil {
 			return fmt.Errorf("service not installed")
 		}
 
 		return nil
 	}
 
-	cmd := exec.Command("sudo", "systemctl", "list-unit-files", "contributoor.service")
-
-	output, err := cmd.CombinedOutput()
+	output, err := s.systemctlQuery(ctx, "list-unit-files", s.unit()).CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to list service: %s: %w", string(output), err)
 	}
 
-	if !strings.Contains(string(output), "contributoor.service") {
+	if !strings.Contains(string(output), s.unit()) {
 		return fmt.Errorf("service not installed")
 	}
 
@@ -291,7 +706,7 @@ func (s *systemdSidecar) checkDaemonExists() error {
 }
 
 // checkBinaryExists checks if the binary exists and has the correct version.
-func (s *systemdSidecar) checkBinaryExists() error {
+func (s *systemdSidecar) checkBinaryExists(ctx context.Context) error {
 	// Create a binary sidecar to check version
 	bs, err := NewBinarySidecar(s.logger, s.sidecarCfg, s.installerCfg)
 	if err != nil {
@@ -300,7 +715,7 @@ func (s *systemdSidecar) checkBinaryExists() error {
 
 	// Check binary version
 	if impl, ok := bs.(*binarySidecar); ok {
-		if err := impl.checkBinaryVersion(); err != nil {
+		if err := impl.checkBinaryVersion(ctx); err != nil {
 			return fmt.Errorf("version check failed: %w", err)
 		}
 	}
@@ -309,55 +724,50 @@ func (s *systemdSidecar) checkBinaryExists() error {
 }
 
 // Status returns the current state of the service.
-func (s *systemdSidecar) Status() (string, error) {
+func (s *systemdSidecar) Status(ctx context.Context) (*Status, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
 	if runtime.GOOS == ArchDarwin {
-		if err := s.checkDaemonExists(); err != nil {
-			return "", wrapNotInstalledError(err, "launchd")
+		if err := s.checkDaemonExists(ctx); err != nil {
+			return nil, wrapNotInstalledError(err, "launchd")
 		}
 
 		// For macOS, check launchd status.
-		cmd := exec.Command("sudo", "launchctl", "list", "io.ethpandaops.contributoor")
+		cmd := exec.CommandContext(ctx, "sudo", "launchctl", "list", s.launchdLabel())
 
 		output, err := cmd.Output()
 		if err != nil {
 			//nolint:nilerr // We don't care about the error here.
-			return "inactive", nil
+			return &Status{State: StateStopped, Detail: "inactive"}, nil
 		}
 
-		// If service is running, output will contain a PID.
-		lines := strings.Split(string(output), "\n")
-		if len(lines) > 0 {
-			fields := strings.Fields(lines[0])
-			if len(fields) > 0 && fields[0] != "-" {
-				return "active", nil
-			}
-		}
-
-		return "inactive", nil
+		return parseLaunchdList(output), nil
 	}
 
-	if err := s.checkDaemonExists(); err != nil {
-		return "", wrapNotInstalledError(err, "systemd")
+	if err := s.checkDaemonExists(ctx); err != nil {
+		return nil, wrapNotInstalledError(err, "systemd")
 	}
 
 	// For Linux/systemd, get service state.
-	cmd := exec.Command("sudo", "systemctl", "show", "-p", "ActiveState", "--value", "contributoor.service")
-
-	output, err := cmd.Output()
+	output, err := s.systemctlQuery(ctx, "show", "-p", strings.Join(systemdShowProperties, ","), s.unit()).Output()
 	if err != nil {
-		return "", fmt.Errorf("failed to get service status: %w", err)
+		return nil, fmt.Errorf("failed to get service status: %w", err)
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	return parseSystemdShow(output, time.Now())
 }
 
 // Version returns the version of the currently running service.
-func (s *systemdSidecar) Version() (string, error) {
+func (s *systemdSidecar) Version(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
 	// Create a binary sidecar to check version since systemd/launchd uses the binary
 	bs, err := NewBinarySidecar(s.logger, s.sidecarCfg, s.installerCfg)
 	if err != nil {
 		return "", fmt.Errorf("failed to create binary sidecar: %w", err)
 	}
 
-	return bs.Version()
+	return bs.Version(ctx)
 }
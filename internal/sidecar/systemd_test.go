@@ -0,0 +1,128 @@
+package sidecar
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceTemplates(t *testing.T) {
+	params := &serviceParams{
+		Label:      "io.ethpandaops.contributoor-holesky",
+		Unit:       "contributoor-holesky.service",
+		User:       "eth",
+		Home:       "/home/eth",
+		Binary:     "/home/eth/.contributoor/instances/holesky/bin/sentry",
+		ConfigPath: "/home/eth/.contributoor/instances/holesky/config.yaml",
+		Dir:        "/home/eth/.contributoor/instances/holesky",
+		WantedBy:   "multi-user.target",
+	}
+
+	require.NoError(t, params.applySystemdConfig(&installer.NewConfig().Systemd))
+
+	t.Run("systemd unit", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, unitTemplate.Execute(&buf, params))
+
+		unit := buf.String()
+		assert.Contains(t, unit, "\nUser=eth\nGroup=eth\n")
+		assert.Contains(t, unit, "\nExecStart="+params.Binary+" --config "+params.ConfigPath+"\n")
+		assert.Contains(t, unit, "\nWorkingDirectory="+params.Dir+"\n")
+		assert.Contains(t, unit, "\nEnvironment=HOME=/home/eth\n")
+		assert.Contains(t, unit, "\nProtectSystem=full\n")
+		assert.Contains(t, unit, "\nWantedBy=multi-user.target\n")
+	})
+
+	t.Run("systemd user unit", func(t *testing.T) {
+		userParams := *params
+		userParams.UserScope = true
+		userParams.WantedBy = "default.target"
+
+		var buf bytes.Buffer
+		require.NoError(t, unitTemplate.Execute(&buf, &userParams))
+
+		// User units run as the user already, and can't use the hardening needing root.
+		unit := buf.String()
+		assert.Contains(t, unit, "\nType=simple\nExecStart="+params.Binary+" --config "+params.ConfigPath+"\n")
+		assert.NotContains(t, unit, "User=eth\n")
+		assert.Contains(t, unit, "\nNoNewPrivileges=true\n\n[Install]\n")
+		assert.NotContains(t, unit, "ProtectSystem")
+		assert.Contains(t, unit, "\nWantedBy=default.target\n")
+	})
+
+	t.Run("launchd plist", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, plistTemplate.Execute(&buf, params))
+
+		plist := buf.String()
+		assert.Contains(t, plist, "<string>io.ethpandaops.contributoor-holesky</string>")
+		assert.Contains(t, plist, "<string>"+params.Binary+"</string>")
+		assert.Contains(t, plist, "<string>"+params.Dir+"/logs/service.log</string>")
+		assert.Contains(t, plist, "<key>UserName</key>\n    <string>eth</string>")
+	})
+}
+
+func TestNewSystemdSidecar(t *testing.T) {
+	tests := []struct {
+		name          string
+		scope         string
+		expectedUser  bool
+		expectedError string
+	}{
+		{name: "default", scope: ""},
+		{name: "system", scope: installer.SystemdScopeSystem},
+		{name: "user", scope: installer.SystemdScopeUser, expectedUser: true},
+		{name: "invalid", scope: "global", expectedError: `invalid systemd scope "global"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expectedUser && runtime.GOOS == ArchDarwin {
+				t.Skip("macOS has no user units")
+			}
+
+			installerCfg := installer.NewConfig()
+			installerCfg.Systemd.Scope = tt.scope
+
+			runner, err := NewSystemdSidecar(logrus.New(), nil, installerCfg)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			s, _ := runner.(*systemdSidecar)
+			assert.Equal(t, tt.expectedUser, s.userScope)
+		})
+	}
+}
+
+func TestSystemdSidecar_UserScope(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/home/eth/.config")
+
+	var (
+		system = &systemdSidecar{name: "contributoor-holesky"}
+		user   = &systemdSidecar{name: "contributoor-holesky", userScope: true}
+	)
+
+	path, err := system.unitPath()
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/systemd/system/contributoor-holesky.service", path)
+
+	path, err = user.unitPath()
+	require.NoError(t, err)
+	assert.Equal(t, "/home/eth/.config/systemd/user/contributoor-holesky.service", path)
+
+	assert.Equal(t, []string{"sudo", "systemctl", "start", "contributoor-holesky.service"}, system.systemctl(context.Background(), "start", system.unit()).Args)
+	assert.Equal(t, []string{"systemctl", "--user", "start", "contributoor-holesky.service"}, user.systemctl(context.Background(), "start", user.unit()).Args)
+	assert.Equal(t, []string{"systemctl", "is-active", "contributoor-holesky.service"}, system.systemctlQuery(context.Background(), "is-active", system.unit()).Args)
+	assert.Equal(t, []string{"systemctl", "--user", "is-active", "contributoor-holesky.service"}, user.systemctlQuery(context.Background(), "is-active", user.unit()).Args)
+}
@@ -0,0 +1,383 @@
+package sidecar
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/mitchellh/go-homedir"
+)
+
+// ErrUnitNotInstalled is returned when the systemd unit (or launchd plist) to compare
+// against isn't installed.
+var ErrUnitNotInstalled = errors.New("service unit not installed")
+
+var (
+	// systemdRestartPolicies are the values systemd accepts for Restart=.
+	systemdRestartPolicies = []string{"no", "always", "on-success", "on-failure", "on-abnormal", "on-abort", "on-watchdog"}
+	// systemdProtectSystem are the values systemd accepts for ProtectSystem=.
+	systemdProtectSystem = []string{"no", "yes", "full", "strict"}
+	// systemdProtectHome are the values systemd accepts for ProtectHome=.
+	systemdProtectHome = []string{"no", "yes", "read-only", "tmpfs"}
+	// systemdMemoryPattern matches a memory limit systemd accepts, ie: a size with an
+	// optional K, M, G or T suffix, a percentage of the host's memory, or infinity.
+	systemdMemoryPattern = regexp.MustCompile(`^(\d+(\.\d+)?[KMGT]?|\d+(\.\d+)?%|infinity)$`)
+	// systemdCPUQuotaPattern matches a CPU quota systemd accepts, eg: 50% or 200%.
+	systemdCPUQuotaPattern = regexp.MustCompile(`^\d+(\.\d+)?%$`)
+	// envNamePattern matches the environment variable names systemd accepts.
+	envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// serviceParams holds the values the systemd unit and launchd plist are rendered with.
+type serviceParams struct {
+	Label      string
+	Unit       string
+	User       string
+	Home       string
+	Binary     string
+	ConfigPath string
+	Dir        string
+	// UserScope renders a user unit, which runs as the user whose service manager
+	// runs it, and can't use the hardening directives needing root.
+	UserScope bool
+	// WantedBy is the target the unit is started with.
+	WantedBy string
+	// Restart and RestartSec are the unit's restart policy. RestartSec is omitted if
+	// empty.
+	Restart    string
+	RestartSec string
+	// MemoryMax and CPUQuota are the unit's resource limits, omitted if empty.
+	MemoryMax string
+	CPUQuota  string
+	// Environment is the extra environment assignments, quoted for the unit.
+	Environment []string
+	// The unit's hardening directives.
+	NoNewPrivileges bool
+	ProtectSystem   string
+	ProtectHome     string
+	PrivateTmp      bool
+}
+
+// unitTemplate renders the same unit install.sh used to write, with the default
+// systemd settings in installer.yaml.
+var unitTemplate = template.Must(template.New("unit").Funcs(template.FuncMap{
+	"execArg": systemdExecArg,
+}).Parse(`# Generated by contributoor from config.yaml and installer.yaml, do not edit.
+# Changes are overwritten by 'contributoor systemd reinstall-unit'. Put customisations
+# in a drop-in instead, eg: with 'systemctl{{ if .UserScope }} --user{{ end }} edit {{ .Unit }}'.
+[Unit]
+Description=Contributoor Service
+After=network-online.target
+Wants=network-online.target
+StartLimitIntervalSec=0
+
+[Service]
+Type=simple
+{{ if not .UserScope -}}
+User={{ .User }}
+Group={{ .User }}
+{{ end -}}
+ExecStart={{ execArg .Binary }} --config {{ execArg .ConfigPath }}
+WorkingDirectory={{ .Dir }}
+Restart={{ .Restart }}
+{{- if .RestartSec }}
+RestartSec={{ .RestartSec }}
+{{- end }}
+{{- if .MemoryMax }}
+MemoryMax={{ .MemoryMax }}
+{{- end }}
+{{- if .CPUQuota }}
+CPUQuota={{ .CPUQuota }}
+{{- end }}
+
+# Environment setup
+Environment=HOME={{ .Home }}
+Environment=USER={{ .User }}
+Environment=PATH=/usr/local/bin:/usr/bin:/bin
+{{- range .Environment }}
+Environment={{ . }}
+{{- end }}
+
+# Hardening
+NoNewPrivileges={{ .NoNewPrivileges }}
+{{ if not .UserScope -}}
+ProtectSystem={{ .ProtectSystem }}
+ProtectHome={{ .ProtectHome }}
+PrivateTmp={{ .PrivateTmp }}
+{{ end }}
+[Install]
+WantedBy={{ .WantedBy }}
+`))
+
+// plistTemplate matches the plist written by install.sh.
+var plistTemplate = template.Must(template.New("plist").Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+    <key>Label</key>
+    <string>{{ .Label }}</string>
+    <key>ProgramArguments</key>
+    <array>
+        <string>{{ .Binary }}</string>
+        <string>--config</string>
+        <string>{{ .ConfigPath }}</string>
+    </array>
+    <key>RunAtLoad</key>
+    <true/>
+    <key>KeepAlive</key>
+    <true/>
+    <key>WorkingDirectory</key>
+    <string>{{ .Dir }}</string>
+    <key>StandardOutPath</key>
+    <string>{{ .Dir }}/logs/debug.log</string>
+    <key>StandardErrorPath</key>
+    <string>{{ .Dir }}/logs/service.log</string>
+    <key>EnvironmentVariables</key>
+    <dict>
+        <key>PATH</key>
+        <string>/usr/local/bin:/usr/bin:/bin</string>
+    </dict>
+    <key>UserName</key>
+    <string>{{ .User }}</string>
+</dict>
+</plist>
+`))
+
+// serviceParams returns the values to render the service definition with. The
+// service runs as the current user, same as install.sh.
+func (s *systemdSidecar) serviceParams() (*serviceParams, error) {
+	current, err := user.Current()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	dir, err := homedir.Expand(s.sidecarCfg.Get().ContributoorDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand config path: %w", err)
+	}
+
+	configPath, err := homedir.Expand(s.sidecarCfg.GetConfigPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand config path: %w", err)
+	}
+
+	params := &serviceParams{
+		Label:      s.launchdLabel(),
+		Unit:       s.unit(),
+		User:       current.Username,
+		Home:       current.HomeDir,
+		Binary:     filepath.Join(dir, "bin", "sentry"),
+		ConfigPath: configPath,
+		Dir:        dir,
+		UserScope:  s.userScope,
+		WantedBy:   s.wantedBy(),
+	}
+
+	// The systemd settings don't apply to the launchd plist.
+	if runtime.GOOS != ArchDarwin {
+		if err := params.applySystemdConfig(&s.installerCfg.Systemd); err != nil {
+			return nil, err
+		}
+	}
+
+	return params, nil
+}
+
+// applySystemdConfig sets the unit's restart policy, resource limits, environment
+// and hardening from the systemd settings in installer.yaml.
+func (p *serviceParams) applySystemdConfig(settings *installer.SystemdConfig) error {
+	if err := validateSystemdConfig(settings); err != nil {
+		return err
+	}
+
+	p.Restart = settings.Restart
+	p.NoNewPrivileges = enabled(settings.Hardening.NoNewPrivileges)
+	p.ProtectSystem = settings.Hardening.ProtectSystem
+	p.ProtectHome = settings.Hardening.ProtectHome
+	p.PrivateTmp = enabled(settings.Hardening.PrivateTmp)
+
+	// "0" means no limit, rather than systemd's zero.
+	if settings.MemoryMax != "0" {
+		p.MemoryMax = settings.MemoryMax
+	}
+
+	if settings.CPUQuota != "0" {
+		p.CPUQuota = settings.CPUQuota
+	}
+
+	if settings.RestartSec != nil {
+		p.RestartSec = systemdTimeSpan(*settings.RestartSec)
+	}
+
+	// Sorted, so the unit only changes when the settings do.
+	for _, key := range slices.Sorted(maps.Keys(settings.Environment)) {
+		p.Environment = append(p.Environment, systemdEnvironment(key, settings.Environment[key]))
+	}
+
+	return nil
+}
+
+// validateSystemdConfig checks the systemd settings before they're rendered, so
+// mistakes are reported against installer.yaml rather than by systemd.
+func validateSystemdConfig(settings *installer.SystemdConfig) error {
+	if !slices.Contains(systemdRestartPolicies, settings.Restart) {
+		return fmt.Errorf(
+			"invalid systemd restart %q, must be one of: %s",
+			settings.Restart,
+			strings.Join(systemdRestartPolicies, ", "),
+		)
+	}
+
+	if settings.RestartSec != nil && *settings.RestartSec < 0 {
+		return fmt.Errorf("invalid systemd restartSec %s, must not be negative", settings.RestartSec)
+	}
+
+	if settings.MemoryMax != "" && !systemdMemoryPattern.MatchString(settings.MemoryMax) {
+		return fmt.Errorf("invalid systemd memoryMax %q, must be a size like 512M or 1G, or a percentage", settings.MemoryMax)
+	}
+
+	if settings.CPUQuota != "" && settings.CPUQuota != "0" && !systemdCPUQuotaPattern.MatchString(settings.CPUQuota) {
+		return fmt.Errorf("invalid systemd cpuQuota %q, must be a percentage of one CPU like 50%% or 200%%", settings.CPUQuota)
+	}
+
+	for key, value := range settings.Environment {
+		if !envNamePattern.MatchString(key) {
+			return fmt.Errorf("invalid systemd environment variable name %q", key)
+		}
+
+		if strings.ContainsAny(value, "\n\r") {
+			return fmt.Errorf("invalid systemd environment variable %s, values can't span lines", key)
+		}
+	}
+
+	hardening := settings.Hardening
+
+	if !slices.Contains(systemdProtectSystem, hardening.ProtectSystem) {
+		return fmt.Errorf(
+			"invalid systemd protectSystem %q, must be one of: %s",
+			hardening.ProtectSystem,
+			strings.Join(systemdProtectSystem, ", "),
+		)
+	}
+
+	if !slices.Contains(systemdProtectHome, hardening.ProtectHome) {
+		return fmt.Errorf(
+			"invalid systemd protectHome %q, must be one of: %s",
+			hardening.ProtectHome,
+			strings.Join(systemdProtectHome, ", "),
+		)
+	}
+
+	return nil
+}
+
+// enabled returns the value of an optional setting, false if it isn't set.
+func enabled(value *bool) bool {
+	return value != nil && *value
+}
+
+// systemdTimeSpan returns d as a systemd time span, in seconds when it's whole.
+func systemdTimeSpan(d time.Duration) string {
+	if d%time.Second == 0 {
+		return strconv.FormatInt(int64(d/time.Second), 10)
+	}
+
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
+
+// systemdExecArg quotes an argument of ExecStart= if needed, and escapes the
+// specifiers and variables systemd would otherwise expand.
+func systemdExecArg(arg string) string {
+	arg = strings.NewReplacer("%", "%%", "$", "$$").Replace(arg)
+
+	return systemdQuote(arg)
+}
+
+// systemdEnvironment returns the value of an Environment= directive setting key to
+// value, quoted if needed.
+func systemdEnvironment(key, value string) string {
+	return systemdQuote(strings.ReplaceAll(key+"="+value, "%", "%%"))
+}
+
+// systemdQuote double quotes value if it contains whitespace, quotes or backslashes.
+func systemdQuote(value string) string {
+	if !strings.ContainsAny(value, " \t\"'\\") {
+		return value
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// unitLines returns the lines of a unit which matter to systemd, ie: without
+// comments, blank lines or surrounding whitespace.
+func unitLines(data []byte) []string {
+	var lines []string
+
+	for line := range strings.SplitSeq(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// diffUnit returns the lines which differ between the installed and rendered unit,
+// prefixed by "-" if they're only in the installed one and "+" if only in the
+// rendered one. Comments and formatting are ignored. It's nil if they match.
+func diffUnit(installed, rendered []byte) []string {
+	a, b := unitLines(installed), unitLines(rendered)
+	if slices.Equal(a, b) {
+		return nil
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var (
+		diff []string
+		i, j int
+	)
+
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "- "+a[i])
+			i++
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
+		}
+	}
+
+	return diff
+}
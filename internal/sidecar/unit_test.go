@@ -0,0 +1,323 @@
+package sidecar
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/contributoor-installer/internal/installer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// installShUnit is the unit install.sh wrote before it left this to contributoor.
+const installShUnit = `[Unit]
+Description=Contributoor Service
+After=network-online.target
+Wants=network-online.target
+StartLimitIntervalSec=0
+
+[Service]
+Type=simple
+User=eth
+Group=eth
+ExecStart=/home/eth/.contributoor/bin/sentry --config /home/eth/.contributoor/config.yaml
+WorkingDirectory=/home/eth/.contributoor
+Restart=always
+RestartSec=5
+
+# Environment setup
+Environment=HOME=/home/eth
+Environment=USER=eth
+Environment=PATH=/usr/local/bin:/usr/bin:/bin
+
+# Hardening
+NoNewPrivileges=true
+ProtectSystem=full
+ProtectHome=read-only
+PrivateTmp=true
+
+[Install]
+WantedBy=multi-user.target
+`
+
+func TestRenderUnit(t *testing.T) {
+	newParams := func() *serviceParams {
+		return &serviceParams{
+			Unit:       "contributoor.service",
+			User:       "eth",
+			Home:       "/home/eth",
+			Binary:     "/home/eth/.contributoor/bin/sentry",
+			ConfigPath: "/home/eth/.contributoor/config.yaml",
+			Dir:        "/home/eth/.contributoor",
+			WantedBy:   "multi-user.target",
+		}
+	}
+
+	render := func(t *testing.T, params *serviceParams) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		require.NoError(t, unitTemplate.Execute(&buf, params))
+
+		return buf.String()
+	}
+
+	t.Run("defaults match install.sh", func(t *testing.T) {
+		params := newParams()
+		require.NoError(t, params.applySystemdConfig(&installer.NewConfig().Systemd))
+
+		unit := render(t, params)
+
+		assert.Nil(t, diffUnit([]byte(installShUnit), []byte(unit)))
+		assert.True(t, strings.HasPrefix(unit, "# Generated by contributoor"))
+		assert.Contains(t, unit, "'systemctl edit contributoor.service'")
+	})
+
+	t.Run("all settings", func(t *testing.T) {
+		var (
+			restartSec = 1500 * time.Millisecond
+			disabled   = false
+		)
+
+		settings := installer.NewConfig().Systemd
+		settings.Restart = "on-failure"
+		settings.RestartSec = &restartSec
+		settings.MemoryMax = "512M"
+		settings.CPUQuota = "50%"
+		settings.Environment = map[string]string{
+			"GOMAXPROCS": "2",
+			"GREETING":   `say "hi" 100%`,
+		}
+		settings.Hardening = installer.SystemdHardening{
+			NoNewPrivileges: &disabled,
+			ProtectSystem:   "strict",
+			ProtectHome:     "no",
+			PrivateTmp:      &disabled,
+		}
+
+		params := newParams()
+		params.Binary = "/srv/my contributoor/bin/sentry"
+		require.NoError(t, params.applySystemdConfig(&settings))
+
+		unit := render(t, params)
+
+		assert.Contains(t, unit, "\nExecStart=\"/srv/my contributoor/bin/sentry\" --config /home/eth/.contributoor/config.yaml\n")
+		assert.Contains(t, unit, "\nRestart=on-failure\nRestartSec=1500ms\nMemoryMax=512M\nCPUQuota=50%\n")
+		assert.Contains(t, unit, "\nEnvironment=PATH=/usr/local/bin:/usr/bin:/bin\n"+
+			"Environment=GOMAXPROCS=2\n"+
+			`Environment="GREETING=say \"hi\" 100%%"`+"\n")
+		assert.Contains(t, unit, "\nNoNewPrivileges=false\nProtectSystem=strict\nProtectHome=no\nPrivateTmp=false\n")
+	})
+
+	t.Run("no limits", func(t *testing.T) {
+		params := newParams()
+		require.NoError(t, params.applySystemdConfig(&installer.NewConfig().Systemd))
+
+		unit := render(t, params)
+		assert.NotContains(t, unit, "MemoryMax")
+		assert.NotContains(t, unit, "CPUQuota")
+	})
+}
+
+func TestValidateSystemdConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(settings *installer.SystemdConfig)
+		expectedError string
+	}{
+		{
+			name:   "defaults",
+			modify: func(*installer.SystemdConfig) {},
+		},
+		{
+			name: "valid limits",
+			modify: func(settings *installer.SystemdConfig) {
+				settings.MemoryMax = "1.5G"
+				settings.CPUQuota = "200%"
+			},
+		},
+		{
+			name: "memory as a percentage",
+			modify: func(settings *installer.SystemdConfig) {
+				settings.MemoryMax = "25%"
+			},
+		},
+		{
+			name: "invalid restart",
+			modify: func(settings *installer.SystemdConfig) {
+				settings.Restart = "unless-stopped"
+			},
+			expectedError: `invalid systemd restart "unless-stopped"`,
+		},
+		{
+			name: "negative restartSec",
+			modify: func(settings *installer.SystemdConfig) {
+				negative := -time.Second
+				settings.RestartSec = &negative
+			},
+			expectedError: "invalid systemd restartSec -1s",
+		},
+		{
+			name: "invalid memoryMax",
+			modify: func(settings *installer.SystemdConfig) {
+				settings.MemoryMax = "1GB"
+			},
+			expectedError: `invalid systemd memoryMax "1GB"`,
+		},
+		{
+			name: "invalid cpuQuota",
+			modify: func(settings *installer.SystemdConfig) {
+				settings.CPUQuota = "0.5"
+			},
+			expectedError: `invalid systemd cpuQuota "0.5"`,
+		},
+		{
+			name: "invalid environment name",
+			modify: func(settings *installer.SystemdConfig) {
+				settings.Environment = map[string]string{"MY-VAR": "1"}
+			},
+			expectedError: `invalid systemd environment variable name "MY-VAR"`,
+		},
+		{
+			name: "multi-line environment value",
+			modify: func(settings *installer.SystemdConfig) {
+				settings.Environment = map[string]string{"MY_VAR": "a\nb"}
+			},
+			expectedError: "invalid systemd environment variable MY_VAR",
+		},
+		{
+			name: "invalid protectSystem",
+			modify: func(settings *installer.SystemdConfig) {
+				settings.Hardening.ProtectSystem = "read-only"
+			},
+			expectedError: `invalid systemd protectSystem "read-only"`,
+		},
+		{
+			name: "invalid protectHome",
+			modify: func(settings *installer.SystemdConfig) {
+				settings.Hardening.ProtectHome = "full"
+			},
+			expectedError: `invalid systemd protectHome "full"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := installer.NewConfig().Systemd
+			tt.modify(&settings)
+
+			err := validateSystemdConfig(&settings)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestDiffUnit(t *testing.T) {
+	rendered := []byte("# Generated\n[Service]\nRestart=always\nRestartSec=5\n\n[Install]\nWantedBy=multi-user.target\n")
+
+	// Comments, blank lines and indentation don't count as drift.
+	assert.Nil(t, diffUnit([]byte("[Service]\n  Restart=always\n; note\nRestartSec=5\n[Install]\nWantedBy=multi-user.target"), rendered))
+
+	assert.Equal(t, []string{
+		"- Restart=on-failure",
+		"+ Restart=always",
+		"- MemoryMax=1G",
+	}, diffUnit([]byte("[Service]\nRestart=on-failure\nRestartSec=5\nMemoryMax=1G\n[Install]\nWantedBy=multi-user.target\n"), rendered))
+
+	assert.Equal(t, []string{
+		"+ [Install]",
+		"+ WantedBy=multi-user.target",
+	}, diffUnit([]byte("[Service]\nRestart=always\nRestartSec=5\n"), rendered))
+}
+
+func TestSystemdQuoting(t *testing.T) {
+	assert.Equal(t, "/home/eth/bin/sentry", systemdExecArg("/home/eth/bin/sentry"))
+	assert.Equal(t, `"/home/my eth/bin/sentry"`, systemdExecArg("/home/my eth/bin/sentry"))
+	assert.Equal(t, "/srv/100%%/$$HOME", systemdExecArg("/srv/100%/$HOME"))
+	assert.Equal(t, "KEY=value", systemdEnvironment("KEY", "value"))
+	assert.Equal(t, `"KEY=a \\ b"`, systemdEnvironment("KEY", `a \ b`))
+	assert.Equal(t, "5", systemdTimeSpan(5*time.Second))
+	assert.Equal(t, "250ms", systemdTimeSpan(250*time.Millisecond))
+}
+
+func TestSystemdSidecar_InstallUnit(t *testing.T) {
+	if runtime.GOOS == ArchDarwin {
+		t.Skip("macOS has no user units")
+	}
+
+	// A fake systemctl records its arguments.
+	var (
+		dir     = t.TempDir()
+		argsLog = filepath.Join(dir, "args")
+		script  = "#!/bin/sh\necho \"$@\" >> " + argsLog + "\n"
+	)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "systemctl"), []byte(script), 0755)) //nolint:gosec // test script.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("contributoorDirectory: "+dir+"\n"), 0600))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+
+	sidecarCfg, err := NewConfigService(logrus.New(), dir)
+	require.NoError(t, err)
+
+	installerCfg := installer.NewConfig()
+	installerCfg.Systemd.Scope = installer.SystemdScopeUser
+	installerCfg.Systemd.MemoryMax = "512M"
+
+	runner, err := NewSystemdSidecar(logrus.New(), sidecarCfg, installerCfg)
+	require.NoError(t, err)
+
+	// Lingering isn't enabled, so the guidance for enabling it is printed.
+	var guided []string
+
+	originalLinger, originalGuidance := lingerEnabled, printLingerGuidance
+	t.Cleanup(func() {
+		lingerEnabled, printLingerGuidance = originalLinger, originalGuidance
+	})
+
+	lingerEnabled = func(string) bool { return false }
+	printLingerGuidance = func(username string) { guided = append(guided, username) }
+
+	_, err = runner.UnitDrift()
+	require.ErrorIs(t, err, ErrUnitNotInstalled)
+
+	require.NoError(t, runner.InstallUnit(context.Background()))
+
+	current, err := user.Current()
+	require.NoError(t, err)
+	assert.Equal(t, []string{current.Username}, guided)
+
+	unit, err := os.ReadFile(filepath.Join(dir, "xdg", "systemd", "user", "contributoor.service"))
+	require.NoError(t, err)
+	assert.Contains(t, string(unit), "\nExecStart="+filepath.Join(dir, "bin", "sentry")+" --config "+filepath.Join(dir, "config.yaml")+"\n")
+	assert.Contains(t, string(unit), "\nMemoryMax=512M\n")
+
+	args, err := os.ReadFile(argsLog)
+	require.NoError(t, err)
+	assert.Equal(t, "--user daemon-reload\n--user enable contributoor.service\n", string(args))
+
+	drift, err := runner.UnitDrift()
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+
+	// Changing installer.yaml leaves the installed unit out of date.
+	installerCfg.Systemd.MemoryMax = "1G"
+
+	drift, err = runner.UnitDrift()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"- MemoryMax=512M", "+ MemoryMax=1G"}, drift)
+}
@@ -1,6 +1,7 @@
 package sidecar
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ethpandaops/contributoor-installer/internal/service"
@@ -10,11 +11,12 @@ import (
 // - For "latest" tag, it compares the actual running version with latest available.
 // - For specific versions, it compares the config version with latest available.
 func CheckVersion(
+	ctx context.Context,
 	runner SidecarRunner,
 	github service.GitHubService,
 	configVersion string,
 ) (currentVersion, latestVersion string, needsUpdate bool, err error) {
-	latestVersion, err = github.GetLatestVersion()
+	latestVersion, err = github.GetLatestVersion(ctx)
 	if err != nil {
 		err = fmt.Errorf("failed to get latest version: %w", err)
 
@@ -23,7 +25,7 @@ func CheckVersion(
 
 	if configVersion == "latest" {
 		// For "latest" tag, compare the actual running version with latest available.
-		currentVersion, err = runner.Version()
+		currentVersion, err = runner.Version(ctx)
 		if err != nil {
 			err = fmt.Errorf("failed to get running version: %w", err)
 
@@ -1,10 +1,12 @@
-package sidecar
+package sidecar_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	servicemock "github.com/ethpandaops/contributoor-installer/internal/service/mock"
+	"github.com/ethpandaops/contributoor-installer/internal/sidecar"
 	"github.com/ethpandaops/contributoor-installer/internal/sidecar/mock"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -27,8 +29,8 @@ func TestCheckVersion(t *testing.T) {
 			name:          "latest tag - up to date",
 			configVersion: "latest",
 			setupMocks: func(r *mock.MockDockerSidecar, g *servicemock.MockGitHubService) {
-				g.EXPECT().GetLatestVersion().Return("1.0.0", nil)
-				r.EXPECT().Version().Return("1.0.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("1.0.0", nil)
+				r.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
 			},
 			expectedCurrent:     "1.0.0",
 			expectedLatest:      "1.0.0",
@@ -38,8 +40,8 @@ func TestCheckVersion(t *testing.T) {
 			name:          "latest tag - needs update",
 			configVersion: "latest",
 			setupMocks: func(r *mock.MockDockerSidecar, g *servicemock.MockGitHubService) {
-				g.EXPECT().GetLatestVersion().Return("2.0.0", nil)
-				r.EXPECT().Version().Return("1.0.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("2.0.0", nil)
+				r.EXPECT().Version(gomock.Any()).Return("1.0.0", nil)
 			},
 			expectedCurrent:     "1.0.0",
 			expectedLatest:      "2.0.0",
@@ -49,7 +51,7 @@ func TestCheckVersion(t *testing.T) {
 			name:          "specific version - up to date",
 			configVersion: "1.0.0",
 			setupMocks: func(r *mock.MockDockerSidecar, g *servicemock.MockGitHubService) {
-				g.EXPECT().GetLatestVersion().Return("1.0.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("1.0.0", nil)
 			},
 			expectedCurrent:     "1.0.0",
 			expectedLatest:      "1.0.0",
@@ -59,7 +61,7 @@ func TestCheckVersion(t *testing.T) {
 			name:          "specific version - needs update",
 			configVersion: "1.0.0",
 			setupMocks: func(r *mock.MockDockerSidecar, g *servicemock.MockGitHubService) {
-				g.EXPECT().GetLatestVersion().Return("2.0.0", nil)
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("2.0.0", nil)
 			},
 			expectedCurrent:     "1.0.0",
 			expectedLatest:      "2.0.0",
@@ -69,7 +71,7 @@ func TestCheckVersion(t *testing.T) {
 			name:          "github error",
 			configVersion: "latest",
 			setupMocks: func(r *mock.MockDockerSidecar, g *servicemock.MockGitHubService) {
-				g.EXPECT().GetLatestVersion().Return("", errors.New("github error"))
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("", errors.New("github error"))
 			},
 			expectedError: "failed to get latest version",
 		},
@@ -77,8 +79,8 @@ func TestCheckVersion(t *testing.T) {
 			name:          "version error with latest tag",
 			configVersion: "latest",
 			setupMocks: func(r *mock.MockDockerSidecar, g *servicemock.MockGitHubService) {
-				g.EXPECT().GetLatestVersion().Return("1.0.0", nil)
-				r.EXPECT().Version().Return("", errors.New("version error"))
+				g.EXPECT().GetLatestVersion(gomock.Any()).Return("1.0.0", nil)
+				r.EXPECT().Version(gomock.Any()).Return("", errors.New("version error"))
 			},
 			expectedError: "failed to get running version",
 		},
@@ -91,7 +93,7 @@ func TestCheckVersion(t *testing.T) {
 
 			tt.setupMocks(mockRunner, mockGitHub)
 
-			current, latest, needsUpdate, err := CheckVersion(mockRunner, mockGitHub, tt.configVersion)
+			current, latest, needsUpdate, err := sidecar.CheckVersion(context.Background(), mockRunner, mockGitHub, tt.configVersion)
 
 			if tt.expectedError != "" {
 				assert.ErrorContains(t, err, tt.expectedError)
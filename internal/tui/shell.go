@@ -18,6 +18,7 @@ const (
 	TerminalColorYellow    = "\033[33m"
 	TerminalColorGreen     = "\033[32m"
 	TerminalColorLightBlue = "\033[36m"
+	TerminalColorGray      = "\033[90m"
 	TerminalClearLine      = "\033[2K"
 )
 
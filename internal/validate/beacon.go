@@ -32,10 +32,7 @@ func ValidateBeaconNodeAddress(addresses string) error {
 		address = strings.TrimSpace(address)
 
 		// Skip health check if using Docker network hostname (non-localhost).
-		host := strings.TrimPrefix(strings.TrimPrefix(address, "http://"), "https://")
-		host = strings.Split(host, ":")[0]
-
-		if !strings.HasPrefix(host, "127.0.0.1") && !strings.HasPrefix(host, "localhost") {
+		if !IsLocalhostAddress(address) {
 			return nil
 		}
 
@@ -68,3 +65,12 @@ func ValidateBeaconNodeAddress(addresses string) error {
 
 	return nil
 }
+
+// IsLocalhostAddress checks if the address points to localhost.
+// Non-localhost addresses (e.g., Docker network hostnames) are not reachable from the host.
+func IsLocalhostAddress(address string) bool {
+	host := strings.TrimPrefix(strings.TrimPrefix(address, "http://"), "https://")
+	host = strings.Split(host, ":")[0]
+
+	return strings.HasPrefix(host, "127.0.0.1") || strings.HasPrefix(host, "localhost")
+}
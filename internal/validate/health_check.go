@@ -0,0 +1,6 @@
+package validate
+
+// ValidateHealthCheckAddress validates the health check address.
+func ValidateHealthCheckAddress(address string) error {
+	return validateListenAddress("health check", address)
+}
@@ -0,0 +1,48 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateHealthCheckAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		wantErr bool
+	}{
+		{
+			name:    "empty address",
+			address: "",
+			wantErr: false,
+		},
+		{
+			name:    "just port",
+			address: "9191",
+			wantErr: false,
+		},
+		{
+			name:    "ip with port",
+			address: "127.0.0.1:9191",
+			wantErr: false,
+		},
+		{
+			name:    "missing port",
+			address: "127.0.0.1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHealthCheckAddress(tt.address)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHealthCheckAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil && !strings.Contains(err.Error(), "health check address") {
+				t.Errorf("ValidateHealthCheckAddress() error = %v, want error mentioning health check address", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// instanceNameRegex matches names which are valid as part of a container name,
+// compose project, systemd unit and launchd label.
+var instanceNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// ValidateInstanceName validates the name of a contributoor instance. "default" is
+// reserved for the unnamed instance.
+func ValidateInstanceName(name string) error {
+	if name == "default" {
+		return fmt.Errorf("instance name %q is reserved, omit --instance to use the default instance", name)
+	}
+
+	if !instanceNameRegex.MatchString(name) {
+		return fmt.Errorf(
+			"invalid instance name %q, must be 1-32 lowercase letters, digits or dashes, starting with a letter or digit",
+			name,
+		)
+	}
+
+	return nil
+}
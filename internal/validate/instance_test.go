@@ -0,0 +1,64 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateInstanceName(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance string
+		wantErr  bool
+	}{
+		{
+			name:     "simple",
+			instance: "holesky",
+			wantErr:  false,
+		},
+		{
+			name:     "with digits and dashes",
+			instance: "mainnet-2",
+			wantErr:  false,
+		},
+		{
+			name:     "empty",
+			instance: "",
+			wantErr:  true,
+		},
+		{
+			name:     "reserved",
+			instance: "default",
+			wantErr:  true,
+		},
+		{
+			name:     "uppercase",
+			instance: "Holesky",
+			wantErr:  true,
+		},
+		{
+			name:     "leading dash",
+			instance: "-holesky",
+			wantErr:  true,
+		},
+		{
+			name:     "path traversal",
+			instance: "../holesky",
+			wantErr:  true,
+		},
+		{
+			name:     "too long",
+			instance: strings.Repeat("a", 33),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateInstanceName(tt.instance)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateInstanceName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
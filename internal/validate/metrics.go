@@ -8,7 +8,12 @@ import (
 
 // ValidateMetricsAddress validates the metrics address.
 func ValidateMetricsAddress(address string) error {
-	// Empty address is valid (disables metrics).
+	return validateListenAddress("metrics", address)
+}
+
+// validateListenAddress validates an optional host:port address the sidecar listens on.
+func validateListenAddress(kind, address string) error {
+	// Empty address is valid (disables the listener).
 	if address == "" {
 		return nil
 	}
@@ -25,11 +30,11 @@ func ValidateMetricsAddress(address string) error {
 
 	u, err := url.Parse(address)
 	if err != nil {
-		return fmt.Errorf("invalid metrics address: %v", err)
+		return fmt.Errorf("invalid %s address: %v", kind, err)
 	}
 
 	if u.Port() == "" {
-		return fmt.Errorf("metrics address must include a port")
+		return fmt.Errorf("%s address must include a port", kind)
 	}
 
 	return nil